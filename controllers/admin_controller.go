@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"inventory-api/models"
+	"inventory-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+type AdminController struct {
+	db     *gorm.DB
+	config *utils.Config
+}
+
+func NewAdminController(db *gorm.DB, config *utils.Config) *AdminController {
+	return &AdminController{
+		db:     db,
+		config: config,
+	}
+}
+
+// GetMigrations handles GET /admin/migrations
+// @Summary List applied database migrations
+// @Description List the migration files recorded as applied, oldest first, with the time each was applied
+// @Tags admin
+// @Produce json
+// @Success 200 {array} models.SchemaMigration
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/migrations [get]
+func (h *AdminController) GetMigrations(c *gin.Context) {
+	migrations, err := utils.AppliedMigrations(h.db)
+	if err != nil {
+		utils.Error.Printf("Failed to list migrations: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to list migrations",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, migrations)
+}
+
+// GetConfig handles GET /admin/config
+// @Summary Show the effective configuration
+// @Description Show the configuration the server loaded from the config file and environment, keyed as in config.yaml. The database password, replica DSN, API key, Redis URL, cursor secret and alert webhook URL are replaced by [REDACTED] when set.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/config [get]
+func (h *AdminController) GetConfig(c *gin.Context) {
+	// Going through YAML keys the response like the config file, with
+	// durations such as "30s" rather than nanoseconds
+	var config map[string]interface{}
+	raw, err := yaml.Marshal(h.config.Redacted())
+	if err == nil {
+		err = yaml.Unmarshal(raw, &config)
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to encode configuration: %w", err)
+		utils.Error.Printf("Failed to show configuration: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to show configuration",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, config)
+}
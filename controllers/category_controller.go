@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"inventory-api/models"
+	"inventory-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CategoryController struct {
+	itemService *utils.ItemService
+}
+
+func NewCategoryControllerWithService(service *utils.ItemService) *CategoryController {
+	return &CategoryController{
+		itemService: service,
+	}
+}
+
+// CreateCategory handles POST /categories
+// @Summary Create a category
+// @Description Create a category, optionally nested under a parent category. Categories nest at most 8 levels deep.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param category body models.CreateCategoryRequest true "Category data"
+// @Success 201 {object} models.Category
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /categories [post]
+func (h *CategoryController) CreateCategory(c *gin.Context) {
+	var req models.CreateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error.Printf("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	category, err := h.itemService.CreateCategory(&req)
+	if errors.Is(err, utils.ErrCategoryNotFound) || errors.Is(err, utils.ErrCategoryTooDeep) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid parent category",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+	if err != nil {
+		utils.Error.Printf("Failed to create category: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to create category",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	utils.Info.Printf("Created category: %s", category.ID)
+	c.JSON(http.StatusCreated, category)
+}
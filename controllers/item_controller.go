@@ -1,12 +1,23 @@
 package controllers
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
 
 	"inventory-api/models"
 	"inventory-api/utils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 )
 
@@ -20,10 +31,22 @@ func NewItemController() *ItemController {
 	}
 }
 
+func NewItemControllerWithService(service *utils.ItemService) *ItemController {
+	return &ItemController{
+		itemService: service,
+	}
+}
+
 func (c *ItemController) SetItemService(service *utils.ItemService) {
 	c.itemService = service
 }
 
+// service returns the item service to handle c with, bound to the request's
+// transaction when it runs behind utils.TransactionMiddleware
+func (h *ItemController) service(c *gin.Context) *utils.ItemService {
+	return h.itemService.WithRequestTx(c)
+}
+
 // CreateItem handles POST /inventory
 // @Summary Create a new item
 // @Description Create a new inventory item
@@ -31,7 +54,9 @@ func (c *ItemController) SetItemService(service *utils.ItemService) {
 // @Accept json
 // @Produce json
 // @Param item body models.CreateItemRequest true "Item data"
-// @Success 201 {object} models.Item
+// @Param X-Actor header string false "Who is creating the item, recorded as created_by and updated_by"
+// @Success 201 {object} models.ItemResponse
+// @Header 201 {string} Location "URL of the created item"
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /inventory [post]
@@ -47,33 +72,250 @@ func (h *ItemController) CreateItem(c *gin.Context) {
 		return
 	}
 
+	req.Actor = utils.RequestActor(c)
 	item, err := h.itemService.CreateItem(&req)
 	if err != nil {
+		if errors.Is(err, utils.ErrNameTooLong) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid item name",
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		if errors.Is(err, utils.ErrValueTooLarge) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Value too large",
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		if errors.Is(err, utils.ErrCategoryNotFound) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid category",
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+
 		utils.Error.Printf("Failed to create item: %v", err)
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		c.JSON(errorStatus(err), models.ErrorResponse{
 			Error:   "Failed to create item",
 			Message: err.Error(),
-			Code:    http.StatusInternalServerError,
+			Code:    errorStatus(err),
 		})
 		return
 	}
 
 	utils.Info.Printf("Created item: %s", item.ID)
-	c.JSON(http.StatusCreated, item)
+	setItemLocation(c, item)
+	c.JSON(http.StatusCreated, models.ItemResponse{
+		Item:     *item,
+		Warnings: h.itemService.PriceWarnings(item.Price),
+	})
+}
+
+// UpsertItemBySKU handles PUT /inventory/by-sku/:sku
+// @Summary Create or replace an item by SKU
+// @Description Create an item with the given SKU, or replace the fields of the existing item with that SKU. Responds 201 with a Location header when the item was created and 200 with the stored item when it already existed. Safe to repeat for idempotent syncs from external systems.
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param sku path string true "Stock keeping unit (max 64 characters)"
+// @Param item body models.CreateItemRequest true "Item data"
+// @Param X-Actor header string false "Who is making the change, recorded as updated_by (and created_by for new items)"
+// @Success 200 {object} models.ItemResponse
+// @Success 201 {object} models.ItemResponse
+// @Header 201 {string} Location "URL of the created item"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/by-sku/{sku} [put]
+func (h *ItemController) UpsertItemBySKU(c *gin.Context) {
+	sku := c.Param("sku")
+	if strings.TrimSpace(sku) == "" || len(sku) > models.MaxSKULength {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid SKU",
+			Message: fmt.Sprintf("sku must be between 1 and %d characters", models.MaxSKULength),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	var req models.CreateItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error.Printf("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	req.Actor = utils.RequestActor(c)
+	item, created, err := h.itemService.UpsertItemBySKU(sku, &req)
+	if err != nil {
+		if errors.Is(err, utils.ErrNameTooLong) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid item name",
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		if errors.Is(err, utils.ErrValueTooLarge) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Value too large",
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		if errors.Is(err, utils.ErrCategoryNotFound) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid category",
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+
+		utils.Error.Printf("Failed to upsert item by sku %s: %v", sku, err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to upsert item",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+		utils.Info.Printf("Created item %s for sku %s", item.ID, sku)
+		setItemLocation(c, item)
+	} else {
+		utils.Info.Printf("Updated item %s for sku %s", item.ID, sku)
+	}
+
+	c.JSON(status, models.ItemResponse{
+		Item:     *item,
+		Warnings: h.itemService.PriceWarnings(item.Price),
+	})
+}
+
+// ValidateItems handles POST /inventory/validate
+// @Summary Validate items without creating them
+// @Description Run the create validation over a list of items and report the result for each row. Nothing is written to the database.
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param request body models.ValidateItemsRequest true "Items to validate"
+// @Success 200 {object} models.ValidateItemsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Router /inventory/validate [post]
+func (h *ItemController) ValidateItems(c *gin.Context) {
+	var req models.ValidateItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error.Printf("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	response := models.ValidateItemsResponse{
+		Results: make([]models.RowValidation, len(req.Items)),
+	}
+	for i := range req.Items {
+		result := models.RowValidation{Row: i, Valid: true}
+		if err := binding.Validator.ValidateStruct(&req.Items[i]); err != nil {
+			result.Valid = false
+			result.Errors = validationMessages(err, req.Items[i])
+		}
+		if err := h.itemService.ValidateName(req.Items[i].Name); err != nil {
+			result.Valid = false
+			result.Errors = append(result.Errors, err.Error())
+		}
+		if err := h.itemService.ValidateStock(req.Items[i].Stock); err != nil {
+			result.Valid = false
+			result.Errors = append(result.Errors, err.Error())
+		}
+		if err := h.itemService.ValidatePrice(req.Items[i].Price); err != nil {
+			result.Valid = false
+			result.Errors = append(result.Errors, err.Error())
+		}
+
+		if result.Valid {
+			response.Valid++
+		} else {
+			response.Invalid++
+		}
+		response.Results[i] = result
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// validationMessages turns a validation error for target into readable
+// messages keyed by the JSON field names clients send
+func validationMessages(err error, target interface{}) []string {
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		return []string{err.Error()}
+	}
+
+	targetType := reflect.TypeOf(target)
+	messages := make([]string, 0, len(fieldErrors))
+	for _, fe := range fieldErrors {
+		field := fe.Field()
+		if sf, ok := targetType.FieldByName(fe.StructField()); ok {
+			if name := strings.Split(sf.Tag.Get("json"), ",")[0]; name != "" {
+				field = name
+			}
+		}
+
+		switch fe.Tag() {
+		case "required":
+			messages = append(messages, fmt.Sprintf("%s is required", field))
+		case "min":
+			messages = append(messages, fmt.Sprintf("%s must be at least %s", field, fe.Param()))
+		case "max":
+			messages = append(messages, fmt.Sprintf("%s must be at most %s", field, fe.Param()))
+		case "oneof":
+			messages = append(messages, fmt.Sprintf("%s must be one of %s", field, fe.Param()))
+		case "http_url":
+			messages = append(messages, fmt.Sprintf("%s must be an http or https URL", field))
+		default:
+			messages = append(messages, fmt.Sprintf("%s failed the %s rule", field, fe.Tag()))
+		}
+	}
+
+	return messages
 }
 
 // GetItem handles GET /inventory/:id
 // @Summary Get an item by ID
-// @Description Get a specific inventory item by its ID
+// @Description Get a specific inventory item by its ID. Soft-deleted items are not found unless include_deleted is set, which requires the API key and returns them with deleted_at.
 // @Tags items
 // @Accept json
-// @Produce json
+// @Produce json,xml
 // @Param id path string true "Item ID"
+// @Param include_deleted query bool false "Also find soft-deleted items (requires the API key)"
+// @Param display_currency query string false "Add converted_price in this currency, defaulting to DISPLAY_CURRENCY; left out when no exchange rate is available" example(EUR)
 // @Success 200 {object} models.Item
+// @Header 200 {string} ETag "Changes whenever the item is updated"
+// @Header 200 {string} Last-Modified "When the item was last updated"
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /inventory/{id} [get]
+// @Router /inventory/{id} [head]
 func (h *ItemController) GetItem(c *gin.Context) {
 	id := c.Param("id")
 	
@@ -87,8 +329,29 @@ func (h *ItemController) GetItem(c *gin.Context) {
 		})
 		return
 	}
+	currency, ok := h.displayCurrency(c)
+	if !ok {
+		invalidDisplayCurrency(c)
+		return
+	}
+
+	includeDeleted := c.Query("include_deleted") == "true"
+	if includeDeleted && !utils.IsAuthenticated(c) {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Including deleted items requires a valid API key",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
 
-	item, err := h.itemService.GetItem(id)
+	var item *models.Item
+	var err error
+	if includeDeleted {
+		item, err = h.itemService.GetItemWithDeleted(id)
+	} else {
+		item, err = h.itemService.GetItem(id)
+	}
 	if err != nil {
 		if err.Error() == "item not found" {
 			c.JSON(http.StatusNotFound, models.ErrorResponse{
@@ -100,56 +363,48 @@ func (h *ItemController) GetItem(c *gin.Context) {
 		}
 		
 		utils.Error.Printf("Failed to get item: %v", err)
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		c.JSON(errorStatus(err), models.ErrorResponse{
 			Error:   "Failed to get item",
 			Message: err.Error(),
-			Code:    http.StatusInternalServerError,
+			Code:    errorStatus(err),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, item)
+	setItemValidators(c, item)
+	respond(c, http.StatusOK, h.convertPrice(item, currency))
 }
 
-// UpdateItem handles PUT /inventory/:id
-// @Summary Update an item
-// @Description Update an existing inventory item
+// GetItemByNumber handles GET /inventory/number/:n
+// @Summary Get an item by its item number
+// @Description Get a specific inventory item by its sequential item number, for systems that cannot use UUIDs
 // @Tags items
 // @Accept json
-// @Produce json
-// @Param id path string true "Item ID"
-// @Param item body models.UpdateItemRequest true "Updated item data"
+// @Produce json,xml
+// @Param n path int true "Item number"
+// @Param display_currency query string false "Add converted_price in this currency, defaulting to DISPLAY_CURRENCY; left out when no exchange rate is available" example(EUR)
 // @Success 200 {object} models.Item
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /inventory/{id} [put]
-func (h *ItemController) UpdateItem(c *gin.Context) {
-	id := c.Param("id")
-	
-	// Validate UUID format
-	if _, err := uuid.Parse(id); err != nil {
-		utils.Error.Printf("Invalid UUID format: %v", err)
+// @Router /inventory/number/{n} [get]
+func (h *ItemController) GetItemByNumber(c *gin.Context) {
+	number, err := strconv.ParseInt(c.Param("n"), 10, 64)
+	if err != nil || number < 1 {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Invalid UUID format",
-			Message: "The provided ID is not a valid UUID",
+			Error:   "Invalid item number",
+			Message: "The item number must be a positive integer",
 			Code:    http.StatusBadRequest,
 		})
 		return
 	}
-
-	var req models.UpdateItemRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.Error.Printf("Invalid request body: %v", err)
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Invalid request body",
-			Message: err.Error(),
-			Code:    http.StatusBadRequest,
-		})
+	currency, ok := h.displayCurrency(c)
+	if !ok {
+		invalidDisplayCurrency(c)
 		return
 	}
 
-	item, err := h.itemService.UpdateItem(id, &req)
+	item, err := h.itemService.GetItemByNumber(number)
 	if err != nil {
 		if err.Error() == "item not found" {
 			c.JSON(http.StatusNotFound, models.ErrorResponse{
@@ -159,36 +414,38 @@ func (h *ItemController) UpdateItem(c *gin.Context) {
 			})
 			return
 		}
-		
-		utils.Error.Printf("Failed to update item: %v", err)
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Failed to update item",
+
+		utils.Error.Printf("Failed to get item: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to get item",
 			Message: err.Error(),
-			Code:    http.StatusInternalServerError,
+			Code:    errorStatus(err),
 		})
 		return
 	}
 
-	utils.Info.Printf("Updated item: %s", item.ID)
-	c.JSON(http.StatusOK, item)
+	respond(c, http.StatusOK, h.convertPrice(item, currency))
 }
 
-// DeleteItem handles DELETE /inventory/:id
-// @Summary Delete an item
-// @Description Delete an inventory item by its ID
+// GetItemMovements handles GET /inventory/:id/movements
+// @Summary Get the stock ledger of an item
+// @Description Get a page of the recorded stock changes of an item, oldest first
 // @Tags items
 // @Accept json
 // @Produce json
 // @Param id path string true "Item ID"
-// @Success 204 "No Content"
+// @Param limit query int false "Number of movements per page (max 100, defaults to DEFAULT_PAGE_SIZE)" default(10)
+// @Param cursor query string false "Cursor for pagination"
+// @Success 200 {object} models.PaginatedMovementsResponse
+// @Header 200 {integer} X-Total-Count "Total number of movements of the item"
+// @Header 200 {string} Link "RFC 5988 links to the first and next pages"
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /inventory/{id} [delete]
-func (h *ItemController) DeleteItem(c *gin.Context) {
+// @Router /inventory/{id}/movements [get]
+func (h *ItemController) GetItemMovements(c *gin.Context) {
 	id := c.Param("id")
-	
-	// Validate UUID format
+
 	if _, err := uuid.Parse(id); err != nil {
 		utils.Error.Printf("Invalid UUID format: %v", err)
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
@@ -199,8 +456,36 @@ func (h *ItemController) DeleteItem(c *gin.Context) {
 		return
 	}
 
-	err := h.itemService.DeleteItem(id)
+	if err := validateLimit(c.Query("limit")); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid pagination parameters",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	var pagination models.PaginationRequest
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		utils.Error.Printf("Invalid pagination parameters: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid pagination parameters",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	movements, err := h.itemService.GetItemMovements(id, &pagination)
 	if err != nil {
+		if errors.Is(err, utils.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid cursor",
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
 		if err.Error() == "item not found" {
 			c.JSON(http.StatusNotFound, models.ErrorResponse{
 				Error:   "Item not found",
@@ -209,122 +494,2142 @@ func (h *ItemController) DeleteItem(c *gin.Context) {
 			})
 			return
 		}
-		
-		utils.Error.Printf("Failed to delete item: %v", err)
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Failed to delete item",
+
+		utils.Error.Printf("Failed to get stock movements: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to get stock movements",
 			Message: err.Error(),
-			Code:    http.StatusInternalServerError,
+			Code:    errorStatus(err),
 		})
 		return
 	}
 
-	utils.Info.Printf("Deleted item: %s", id)
-	c.Status(http.StatusNoContent)
+	setPaginationHeaders(c, &pagination, movements.Total, movements.NextCursor)
+	c.JSON(http.StatusOK, movements)
 }
 
-// GetItems handles GET /inventory
-// @Summary Get all items
-// @Description Get all inventory items with pagination, filtering, and sorting
+// UpdateItem handles PUT /inventory/:id
+// @Summary Update an item
+// @Description Update an existing inventory item
 // @Tags items
 // @Accept json
 // @Produce json
-// @Param limit query int false "Number of items per page (max 100)" default(10)
-// @Param cursor query string false "Cursor for pagination"
-// @Param name query string false "Filter by item name (partial match)"
-// @Param min_stock query int false "Filter by minimum stock level"
-// @Param min_price query number false "Filter by minimum price"
-// @Param max_price query number false "Filter by maximum price"
-// @Param sort_by query string false "Sort by field (name, stock, price, created_at)" default(created_at)
-// @Param sort_order query string false "Sort order (asc, desc)" default(desc)
-// @Success 200 {object} models.PaginatedResponse
+// @Param id path string true "Item ID"
+// @Param item body models.UpdateItemRequest true "Updated item data"
+// @Param X-Actor header string false "Who is making the change, recorded as updated_by"
+// @Param X-Lock-Owner header string false "Owner token of the lock held on the item"
+// @Param If-Unmodified-Since header string false "Respond 412 instead of updating when the item changed since this HTTP date"
+// @Success 200 {object} models.ItemResponse
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 412 {object} models.ErrorResponse
+// @Failure 423 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /inventory [get]
-func (h *ItemController) GetItems(c *gin.Context) {
-	// Parse pagination parameters
-	var pagination models.PaginationRequest
-	if err := c.ShouldBindQuery(&pagination); err != nil {
-		utils.Error.Printf("Invalid pagination parameters: %v", err)
+// @Router /inventory/{id} [put]
+func (h *ItemController) UpdateItem(c *gin.Context) {
+	id := c.Param("id")
+	
+	// Validate UUID format
+	if _, err := uuid.Parse(id); err != nil {
+		utils.Error.Printf("Invalid UUID format: %v", err)
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Invalid pagination parameters",
-			Message: err.Error(),
+			Error:   "Invalid UUID format",
+			Message: "The provided ID is not a valid UUID",
 			Code:    http.StatusBadRequest,
 		})
 		return
 	}
 
-	// Parse filter parameters
-	var filters models.FilterRequest
-	if err := c.ShouldBindQuery(&filters); err != nil {
-		utils.Error.Printf("Invalid filter parameters: %v", err)
+	var req models.UpdateItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error.Printf("Invalid request body: %v", err)
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Invalid filter parameters",
+			Error:   "Invalid request body",
 			Message: err.Error(),
 			Code:    http.StatusBadRequest,
 		})
 		return
 	}
 
-	// Parse sort parameters
-	var sort models.SortRequest
-	if err := c.ShouldBindQuery(&sort); err != nil {
-		utils.Error.Printf("Invalid sort parameters: %v", err)
+	h.updateItem(c, id, &req)
+}
+
+// PatchItem handles PATCH /inventory/:id
+// @Summary Merge patch an item
+// @Description Apply an RFC 7386 JSON merge patch to an item. Absent fields are left untouched and a null image_url removes the image; other fields cannot be null.
+// @Tags items
+// @Accept json
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param id path string true "Item ID"
+// @Param item body models.UpdateItemRequest true "Merge patch of the item"
+// @Param X-Actor header string false "Who is making the change, recorded as updated_by"
+// @Param X-Lock-Owner header string false "Owner token of the lock held on the item"
+// @Param If-Unmodified-Since header string false "Respond 412 instead of updating when the item changed since this HTTP date"
+// @Success 200 {object} models.ItemResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 412 {object} models.ErrorResponse
+// @Failure 423 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/{id} [patch]
+func (h *ItemController) PatchItem(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		utils.Error.Printf("Invalid UUID format: %v", err)
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Invalid sort parameters",
-			Message: err.Error(),
+			Error:   "Invalid UUID format",
+			Message: "The provided ID is not a valid UUID",
 			Code:    http.StatusBadRequest,
 		})
 		return
 	}
 
-	// Set default values
-	if pagination.Limit == 0 {
-		pagination.Limit = 10
-	}
-	if sort.SortBy == "" {
-		sort.SortBy = "created_at"
+	var req *models.UpdateItemRequest
+	body, err := c.GetRawData()
+	if err == nil {
+		req, err = models.DecodeItemMergePatch(body)
 	}
-	if sort.SortOrder == "" {
-		sort.SortOrder = "desc"
+	if err == nil {
+		err = binding.Validator.ValidateStruct(req)
 	}
-
-	response, err := h.itemService.GetItems(&pagination, &filters, &sort)
 	if err != nil {
-		utils.Error.Printf("Failed to get items: %v", err)
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Failed to get items",
+		utils.Error.Printf("Invalid merge patch: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request body",
 			Message: err.Error(),
-			Code:    http.StatusInternalServerError,
+			Code:    http.StatusBadRequest,
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	h.updateItem(c, id, req)
 }
 
-// GetItemStats handles GET /inventory/stats
-// @Summary Get inventory statistics
-// @Description Get statistics about the inventory
-// @Tags items
-// @Accept json
-// @Produce json
-// @Success 200 {object} map[string]interface{}
-// @Failure 500 {object} models.ErrorResponse
+// updateItem applies a decoded update to the item and writes the response
+func (h *ItemController) updateItem(c *gin.Context, id string, req *models.UpdateItemRequest) {
+	req.Actor = utils.RequestActor(c)
+	req.LockOwner = c.GetHeader(utils.LockOwnerHeader)
+	req.UnmodifiedSince = unmodifiedSince(c)
+	item, err := h.itemService.UpdateItem(id, req)
+	if err != nil {
+		if errors.Is(err, utils.ErrPreconditionFailed) {
+			preconditionFailed(c)
+			return
+		}
+		if errors.Is(err, utils.ErrItemLocked) {
+			c.JSON(http.StatusLocked, models.ErrorResponse{
+				Error:   "Item locked",
+				Message: "The item is being edited by someone else; retry once their lock is released or expires",
+				Code:    http.StatusLocked,
+			})
+			return
+		}
+
+		if err.Error() == "item not found" {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "Item not found",
+				Message: "The requested item does not exist",
+				Code:    http.StatusNotFound,
+			})
+			return
+		}
+		
+		if errors.Is(err, utils.ErrNameTooLong) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid item name",
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		if errors.Is(err, utils.ErrValueTooLarge) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Value too large",
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		if errors.Is(err, utils.ErrCategoryNotFound) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid category",
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+
+		utils.Error.Printf("Failed to update item: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to update item",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	var warnings []string
+	if req.Price != nil {
+		warnings = h.itemService.PriceWarnings(*req.Price)
+	}
+
+	utils.Info.Printf("Updated item: %s", item.ID)
+	c.JSON(http.StatusOK, models.ItemResponse{
+		Item:     *item,
+		Warnings: warnings,
+	})
+}
+
+// CloneItem handles POST /inventory/:id/clone
+// @Summary Clone an item
+// @Description Copy an existing item into a new item, applying optional overrides
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param id path string true "Source item ID"
+// @Param item body models.UpdateItemRequest false "Fields to override on the clone"
+// @Param X-Actor header string false "Who is cloning the item, recorded as created_by and updated_by on the clone"
+// @Success 201 {object} models.ItemResponse
+// @Header 201 {string} Location "URL of the clone"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/{id}/clone [post]
+func (h *ItemController) CloneItem(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		utils.Error.Printf("Invalid UUID format: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid UUID format",
+			Message: "The provided ID is not a valid UUID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	// The overrides body is optional
+	var req models.UpdateItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		utils.Error.Printf("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	req.Actor = utils.RequestActor(c)
+	item, err := h.itemService.CloneItem(id, &req)
+	if err != nil {
+		if err.Error() == "item not found" {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "Item not found",
+				Message: "The requested item does not exist",
+				Code:    http.StatusNotFound,
+			})
+			return
+		}
+
+		if errors.Is(err, utils.ErrNameTooLong) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid item name",
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		if errors.Is(err, utils.ErrValueTooLarge) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Value too large",
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		if errors.Is(err, utils.ErrCategoryNotFound) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid category",
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+
+		utils.Error.Printf("Failed to clone item: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to clone item",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	utils.Info.Printf("Cloned item %s into %s", id, item.ID)
+	setItemLocation(c, item)
+	c.JSON(http.StatusCreated, models.ItemResponse{
+		Item:     *item,
+		Warnings: h.itemService.PriceWarnings(item.Price),
+	})
+}
+
+// LockItem handles POST /inventory/:id/lock
+// @Summary Lock an item for editing
+// @Description Take an advisory lock on an item so updates from other owners are rejected until it is released or expires. Locking again with the same owner renews the lock.
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param id path string true "Item ID"
+// @Param request body models.LockRequest true "Lock owner token and TTL (default 300 seconds)"
+// @Success 200 {object} models.ItemLock
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 423 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/{id}/lock [post]
+func (h *ItemController) LockItem(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid UUID format",
+			Message: "The provided ID is not a valid UUID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	var req models.LockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error.Printf("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	ttl := models.DefaultLockTTL
+	if req.TTLSeconds > 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+	}
+
+	lock, err := h.itemService.LockItem(id, req.Owner, ttl)
+	if err != nil {
+		if err.Error() == "item not found" {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "Item not found",
+				Message: "The requested item does not exist",
+				Code:    http.StatusNotFound,
+			})
+			return
+		}
+		if errors.Is(err, utils.ErrItemLocked) {
+			c.JSON(http.StatusLocked, models.ErrorResponse{
+				Error:   "Item locked",
+				Message: "The item is already locked by another owner",
+				Code:    http.StatusLocked,
+			})
+			return
+		}
+
+		utils.Error.Printf("Failed to lock item: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to lock item",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, lock)
+}
+
+// UnlockItem handles DELETE /inventory/:id/lock
+// @Summary Release an item lock
+// @Description Release the lock held on an item by the owner in the X-Lock-Owner header. Succeeds when the item is not locked.
+// @Tags items
+// @Produce json
+// @Param id path string true "Item ID"
+// @Param X-Lock-Owner header string true "Owner token of the lock"
+// @Success 204 "No Content"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 423 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/{id}/lock [delete]
+func (h *ItemController) UnlockItem(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid UUID format",
+			Message: "The provided ID is not a valid UUID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	owner := c.GetHeader(utils.LockOwnerHeader)
+	if owner == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Missing lock owner",
+			Message: fmt.Sprintf("The %s header is required", utils.LockOwnerHeader),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	if err := h.itemService.UnlockItem(id, owner); err != nil {
+		if errors.Is(err, utils.ErrItemLocked) {
+			c.JSON(http.StatusLocked, models.ErrorResponse{
+				Error:   "Item locked",
+				Message: "The item is locked by another owner",
+				Code:    http.StatusLocked,
+			})
+			return
+		}
+
+		utils.Error.Printf("Failed to unlock item: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to unlock item",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteItem handles DELETE /inventory/:id
+// @Summary Delete an item
+// @Description Delete an inventory item by its ID
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param id path string true "Item ID"
+// @Param hard query bool false "Permanently delete the item (requires authentication)"
+// @Param echo query bool false "Respond 200 with a confirmation body instead of 204"
+// @Param Prefer header string false "return=representation also responds 200 with a confirmation body"
+// @Param If-Unmodified-Since header string false "Respond 412 instead of deleting when the item changed since this HTTP date"
+// @Success 200 {object} models.DeleteResponse
+// @Success 204 "No Content"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 412 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /inventory/{id} [delete]
+func (h *ItemController) DeleteItem(c *gin.Context) {
+	id := c.Param("id")
+	
+	// Validate UUID format
+	if _, err := uuid.Parse(id); err != nil {
+		utils.Error.Printf("Invalid UUID format: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid UUID format",
+			Message: "The provided ID is not a valid UUID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	hard := c.Query("hard") == "true"
+	if hard && !utils.IsAuthenticated(c) {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Hard delete requires a valid API key",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	var err error
+	if hard {
+		err = h.itemService.HardDeleteItemUnmodifiedSince(id, unmodifiedSince(c))
+	} else {
+		err = h.itemService.DeleteItemUnmodifiedSince(id, unmodifiedSince(c))
+	}
+	if err != nil {
+		if errors.Is(err, utils.ErrPreconditionFailed) {
+			preconditionFailed(c)
+			return
+		}
+		if err.Error() == "item not found" {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "Item not found",
+				Message: "The requested item does not exist",
+				Code:    http.StatusNotFound,
+			})
+			return
+		}
+		
+		utils.Error.Printf("Failed to delete item: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to delete item",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	if hard {
+		utils.Info.Printf("Hard deleted item: %s", id)
+	} else {
+		utils.Info.Printf("Deleted item: %s", id)
+	}
+
+	echo := c.Query("echo") == "true"
+	if prefersRepresentation(c) {
+		c.Header("Preference-Applied", "return=representation")
+		echo = true
+	}
+	if echo {
+		c.JSON(http.StatusOK, models.DeleteResponse{Deleted: true, ID: id})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// prefersRepresentation reports whether the request sends the RFC 7240
+// Prefer: return=representation preference
+func prefersRepresentation(c *gin.Context) bool {
+	for _, header := range c.Request.Header.Values("Prefer") {
+		for _, preference := range strings.Split(header, ",") {
+			if strings.EqualFold(strings.TrimSpace(preference), "return=representation") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetItems handles GET /inventory
+// @Summary Get all items
+// @Description Get all inventory items with pagination, filtering, and sorting. Send Accept: application/xml for an XML response.
+// @Tags items
+// @Accept json
+// @Produce json,xml
+// @Param limit query int false "Number of items per page (max 100, defaults to DEFAULT_PAGE_SIZE)" default(10)
+// @Param cursor query string false "Cursor for pagination"
+// @Param name query string false "Filter by item name (partial match)"
+// @Param min_stock query int false "Filter by minimum stock level"
+// @Param min_price query number false "Filter by minimum price"
+// @Param max_price query number false "Filter by maximum price"
+// @Param price_eq query number false "Filter by exact price; cannot be combined with min_price or max_price"
+// @Param active query bool false "Filter by availability; only active items are listed by default"
+// @Param include_inactive query bool false "Include discontinued items when active is not set"
+// @Param created_after query string false "Filter by creation time, RFC 3339, inclusive" example(2026-10-01T00:00:00Z)
+// @Param created_before query string false "Filter by creation time, RFC 3339, exclusive" example(2026-11-01T00:00:00Z)
+// @Param uncategorized query bool false "Only items filed under no category"
+// @Param sort_by query string false "Sort by field (name, stock, price, created_at)" default(created_at)
+// @Param sort_order query string false "Sort order (asc, desc)" default(desc)
+// @Param fields query string false "Comma-separated fields to include in each item (e.g. id,name,stock)"
+// @Param empty query string false "Set to 404 to respond with 404 when no items match" Enums(404)
+// @Param include_totals query bool false "Add an aggregate of total_stock, and of total_value and avg_price per currency, over every matching item, not just this page. The overall total_value and avg_price are null when the items use more than one currency."
+// @Param display_currency query string false "Add converted_price to each item in this currency, defaulting to DISPLAY_CURRENCY; items without an exchange rate are left unconverted" example(EUR)
+// @Param If-Modified-Since header string false "Respond 304 when no matching item changed since this HTTP date"
+// @Success 200 {object} models.PaginatedResponse
+// @Header 200 {integer} X-Total-Count "Total number of items matching the filters"
+// @Header 200 {string} Link "RFC 5988 links to the first and next pages"
+// @Header 200 {string} Last-Modified "Latest change to any item matching the filters"
+// @Success 304 "No matching item changed since If-Modified-Since"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory [get]
+// @Router /inventory [head]
+func (h *ItemController) GetItems(c *gin.Context) {
+	// Validate limit up front so clients get a readable message instead of validator output
+	if err := validateLimit(c.Query("limit")); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid pagination parameters",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	// Parse pagination parameters
+	var pagination models.PaginationRequest
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		utils.Error.Printf("Invalid pagination parameters: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid pagination parameters",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	// Parse filter parameters
+	var filters models.FilterRequest
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		utils.Error.Printf("Invalid filter parameters: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid filter parameters",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	// Parse sort parameters
+	var sort models.SortRequest
+	if err := c.ShouldBindQuery(&sort); err != nil {
+		utils.Error.Printf("Invalid sort parameters: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid sort parameters",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	// Parse response shaping parameters
+	fields, err := models.FieldsRequest{Fields: c.Query("fields")}.Parse()
+	if err != nil {
+		utils.Error.Printf("Invalid fields parameter: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid fields parameter",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+	currency, ok := h.displayCurrency(c)
+	if !ok {
+		invalidDisplayCurrency(c)
+		return
+	}
+
+	// Clients may opt in to a 404 instead of an empty list
+	emptyMode := c.Query("empty")
+	if emptyMode != "" && emptyMode != "404" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid empty parameter",
+			Message: "empty must be 404 when set",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	// Set default values; the service applies the default page size
+	if sort.SortBy == "" {
+		sort.SortBy = "created_at"
+	}
+	if sort.SortOrder == "" {
+		sort.SortOrder = "desc"
+	}
+
+	// Let polling clients skip the body when nothing matching has changed
+	lastModified, err := h.itemService.LastModified(&filters)
+	if errors.Is(err, utils.ErrFilterTooBroad) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Filter too broad",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+	if err != nil {
+		utils.Error.Printf("Failed to get last modified time: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to get items",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+		if notModifiedSince(c, lastModified) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+
+	response, err := h.itemService.GetItems(&pagination, &filters, &sort, fields)
+	if errors.Is(err, utils.ErrInvalidSort) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid sort parameters",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+	if errors.Is(err, utils.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid cursor",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+	if err != nil {
+		utils.Error.Printf("Failed to get items: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to get items",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	if emptyMode == "404" && len(response.Items) == 0 {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "No items found",
+			Message: "No items match the given filters",
+			Code:    http.StatusNotFound,
+		})
+		return
+	}
+
+	// Totals cost an extra query, so they are only computed on request
+	if c.Query("include_totals") == "true" {
+		response.Aggregate, err = h.itemService.GetListAggregate(&filters)
+		if err != nil {
+			utils.Error.Printf("Failed to get list aggregate: %v", err)
+			c.JSON(errorStatus(err), models.ErrorResponse{
+				Error:   "Failed to get items",
+				Message: err.Error(),
+				Code:    errorStatus(err),
+			})
+			return
+		}
+	}
+
+	setPaginationHeaders(c, &pagination, response.Total, response.NextCursor)
+
+	if currency != "" {
+		for i := range response.Items {
+			h.itemService.ConvertPrice(&response.Items[i], currency)
+		}
+		if len(fields) > 0 {
+			fields = append(fields, "converted_price")
+		}
+	}
+
+	if len(fields) > 0 {
+		if prefersXML(c) {
+			c.JSON(http.StatusNotAcceptable, models.ErrorResponse{
+				Error:   "Not acceptable",
+				Message: "fields is only supported for JSON responses",
+				Code:    http.StatusNotAcceptable,
+			})
+			return
+		}
+
+		items, err := models.ProjectItems(response.Items, fields)
+		if err != nil {
+			utils.Error.Printf("Failed to shape items: %v", err)
+			c.JSON(errorStatus(err), models.ErrorResponse{
+				Error:   "Failed to get items",
+				Message: err.Error(),
+				Code:    errorStatus(err),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, models.PartialPaginatedResponse{
+			Items:      items,
+			NextCursor: response.NextCursor,
+			HasMore:    response.HasMore,
+			Total:      response.Total,
+			Aggregate:  response.Aggregate,
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, response)
+}
+
+// DecodeCursor handles GET /inventory/cursor/decode
+// @Summary Decode a list cursor
+// @Description Decode a cursor returned by GET /inventory, for diagnosing pagination that skipped or repeated items. Reports the item the page ended on, its sorted value when the cursor was issued, whether the item still exists and whether its sorted value has changed since. Requires an API key.
+// @Tags items
+// @Produce json
+// @Security ApiKeyAuth
+// @Param cursor query string true "Cursor to decode"
+// @Success 200 {object} models.CursorInfo
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/cursor/decode [get]
+func (h *ItemController) DecodeCursor(c *gin.Context) {
+	cursor := c.Query("cursor")
+	if cursor == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid cursor",
+			Message: "cursor is required",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	info, err := h.itemService.DescribeCursor(cursor)
+	if errors.Is(err, utils.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid cursor",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+	if err != nil {
+		utils.Error.Printf("Failed to decode cursor: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to decode cursor",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, info)
+}
+
+// SuggestNames handles GET /inventory/suggest
+// @Summary Suggest item names
+// @Description Get distinct item names starting with the given prefix, for type-ahead search
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param q query string true "Name prefix"
+// @Param limit query int false "Maximum number of suggestions (1-20)" default(5)
+// @Success 200 {array} string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/suggest [get]
+func (h *ItemController) SuggestNames(c *gin.Context) {
+	var req models.SuggestRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.Error.Printf("Invalid suggest parameters: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid suggest parameters",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	prefix := strings.TrimSpace(req.Query)
+	if prefix == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid suggest parameters",
+			Message: "q must not be blank",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = 5
+	}
+
+	names, err := h.itemService.SuggestNames(prefix, req.Limit)
+	if err != nil {
+		utils.Error.Printf("Failed to suggest names: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to suggest names",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, names)
+}
+
+// SampleItems handles GET /inventory/sample
+// @Summary Sample random items
+// @Description Get up to n items picked at random, for spot-checks and A/B tests. Unlike the listing there is no order or paging; each call draws a new sample.
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param n query int false "Number of items to sample (1-100)" default(5)
+// @Success 200 {array} models.Item
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/sample [get]
+func (h *ItemController) SampleItems(c *gin.Context) {
+	var req models.SampleRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.Error.Printf("Invalid sample parameters: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid sample parameters",
+			Message: fmt.Sprintf("n must be between 1 and %d", models.MaxSampleSize),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+	n := models.DefaultSampleSize
+	if req.N != nil {
+		n = *req.N
+	}
+
+	items, err := h.itemService.SampleItems(n)
+	if err != nil {
+		utils.Error.Printf("Failed to sample items: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to sample items",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// GetLowStockItems handles GET /inventory/low-stock
+// @Summary List low stock items
+// @Description Get the active items whose stock is below their reorder point, or below 10 for items without one, lowest stock first
+// @Tags items
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Item
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/low-stock [get]
+func (h *ItemController) GetLowStockItems(c *gin.Context) {
+	items, err := h.itemService.GetLowStockItems()
+	if err != nil {
+		utils.Error.Printf("Failed to get low stock items: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to get low stock items",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// notModifiedSince reports whether the request's If-Modified-Since header
+// covers lastModified. HTTP dates have one second resolution, so
+// lastModified is truncated before comparing.
+func notModifiedSince(c *gin.Context, lastModified time.Time) bool {
+	since, err := http.ParseTime(c.GetHeader("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// unmodifiedSince returns the time in the request's If-Unmodified-Since
+// header, or the zero time when it is absent or not a valid HTTP date, in
+// which case the header is ignored
+func unmodifiedSince(c *gin.Context) time.Time {
+	since, err := http.ParseTime(c.GetHeader("If-Unmodified-Since"))
+	if err != nil {
+		return time.Time{}
+	}
+	return since
+}
+
+// preconditionFailed responds 412 to a conditional write on an item that
+// changed since the client last saw it
+func preconditionFailed(c *gin.Context) {
+	c.JSON(http.StatusPreconditionFailed, models.ErrorResponse{
+		Error:   "Precondition failed",
+		Message: "The item has been modified since If-Unmodified-Since; fetch it again and retry",
+		Code:    http.StatusPreconditionFailed,
+	})
+}
+
+// prefersXML reports whether the Accept header asks for XML over JSON
+func prefersXML(c *gin.Context) bool {
+	switch c.NegotiateFormat(gin.MIMEJSON, gin.MIMEXML, gin.MIMEXML2) {
+	case gin.MIMEXML, gin.MIMEXML2:
+		return true
+	default:
+		return false
+	}
+}
+
+// respond writes data as XML for clients that prefer it, and as JSON
+// otherwise, including when the Accept header matches neither
+func respond(c *gin.Context, code int, data interface{}) {
+	if prefersXML(c) {
+		c.XML(code, data)
+		return
+	}
+	c.JSON(code, data)
+}
+
+// setItemValidators sets the ETag and Last-Modified of a single item
+// response, so clients and monitors can tell whether it changed. The ETag
+// is weak because the representation also depends on the Accept header
+// and query parameters.
+func setItemValidators(c *gin.Context, item *models.Item) {
+	c.Header("ETag", fmt.Sprintf(`W/"%s-%d"`, item.ID, item.UpdatedAt.UnixNano()))
+	c.Header("Last-Modified", item.UpdatedAt.UTC().Format(http.TimeFormat))
+}
+
+// displayCurrency returns the currency prices are converted into for this
+// request: display_currency when given, otherwise DISPLAY_CURRENCY, with ""
+// meaning no conversion. It reports false when display_currency is not a
+// currency code.
+func (h *ItemController) displayCurrency(c *gin.Context) (string, bool) {
+	currency := strings.ToUpper(c.Query("display_currency"))
+	if currency == "" {
+		return h.itemService.DisplayCurrency(), true
+	}
+	return currency, models.IsCurrencyCode(currency)
+}
+
+// invalidDisplayCurrency rejects a display_currency that is not a currency code
+func invalidDisplayCurrency(c *gin.Context) {
+	c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		Error:   "Invalid display currency",
+		Message: "display_currency must be a three letter currency code such as EUR",
+		Code:    http.StatusBadRequest,
+	})
+}
+
+// convertPrice returns item with its price converted into currency. The
+// item may be shared with the cache, so a converted copy is returned
+// instead of changing it.
+func (h *ItemController) convertPrice(item *models.Item, currency string) *models.Item {
+	if currency == "" {
+		return item
+	}
+	converted := *item
+	h.itemService.ConvertPrice(&converted, currency)
+	return &converted
+}
+
+// setPaginationHeaders sets X-Total-Count and an RFC 5988 Link header
+// for off-the-shelf admin frontends. Cursors only move forward, so the
+// links offer the next page and, once past it, the first page.
+func setPaginationHeaders(c *gin.Context, pagination *models.PaginationRequest, total int64, nextCursor string) {
+	c.Header("X-Total-Count", strconv.FormatInt(total, 10))
+
+	var links []string
+	if pagination.Cursor != "" {
+		links = append(links, fmt.Sprintf(`<%s>; rel="first"`, pageURL(c, "")))
+	}
+	if nextCursor != "" {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, pageURL(c, nextCursor)))
+	}
+	if len(links) > 0 {
+		c.Header("Link", strings.Join(links, ", "))
+	}
+}
+
+// pageURL returns the absolute URL of the current request with its
+// cursor replaced, or removed when cursor is empty
+func pageURL(c *gin.Context, cursor string) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	query := c.Request.URL.Query()
+	if cursor == "" {
+		query.Del("cursor")
+	} else {
+		query.Set("cursor", cursor)
+	}
+
+	u := url.URL{
+		Scheme:   scheme,
+		Host:     c.Request.Host,
+		Path:     c.Request.URL.Path,
+		RawQuery: query.Encode(),
+	}
+	return u.String()
+}
+
+// setItemLocation points the Location header of a 201 response at the
+// created item, under the inventory path the request was routed through
+func setItemLocation(c *gin.Context, item *models.Item) {
+	collection := c.Request.URL.Path
+	if i := strings.Index(collection, "/inventory"); i >= 0 {
+		collection = collection[:i+len("/inventory")]
+	}
+	c.Header("Location", collection+"/"+item.ID.String())
+}
+
+// errorStatus returns the status for a failed service call: 503 when the
+// database is shedding load or its circuit breaker is open and the client
+// should retry, 500 otherwise
+func errorStatus(err error) int {
+	if errors.Is(err, utils.ErrDatabaseBusy) || errors.Is(err, utils.ErrCircuitOpen) {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+// validateLimit checks the raw limit query value, which may be empty
+func validateLimit(raw string) error {
+	if raw == "" {
+		return nil
+	}
+
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit < 1 || limit > models.MaxPageLimit {
+		return fmt.Errorf("limit must be between 1 and %d", models.MaxPageLimit)
+	}
+
+	return nil
+}
+
+// GetItemStats handles GET /inventory/stats
+// @Summary Get inventory statistics
+// @Description Get statistics about the inventory. Total value is reported per currency, and overall only when all items share one currency. When no items match, every stat is zero, including the average price, total value and price and stock ranges. Without filters the stats cover the active items and are a snapshot recomputed periodically; computed_at tells when it was taken. With MAX_TOTAL_VALUE set, total_value_alert compares the total value in USD with it. The listing filters, such as a created_after and created_before window, scope every aggregate to the matching items and are computed on each request.
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param name query string false "Filter by item name (partial match)"
+// @Param min_stock query int false "Filter by minimum stock level"
+// @Param min_price query number false "Filter by minimum price"
+// @Param max_price query number false "Filter by maximum price"
+// @Param price_eq query number false "Filter by exact price; cannot be combined with min_price or max_price"
+// @Param active query bool false "Filter by availability; only active items are counted by default"
+// @Param include_inactive query bool false "Include discontinued items when active is not set"
+// @Param created_after query string false "Filter by creation time, RFC 3339, inclusive" example(2026-10-01T00:00:00Z)
+// @Param created_before query string false "Filter by creation time, RFC 3339, exclusive" example(2026-11-01T00:00:00Z)
+// @Param uncategorized query bool false "Only items filed under no category"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
 // @Router /inventory/stats [get]
 func (h *ItemController) GetItemStats(c *gin.Context) {
-	stats, err := h.itemService.GetItemStats()
+	var filters models.FilterRequest
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		utils.Error.Printf("Invalid filter parameters: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid filter parameters",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	stats, err := h.itemService.GetItemStats(&filters)
+	if errors.Is(err, utils.ErrFilterTooBroad) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Filter too broad",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+	if err != nil {
+		utils.Error.Printf("Failed to get item stats: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to get item stats",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// RefreshStats handles POST /inventory/stats/refresh
+// @Summary Refresh inventory statistics
+// @Description Recompute the statistics snapshot served by GET /inventory/stats now rather than at the next periodic refresh, and return it
+// @Tags items
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/stats/refresh [post]
+func (h *ItemController) RefreshStats(c *gin.Context) {
+	stats, err := h.itemService.RefreshStats()
+	if err != nil {
+		utils.Error.Printf("Failed to refresh item stats: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to refresh item stats",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetBatchStats handles POST /inventory/stats/batch
+// @Summary Get filtered counts in batch
+// @Description Count the items matching each of several named filters in a single query. Each filter is held to the same complexity budget as the item listing.
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param request body models.BatchStatsRequest true "Named filters"
+// @Success 200 {object} map[string]int64
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/stats/batch [post]
+func (h *ItemController) GetBatchStats(c *gin.Context) {
+	var req models.BatchStatsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error.Printf("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	seen := make(map[string]bool, len(req.Filters))
+	for _, filter := range req.Filters {
+		if seen[filter.Name] {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid request body",
+				Message: "Duplicate filter name: " + filter.Name,
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		seen[filter.Name] = true
+	}
+
+	counts, err := h.itemService.GetBatchCounts(req.Filters)
+	if errors.Is(err, utils.ErrFilterTooBroad) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Filter too broad",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+	if err != nil {
+		utils.Error.Printf("Failed to get batch stats: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to get batch stats",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, counts)
+}
+
+// GetStatsForIDs handles POST /inventory/stats/for-ids
+// @Summary Get stats for a set of items
+// @Description Aggregate count, total value per currency and total stock over up to 100 item IDs. The overall total_value is null when the items use more than one currency. IDs that do not exist are ignored.
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param request body models.IDStatsRequest true "Item IDs"
+// @Success 200 {object} models.IDStatsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/stats/for-ids [post]
+func (h *ItemController) GetStatsForIDs(c *gin.Context) {
+	var req models.IDStatsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error.Printf("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	stats, err := h.itemService.GetStatsForIDs(req.IDs)
+	if err != nil {
+		utils.Error.Printf("Failed to get stats for ids: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to get stats for ids",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// CompareItems handles POST /inventory/compare
+// @Summary Compare items side by side
+// @Description Return 2 to 5 items in the requested order, each with its price and stock difference from the first item (the first has zero deltas). All IDs must exist and be distinct.
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param request body models.CompareItemsRequest true "Item IDs, the first being the base of the comparison"
+// @Success 200 {object} models.CompareItemsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/compare [post]
+func (h *ItemController) CompareItems(c *gin.Context) {
+	var req models.CompareItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error.Printf("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: fmt.Sprintf("ids must be %d to %d distinct item UUIDs: %v", models.MinCompareItems, models.MaxCompareItems, err),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	comparison, err := h.itemService.CompareItems(req.IDs)
+	if err != nil {
+		if err.Error() == "item not found" {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "Item not found",
+				Message: "One or more of the requested items do not exist",
+				Code:    http.StatusNotFound,
+			})
+			return
+		}
+
+		utils.Error.Printf("Failed to compare items: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to compare items",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, comparison)
+}
+
+// GetRelatedItems handles GET /inventory/:id/related
+// @Summary Get related items
+// @Description Get active items related to an item: in the same category (or also uncategorized), in the same currency and priced within 20% of it, closest in price first. The item itself is never included.
+// @Tags items
+// @Produce json
+// @Param id path string true "Item ID"
+// @Param limit query int false "Maximum number of related items (max 20)" default(5)
+// @Success 200 {object} models.RelatedItemsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/{id}/related [get]
+func (h *ItemController) GetRelatedItems(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		utils.Error.Printf("Invalid UUID format: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid UUID format",
+			Message: "The provided ID is not a valid UUID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	var req models.RelatedItemsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.Error.Printf("Invalid related items parameters: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = models.DefaultRelatedLimit
+	}
+
+	related, err := h.itemService.GetRelatedItems(id, req.Limit)
+	if err != nil {
+		if err.Error() == "item not found" {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "Item not found",
+				Message: "The requested item does not exist",
+				Code:    http.StatusNotFound,
+			})
+			return
+		}
+
+		utils.Error.Printf("Failed to get related items: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to get related items",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, related)
+}
+
+// GetDuplicates handles GET /inventory/duplicates
+// @Summary Find likely duplicate items
+// @Description Group items whose names match once lowercased and trimmed, so near-duplicate entries can be merged. Only names shared by more than one item are returned.
+// @Tags items
+// @Produce json
+// @Success 200 {object} models.DuplicatesResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/duplicates [get]
+func (h *ItemController) GetDuplicates(c *gin.Context) {
+	duplicates, err := h.itemService.FindDuplicates()
+	if err != nil {
+		utils.Error.Printf("Failed to find duplicate items: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to find duplicate items",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, duplicates)
+}
+
+// GetItemTree handles GET /inventory/tree
+// @Summary Get items grouped by category
+// @Description Get the active items nested under the category hierarchy, with item counts and stock totalled over each subtree. Items in no category are listed separately.
+// @Tags items
+// @Produce json
+// @Success 200 {object} models.ItemTreeResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/tree [get]
+func (h *ItemController) GetItemTree(c *gin.Context) {
+	tree, err := h.itemService.GetItemTree()
+	if err != nil {
+		utils.Error.Printf("Failed to get item tree: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to get item tree",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, tree)
+}
+
+// GetItemSchema handles GET /inventory/schema
+// @Summary Get item validation rules
+// @Description Get the validation rules of the item create and update requests, keyed by JSON field name, so forms can check input before submitting it. The maximum name length, stock and price reflect the configured MAX_NAME_LENGTH, MAX_STOCK and MAX_PRICE.
+// @Tags items
+// @Produce json
+// @Success 200 {object} models.ItemSchemaResponse
+// @Router /inventory/schema [get]
+func (h *ItemController) GetItemSchema(c *gin.Context) {
+	c.JSON(http.StatusOK, h.itemService.ItemSchema())
+}
+
+// GetCategoryCounts handles GET /inventory/categories
+// @Summary Count items by category
+// @Description List every category with the number of active items filed directly under it, most items first. Subcategories are counted separately rather than added to their parent.
+// @Tags items
+// @Produce json
+// @Success 200 {array} models.CategoryCount
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/categories [get]
+func (h *ItemController) GetCategoryCounts(c *gin.Context) {
+	counts, err := h.itemService.GetCategoryCounts()
+	if err != nil {
+		utils.Error.Printf("Failed to count items by category: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to count items by category",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, counts)
+}
+
+// GroupItems handles GET /inventory/group-by
+// @Summary Group items by a field
+// @Description Bucket the items matching the same filters as the item listing by category, currency or price range in one call. Categories are keyed by ID, with their names in labels, and items in no category are grouped under "uncategorized"; price ranges are labelled like "10-50" and "1000+". With counts=true only the size of each group is returned. Without it at most 1000 items can be grouped; more are rejected with 400.
+// @Tags items
+// @Produce json
+// @Param field query string true "Field to group by" Enums(category, currency, price_range)
+// @Param counts query bool false "Return the number of items per group instead of the items"
+// @Param name query string false "Filter by item name (partial match)"
+// @Param min_stock query int false "Filter by minimum stock level"
+// @Param min_price query number false "Filter by minimum price"
+// @Param max_price query number false "Filter by maximum price"
+// @Param price_eq query number false "Filter by exact price; cannot be combined with min_price or max_price"
+// @Param active query bool false "Filter by availability; only active items are grouped by default"
+// @Param include_inactive query bool false "Include discontinued items when active is not set"
+// @Success 200 {object} models.GroupedItemsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/group-by [get]
+func (h *ItemController) GroupItems(c *gin.Context) {
+	var req models.GroupByRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.Error.Printf("Invalid group parameters: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid group parameters",
+			Message: fmt.Sprintf("field must be one of: %s", strings.Join(models.GroupFields, ", ")),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	var filters models.FilterRequest
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		utils.Error.Printf("Invalid filter parameters: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid filter parameters",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	groups, err := h.itemService.GroupItems(&filters, req.Field, req.Counts)
+	if errors.Is(err, utils.ErrFilterTooBroad) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Filter too broad",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+	if errors.Is(err, utils.ErrInvalidGroupField) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid group parameters",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+	if errors.Is(err, utils.ErrTooManyToGroup) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Too many items to group",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+	if err != nil {
+		utils.Error.Printf("Failed to group items: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to group items",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, groups)
+}
+
+// GetInventoryValue handles GET /inventory/value
+// @Summary Get the value of filtered inventory
+// @Description Get the total value (price * stock) in each currency and count of the items matching the same filters as the item listing. The overall total_value is null when the items use more than one currency.
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param name query string false "Filter by item name (partial match)"
+// @Param min_stock query int false "Filter by minimum stock level"
+// @Param min_price query number false "Filter by minimum price"
+// @Param max_price query number false "Filter by maximum price"
+// @Param price_eq query number false "Filter by exact price; cannot be combined with min_price or max_price"
+// @Param active query bool false "Filter by availability; only active items are counted by default"
+// @Param include_inactive query bool false "Include discontinued items when active is not set"
+// @Success 200 {object} models.InventoryValueResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/value [get]
+func (h *ItemController) GetInventoryValue(c *gin.Context) {
+	var filters models.FilterRequest
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		utils.Error.Printf("Invalid filter parameters: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid filter parameters",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	value, err := h.itemService.GetInventoryValue(&filters)
+	if errors.Is(err, utils.ErrFilterTooBroad) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Filter too broad",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+	if err != nil {
+		utils.Error.Printf("Failed to get inventory value: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to get inventory value",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, value)
+}
+
+// stockMaxAge is how long clients and shared caches may reuse a stock
+// response. Availability badges tolerate being a few seconds behind.
+const stockMaxAge = 5 * time.Second
+
+// GetItemStock handles GET /inventory/:id/stock
+// @Summary Get the stock of an item
+// @Description Get only the stock and availability of an item, for cheap "in stock?" checks. Responses may be cached for a few seconds.
+// @Tags items
+// @Produce json
+// @Param id path string true "Item ID"
+// @Success 200 {object} models.StockResponse
+// @Header 200 {string} Cache-Control "public, max-age=5"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/{id}/stock [get]
+func (h *ItemController) GetItemStock(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		utils.Error.Printf("Invalid UUID format: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid UUID format",
+			Message: "The provided ID is not a valid UUID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	stock, err := h.itemService.GetItemStock(id)
+	if err != nil {
+		if err.Error() == "item not found" {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "Item not found",
+				Message: "The requested item does not exist",
+				Code:    http.StatusNotFound,
+			})
+			return
+		}
+
+		utils.Error.Printf("Failed to get item stock: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to get item stock",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(stockMaxAge.Seconds())))
+	c.JSON(http.StatusOK, stock)
+}
+
+// exportFlushInterval is how many exported items are written between flushes
+const exportFlushInterval = 100
+
+// ExportItems handles GET /inventory/export.jsonl
+// @Summary Export items as JSON Lines
+// @Description Stream every item matching the same filters as the item listing as newline-delimited JSON, one item per line, oldest first
+// @Tags items
+// @Produce application/x-ndjson
+// @Param name query string false "Filter by item name (partial match)"
+// @Param min_stock query int false "Filter by minimum stock level"
+// @Param min_price query number false "Filter by minimum price"
+// @Param max_price query number false "Filter by maximum price"
+// @Param price_eq query number false "Filter by exact price; cannot be combined with min_price or max_price"
+// @Param active query bool false "Filter by availability; only active items are exported by default"
+// @Param include_inactive query bool false "Include discontinued items when active is not set"
+// @Success 200 {object} models.Item "One item per line"
+// @Header 200 {string} X-Export-Truncated "true when more items matched than MAX_EXPORT_ROWS and only that many were exported"
+// @Header 200 {integer} X-Total-Count "Number of matching items, set when the export was truncated"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/export.jsonl [get]
+func (h *ItemController) ExportItems(c *gin.Context) {
+	var filters models.FilterRequest
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		utils.Error.Printf("Invalid filter parameters: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid filter parameters",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	limit, total, err := h.itemService.ExportLimit(&filters)
+	if errors.Is(err, utils.ErrFilterTooBroad) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Filter too broad",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+	if errors.Is(err, utils.ErrExportTooLarge) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Export too large",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
 	if err != nil {
-		utils.Error.Printf("Failed to get item stats: %v", err)
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Failed to get item stats",
+		utils.Error.Printf("Failed to count items to export: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to export items",
 			Message: err.Error(),
-			Code:    http.StatusInternalServerError,
+			Code:    errorStatus(err),
 		})
 		return
 	}
+	if limit > 0 {
+		// Headers go out with the first line, so the truncation is announced up front
+		c.Header("X-Export-Truncated", "true")
+		c.Header("X-Total-Count", strconv.FormatInt(total, 10))
+	}
 
-	c.JSON(http.StatusOK, stats)
+	encoder := json.NewEncoder(c.Writer)
+	exported := 0
+	err = h.itemService.ExportItems(&filters, limit, func(item *models.Item) error {
+		if !c.Writer.Written() {
+			c.Header("Content-Type", "application/x-ndjson")
+		}
+		if err := encoder.Encode(item); err != nil {
+			return err
+		}
+		exported++
+		if exported%exportFlushInterval == 0 {
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	if errors.Is(err, utils.ErrFilterTooBroad) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Filter too broad",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+	if err != nil {
+		utils.Error.Printf("Failed to export items after %d items: %v", exported, err)
+		// Once streaming has started the status is sent, so the export is just cut short
+		if !c.Writer.Written() {
+			c.JSON(errorStatus(err), models.ErrorResponse{
+				Error:   "Failed to export items",
+				Message: err.Error(),
+				Code:    errorStatus(err),
+			})
+		}
+		return
+	}
+
+	if !c.Writer.Written() {
+		c.Header("Content-Type", "application/x-ndjson")
+		c.Writer.WriteHeaderNow()
+	}
+	utils.Info.Printf("Exported %d items", exported)
+}
+
+// BulkTag handles POST /inventory/bulk-tag
+// @Summary Tag items by filter
+// @Description Attach tags to every item matching the filter in one transaction. As in the listing, inactive items only match with include_inactive or active=false. Tags an item already carries are kept; the count includes every matching item. Filters over the complexity budget are rejected with 400 as in the listing.
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param request body models.BulkTagRequest true "Items to tag and the tags to attach"
+// @Success 200 {object} map[string]int64
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/bulk-tag [post]
+func (h *ItemController) BulkTag(c *gin.Context) {
+	var req models.BulkTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error.Printf("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	tagged, err := h.service(c).BulkTag(&req.Filter, req.Tags)
+	if errors.Is(err, utils.ErrFilterTooBroad) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Filter too broad",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+	if err != nil {
+		utils.Error.Printf("Failed to tag items: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to tag items",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	utils.Info.Printf("Tagged %d items with %v", tagged, req.Tags)
+	c.JSON(http.StatusOK, map[string]int64{
+		"tagged": tagged,
+	})
+}
+
+// GetItemTags handles GET /inventory/:id/tags
+// @Summary Get an item's tags
+// @Description Get the tags attached to an item in alphabetical order
+// @Tags items
+// @Produce json
+// @Param id path string true "Item ID"
+// @Success 200 {array} string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/{id}/tags [get]
+func (h *ItemController) GetItemTags(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		utils.Error.Printf("Invalid UUID format: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid UUID format",
+			Message: "The provided ID is not a valid UUID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	tags, err := h.itemService.GetItemTags(id)
+	if err != nil {
+		if err.Error() == "item not found" {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "Item not found",
+				Message: "The requested item does not exist",
+				Code:    http.StatusNotFound,
+			})
+			return
+		}
+
+		utils.Error.Printf("Failed to get item tags: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to get item tags",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// BulkSoftDelete handles POST /inventory/bulk-soft-delete
+// @Summary Soft-delete items by filter
+// @Description Soft-delete every item matching the filter in one statement, for example to discontinue a range of items at once. As in the listing, inactive items only match with include_inactive or active=false. An empty filter is refused unless confirm_all=true is passed, and filters over the complexity budget are rejected as in the listing.
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param request body models.FilterRequest true "Items to delete"
+// @Param confirm_all query bool false "Allow an empty filter, deleting every active item"
+// @Success 200 {object} map[string]int64
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/bulk-soft-delete [post]
+func (h *ItemController) BulkSoftDelete(c *gin.Context) {
+	var filters models.FilterRequest
+	if err := c.ShouldBindJSON(&filters); err != nil {
+		utils.Error.Printf("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	deleted, err := h.service(c).BulkSoftDelete(&filters, c.Query("confirm_all") == "true")
+	if errors.Is(err, utils.ErrFilterRequired) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Filter required",
+			Message: "An empty filter would delete every item; narrow the filter or pass confirm_all=true",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+	if errors.Is(err, utils.ErrFilterTooBroad) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Filter too broad",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+	if err != nil {
+		utils.Error.Printf("Failed to delete items: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to delete items",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	utils.Info.Printf("Soft-deleted %d items by filter", deleted)
+	c.JSON(http.StatusOK, map[string]int64{
+		"deleted": deleted,
+	})
+}
+
+// PurgeDeletedItems handles POST /inventory/purge
+// @Summary Purge soft-deleted items
+// @Description Permanently remove items soft-deleted longer ago than the configured retention period
+// @Tags items
+// @Produce json
+// @Success 200 {object} map[string]int64
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /inventory/purge [post]
+func (h *ItemController) PurgeDeletedItems(c *gin.Context) {
+	purged, err := h.itemService.PurgeSoftDeletedItems()
+	if err != nil {
+		utils.Error.Printf("Failed to purge deleted items: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to purge deleted items",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	utils.Info.Printf("Purged %d soft-deleted items", purged)
+	c.JSON(http.StatusOK, map[string]int64{
+		"purged": purged,
+	})
+}
+
+// NormalizePrices handles POST /admin/normalize-prices
+// @Summary Round every price to cents
+// @Description One-off cleanup rounding the price of every item, including soft-deleted ones, to two decimals in a single update. Returns how many items were changed.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]int64
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/normalize-prices [post]
+func (h *ItemController) NormalizePrices(c *gin.Context) {
+	normalized, err := h.itemService.NormalizePrices()
+	if err != nil {
+		utils.Error.Printf("Failed to normalize prices: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to normalize prices",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	utils.Info.Printf("Normalized the prices of %d items", normalized)
+	c.JSON(http.StatusOK, map[string]int64{
+		"normalized": normalized,
+	})
+}
+
+// Transact handles POST /inventory/transact
+// @Summary Apply stock changes atomically
+// @Description Apply stock deltas to several items in one transaction. If any item is missing, would drop below zero stock or would exceed MAX_STOCK, no item is changed.
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param request body models.TransactRequest true "Stock operations"
+// @Success 200 {object} models.TransactResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.TransactErrorResponse
+// @Failure 409 {object} models.TransactErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/transact [post]
+func (h *ItemController) Transact(c *gin.Context) {
+	var req models.TransactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error.Printf("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	items, err := h.service(c).Transact(req.Operations)
+	if err != nil {
+		var opErr *utils.OperationError
+		if errors.As(err, &opErr) {
+			status, title := http.StatusInternalServerError, "Transaction failed"
+			switch {
+			case errors.Is(opErr.Err, utils.ErrValueTooLarge):
+				status, title = http.StatusBadRequest, "Value too large"
+			case opErr.Err.Error() == "item not found":
+				status, title = http.StatusNotFound, "Item not found"
+			case opErr.Err.Error() == "insufficient stock":
+				status, title = http.StatusConflict, "Insufficient stock"
+			default:
+				utils.Error.Printf("Failed to apply transaction: %v", err)
+			}
+
+			c.JSON(status, models.TransactErrorResponse{
+				ErrorResponse: models.ErrorResponse{
+					Error:   title,
+					Message: err.Error(),
+					Code:    status,
+				},
+				FailedIndex: opErr.Index,
+				FailedID:    opErr.ItemID,
+			})
+			return
+		}
+
+		utils.Error.Printf("Failed to apply transaction: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Transaction failed",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.TransactResponse{Items: items})
+}
+
+// SyncStock handles POST /inventory/stock-sync
+// @Summary Sync stock levels from a supplier feed
+// @Description Set the stock of the items with the listed SKUs in one transaction. Only stock changes; SKUs that match no item are counted as unmatched. A SKU listed more than once is set to its last entry's stock and counted once.
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param entries body []models.StockSyncEntry true "Stock level per SKU"
+// @Success 200 {object} models.StockSyncResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/stock-sync [post]
+func (h *ItemController) SyncStock(c *gin.Context) {
+	var entries []models.StockSyncEntry
+	if err := c.ShouldBindJSON(&entries); err != nil {
+		utils.Error.Printf("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	if len(entries) == 0 || len(entries) > models.MaxStockSyncEntries {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: fmt.Sprintf("a stock sync must list between 1 and %d SKUs", models.MaxStockSyncEntries),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	result, err := h.service(c).SyncStock(entries)
+	if err != nil {
+		if errors.Is(err, utils.ErrValueTooLarge) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Value too large",
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		utils.Error.Printf("Failed to sync stock: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to sync stock",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	utils.Info.Printf("Synced stock: %d updated, %d unmatched", result.Updated, result.Unmatched)
+	c.JSON(http.StatusOK, result)
+}
+
+// MergeItems handles POST /inventory/merge
+// @Summary Merge duplicate items
+// @Description Fold duplicate items into the item to keep in one transaction. Their stock is added to the kept item and recorded in the stock ledger, and the merged items are soft-deleted.
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param request body models.MergeItemsRequest true "Item to keep and the items merged into it"
+// @Param X-Actor header string false "Who is merging the items, recorded as updated_by on the kept item"
+// @Param X-Lock-Owner header string false "Owner token of the locks held on the items"
+// @Success 200 {object} models.Item
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 423 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/merge [post]
+func (h *ItemController) MergeItems(c *gin.Context) {
+	var req models.MergeItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error.Printf("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	for _, id := range req.MergeIDs {
+		if strings.EqualFold(id, req.KeepID) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid request body",
+				Message: "merge_ids must not contain keep_id",
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+	}
+
+	req.Actor = utils.RequestActor(c)
+	req.LockOwner = c.GetHeader(utils.LockOwnerHeader)
+	item, err := h.service(c).MergeItems(&req)
+	if err != nil {
+		if errors.Is(err, utils.ErrItemLocked) {
+			c.JSON(http.StatusLocked, models.ErrorResponse{
+				Error:   "Item locked",
+				Message: "An item is being edited by someone else; retry once their lock is released or expires",
+				Code:    http.StatusLocked,
+			})
+			return
+		}
+
+		if err.Error() == "item not found" {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "Item not found",
+				Message: "The item to keep or an item to merge does not exist",
+				Code:    http.StatusNotFound,
+			})
+			return
+		}
+
+		utils.Error.Printf("Failed to merge items: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{
+			Error:   "Failed to merge items",
+			Message: err.Error(),
+			Code:    errorStatus(err),
+		})
+		return
+	}
+
+	utils.Info.Printf("Merged %d items into item %s", len(req.MergeIDs), item.ID)
+	c.JSON(http.StatusOK, item)
 }
 
 // SeedDatabase handles POST /inventory/seed
@@ -340,10 +2645,10 @@ func (h *ItemController) SeedDatabase(c *gin.Context) {
 	err := h.itemService.SeedDatabase()
 	if err != nil {
 		utils.Error.Printf("Failed to seed database: %v", err)
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		c.JSON(errorStatus(err), models.ErrorResponse{
 			Error:   "Failed to seed database",
 			Message: err.Error(),
-			Code:    http.StatusInternalServerError,
+			Code:    errorStatus(err),
 		})
 		return
 	}
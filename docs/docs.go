@@ -24,81 +24,31 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/inventory": {
+        "/admin/config": {
             "get": {
-                "description": "Get all inventory items with pagination, filtering, and sorting",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "Show the configuration the server loaded from the config file and environment, keyed as in config.yaml. The database password, replica DSN, API key, Redis URL, cursor secret and alert webhook URL are replaced by [REDACTED] when set.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "items"
-                ],
-                "summary": "Get all items",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Number of items per page (max 100)",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Cursor for pagination",
-                        "name": "cursor",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by item name (partial match)",
-                        "name": "name",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Filter by minimum stock level",
-                        "name": "min_stock",
-                        "in": "query"
-                    },
-                    {
-                        "type": "number",
-                        "description": "Filter by minimum price",
-                        "name": "min_price",
-                        "in": "query"
-                    },
-                    {
-                        "type": "number",
-                        "description": "Filter by maximum price",
-                        "name": "max_price",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "default": "created_at",
-                        "description": "Sort by field (name, stock, price, created_at)",
-                        "name": "sort_by",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "default": "desc",
-                        "description": "Sort order (asc, desc)",
-                        "name": "sort_order",
-                        "in": "query"
-                    }
+                    "admin"
                 ],
+                "summary": "Show the effective configuration",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.PaginatedResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -110,39 +60,35 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a new inventory item",
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/admin/migrations": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "List the migration files recorded as applied, oldest first, with the time each was applied",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "items"
-                ],
-                "summary": "Create a new item",
-                "parameters": [
-                    {
-                        "description": "Item data",
-                        "name": "item",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateItemRequest"
-                        }
-                    }
+                    "admin"
                 ],
+                "summary": "List applied database migrations",
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Item"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SchemaMigration"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -156,29 +102,37 @@ const docTemplate = `{
                 }
             }
         },
-        "/inventory/seed": {
+        "/admin/normalize-prices": {
             "post": {
-                "description": "Seed the database with sample data",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "One-off cleanup rounding the price of every item, including soft-deleted ones, to two decimals in a single update. Returns how many items were changed.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "items"
+                    "admin"
                 ],
-                "summary": "Seed the database",
+                "summary": "Round every price to cents",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "type": "string"
+                                "type": "integer"
                             }
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -188,9 +142,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/inventory/stats": {
-            "get": {
-                "description": "Get statistics about the inventory",
+        "/categories": {
+            "post": {
+                "description": "Create a category, optionally nested under a parent category. Categories nest at most 8 levels deep.",
                 "consumes": [
                     "application/json"
                 ],
@@ -198,15 +152,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "items"
+                    "categories"
+                ],
+                "summary": "Create a category",
+                "parameters": [
+                    {
+                        "description": "Category data",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateCategoryRequest"
+                        }
+                    }
                 ],
-                "summary": "Get inventory statistics",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.Category"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
@@ -218,35 +188,169 @@ const docTemplate = `{
                 }
             }
         },
-        "/inventory/{id}": {
+        "/inventory": {
             "get": {
-                "description": "Get a specific inventory item by its ID",
+                "description": "Get all inventory items with pagination, filtering, and sorting. Send Accept: application/xml for an XML response.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml"
                 ],
                 "tags": [
                     "items"
                 ],
-                "summary": "Get an item by ID",
+                "summary": "Get all items",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of items per page (max 100, defaults to DEFAULT_PAGE_SIZE)",
+                        "name": "limit",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Item ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Cursor for pagination",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by item name (partial match)",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by minimum stock level",
+                        "name": "min_stock",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Filter by minimum price",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Filter by maximum price",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Filter by exact price; cannot be combined with min_price or max_price",
+                        "name": "price_eq",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by availability; only active items are listed by default",
+                        "name": "active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include discontinued items when active is not set",
+                        "name": "include_inactive",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-10-01T00:00:00Z",
+                        "description": "Filter by creation time, RFC 3339, inclusive",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-11-01T00:00:00Z",
+                        "description": "Filter by creation time, RFC 3339, exclusive",
+                        "name": "created_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only items filed under no category",
+                        "name": "uncategorized",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "created_at",
+                        "description": "Sort by field (name, stock, price, created_at)",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Sort order (asc, desc)",
+                        "name": "sort_order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to include in each item (e.g. id,name,stock)",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "404"
+                        ],
+                        "type": "string",
+                        "description": "Set to 404 to respond with 404 when no items match",
+                        "name": "empty",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Add an aggregate of total_stock, and of total_value and avg_price per currency, over every matching item, not just this page. The overall total_value and avg_price are null when the items use more than one currency.",
+                        "name": "include_totals",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "EUR",
+                        "description": "Add converted_price to each item in this currency, defaulting to DISPLAY_CURRENCY; items without an exchange rate are left unconverted",
+                        "name": "display_currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Respond 304 when no matching item changed since this HTTP date",
+                        "name": "If-Modified-Since",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Item"
+                            "$ref": "#/definitions/models.PaginatedResponse"
+                        },
+                        "headers": {
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Latest change to any item matching the filters"
+                            },
+                            "Link": {
+                                "type": "string",
+                                "description": "RFC 5988 links to the first and next pages"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of items matching the filters"
+                            }
                         }
                     },
+                    "304": {
+                        "description": "No matching item changed since If-Modified-Since"
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
@@ -259,6 +363,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -267,8 +377,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "Update an existing inventory item",
+            "post": {
+                "description": "Create a new inventory item",
                 "consumes": [
                     "application/json"
                 ],
@@ -278,32 +388,213 @@ const docTemplate = `{
                 "tags": [
                     "items"
                 ],
-                "summary": "Update an item",
+                "summary": "Create a new item",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Item ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Updated item data",
+                        "description": "Item data",
                         "name": "item",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateItemRequest"
+                            "$ref": "#/definitions/models.CreateItemRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who is creating the item, recorded as created_by and updated_by",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ItemResponse"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the created item"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "head": {
+                "description": "Get all inventory items with pagination, filtering, and sorting. Send Accept: application/xml for an XML response.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Get all items",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of items per page (max 100, defaults to DEFAULT_PAGE_SIZE)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor for pagination",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by item name (partial match)",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by minimum stock level",
+                        "name": "min_stock",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Filter by minimum price",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Filter by maximum price",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Filter by exact price; cannot be combined with min_price or max_price",
+                        "name": "price_eq",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by availability; only active items are listed by default",
+                        "name": "active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include discontinued items when active is not set",
+                        "name": "include_inactive",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-10-01T00:00:00Z",
+                        "description": "Filter by creation time, RFC 3339, inclusive",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-11-01T00:00:00Z",
+                        "description": "Filter by creation time, RFC 3339, exclusive",
+                        "name": "created_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only items filed under no category",
+                        "name": "uncategorized",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "created_at",
+                        "description": "Sort by field (name, stock, price, created_at)",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Sort order (asc, desc)",
+                        "name": "sort_order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to include in each item (e.g. id,name,stock)",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "404"
+                        ],
+                        "type": "string",
+                        "description": "Set to 404 to respond with 404 when no items match",
+                        "name": "empty",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Add an aggregate of total_stock, and of total_value and avg_price per currency, over every matching item, not just this page. The overall total_value and avg_price are null when the items use more than one currency.",
+                        "name": "include_totals",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "EUR",
+                        "description": "Add converted_price to each item in this currency, defaulting to DISPLAY_CURRENCY; items without an exchange rate are left unconverted",
+                        "name": "display_currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Respond 304 when no matching item changed since this HTTP date",
+                        "name": "If-Modified-Since",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Item"
+                            "$ref": "#/definitions/models.PaginatedResponse"
+                        },
+                        "headers": {
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Latest change to any item matching the filters"
+                            },
+                            "Link": {
+                                "type": "string",
+                                "description": "RFC 5988 links to the first and next pages"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of items matching the filters"
+                            }
                         }
                     },
+                    "304": {
+                        "description": "No matching item changed since If-Modified-Since"
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
@@ -316,6 +607,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -323,9 +620,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete an inventory item by its ID",
+            }
+        },
+        "/inventory/bulk-soft-delete": {
+            "post": {
+                "description": "Soft-delete every item matching the filter in one statement, for example to discontinue a range of items at once. As in the listing, inactive items only match with include_inactive or active=false. An empty filter is refused unless confirm_all=true is passed, and filters over the complexity budget are rejected as in the listing.",
                 "consumes": [
                     "application/json"
                 ],
@@ -335,19 +634,153 @@ const docTemplate = `{
                 "tags": [
                     "items"
                 ],
-                "summary": "Delete an item",
+                "summary": "Soft-delete items by filter",
+                "parameters": [
+                    {
+                        "description": "Items to delete",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.FilterRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Allow an empty filter, deleting every active item",
+                        "name": "confirm_all",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/bulk-tag": {
+            "post": {
+                "description": "Attach tags to every item matching the filter in one transaction. As in the listing, inactive items only match with include_inactive or active=false. Tags an item already carries are kept; the count includes every matching item. Filters over the complexity budget are rejected with 400 as in the listing.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Tag items by filter",
+                "parameters": [
+                    {
+                        "description": "Items to tag and the tags to attach",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BulkTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/by-sku/{sku}": {
+            "put": {
+                "description": "Create an item with the given SKU, or replace the fields of the existing item with that SKU. Responds 201 with a Location header when the item was created and 200 with the stored item when it already existed. Safe to repeat for idempotent syncs from external systems.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Create or replace an item by SKU",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Item ID",
-                        "name": "id",
+                        "description": "Stock keeping unit (max 64 characters)",
+                        "name": "sku",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Item data",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateItemRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who is making the change, recorded as updated_by (and created_by for new items)",
+                        "name": "X-Actor",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ItemResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ItemResponse"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the created item"
+                            }
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -355,131 +788,3237 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
+                    }
+                }
+            }
+        },
+        "/inventory/categories": {
+            "get": {
+                "description": "List every category with the number of active items filed directly under it, most items first. Subcategories are counted separately rather than added to their parent.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Count items by category",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CategoryCount"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/compare": {
+            "post": {
+                "description": "Return 2 to 5 items in the requested order, each with its price and stock difference from the first item (the first has zero deltas). All IDs must exist and be distinct.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Compare items side by side",
+                "parameters": [
+                    {
+                        "description": "Item IDs, the first being the base of the comparison",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CompareItemsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CompareItemsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/cursor/decode": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Decode a cursor returned by GET /inventory, for diagnosing pagination that skipped or repeated items. Reports the item the page ended on, its sorted value when the cursor was issued, whether the item still exists and whether its sorted value has changed since. Requires an API key.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Decode a list cursor",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cursor to decode",
+                        "name": "cursor",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CursorInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/duplicates": {
+            "get": {
+                "description": "Group items whose names match once lowercased and trimmed, so near-duplicate entries can be merged. Only names shared by more than one item are returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Find likely duplicate items",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DuplicatesResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/export.jsonl": {
+            "get": {
+                "description": "Stream every item matching the same filters as the item listing as newline-delimited JSON, one item per line, oldest first",
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Export items as JSON Lines",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by item name (partial match)",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by minimum stock level",
+                        "name": "min_stock",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Filter by minimum price",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Filter by maximum price",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Filter by exact price; cannot be combined with min_price or max_price",
+                        "name": "price_eq",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by availability; only active items are exported by default",
+                        "name": "active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include discontinued items when active is not set",
+                        "name": "include_inactive",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "One item per line",
+                        "schema": {
+                            "$ref": "#/definitions/models.Item"
+                        },
+                        "headers": {
+                            "X-Export-Truncated": {
+                                "type": "string",
+                                "description": "true when more items matched than MAX_EXPORT_ROWS and only that many were exported"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Number of matching items, set when the export was truncated"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/group-by": {
+            "get": {
+                "description": "Bucket the items matching the same filters as the item listing by category, currency or price range in one call. Categories are keyed by ID, with their names in labels, and items in no category are grouped under \"uncategorized\"; price ranges are labelled like \"10-50\" and \"1000+\". With counts=true only the size of each group is returned. Without it at most 1000 items can be grouped; more are rejected with 400.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Group items by a field",
+                "parameters": [
+                    {
+                        "enum": [
+                            "category",
+                            "currency",
+                            "price_range"
+                        ],
+                        "type": "string",
+                        "description": "Field to group by",
+                        "name": "field",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return the number of items per group instead of the items",
+                        "name": "counts",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by item name (partial match)",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by minimum stock level",
+                        "name": "min_stock",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Filter by minimum price",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Filter by maximum price",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Filter by exact price; cannot be combined with min_price or max_price",
+                        "name": "price_eq",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by availability; only active items are grouped by default",
+                        "name": "active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include discontinued items when active is not set",
+                        "name": "include_inactive",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.GroupedItemsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/low-stock": {
+            "get": {
+                "description": "Get the active items whose stock is below their reorder point, or below 10 for items without one, lowest stock first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "List low stock items",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Item"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/merge": {
+            "post": {
+                "description": "Fold duplicate items into the item to keep in one transaction. Their stock is added to the kept item and recorded in the stock ledger, and the merged items are soft-deleted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Merge duplicate items",
+                "parameters": [
+                    {
+                        "description": "Item to keep and the items merged into it",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MergeItemsRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who is merging the items, recorded as updated_by on the kept item",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Owner token of the locks held on the items",
+                        "name": "X-Lock-Owner",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Item"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Locked",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/number/{n}": {
+            "get": {
+                "description": "Get a specific inventory item by its sequential item number, for systems that cannot use UUIDs",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Get an item by its item number",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Item number",
+                        "name": "n",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "EUR",
+                        "description": "Add converted_price in this currency, defaulting to DISPLAY_CURRENCY; left out when no exchange rate is available",
+                        "name": "display_currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Item"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/purge": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Permanently remove items soft-deleted longer ago than the configured retention period",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Purge soft-deleted items",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/sample": {
+            "get": {
+                "description": "Get up to n items picked at random, for spot-checks and A/B tests. Unlike the listing there is no order or paging; each call draws a new sample.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Sample random items",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Number of items to sample (1-100)",
+                        "name": "n",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Item"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/schema": {
+            "get": {
+                "description": "Get the validation rules of the item create and update requests, keyed by JSON field name, so forms can check input before submitting it. The maximum name length, stock and price reflect the configured MAX_NAME_LENGTH, MAX_STOCK and MAX_PRICE.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Get item validation rules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ItemSchemaResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/seed": {
+            "post": {
+                "description": "Seed the database with sample data",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Seed the database",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/stats": {
+            "get": {
+                "description": "Get statistics about the inventory. Total value is reported per currency, and overall only when all items share one currency. When no items match, every stat is zero, including the average price, total value and price and stock ranges. Without filters the stats cover the active items and are a snapshot recomputed periodically; computed_at tells when it was taken. With MAX_TOTAL_VALUE set, total_value_alert compares the total value in USD with it. The listing filters, such as a created_after and created_before window, scope every aggregate to the matching items and are computed on each request.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Get inventory statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by item name (partial match)",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by minimum stock level",
+                        "name": "min_stock",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Filter by minimum price",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Filter by maximum price",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Filter by exact price; cannot be combined with min_price or max_price",
+                        "name": "price_eq",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by availability; only active items are counted by default",
+                        "name": "active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include discontinued items when active is not set",
+                        "name": "include_inactive",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-10-01T00:00:00Z",
+                        "description": "Filter by creation time, RFC 3339, inclusive",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-11-01T00:00:00Z",
+                        "description": "Filter by creation time, RFC 3339, exclusive",
+                        "name": "created_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only items filed under no category",
+                        "name": "uncategorized",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/stats/batch": {
+            "post": {
+                "description": "Count the items matching each of several named filters in a single query. Each filter is held to the same complexity budget as the item listing.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Get filtered counts in batch",
+                "parameters": [
+                    {
+                        "description": "Named filters",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchStatsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/stats/for-ids": {
+            "post": {
+                "description": "Aggregate count, total value per currency and total stock over up to 100 item IDs. The overall total_value is null when the items use more than one currency. IDs that do not exist are ignored.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Get stats for a set of items",
+                "parameters": [
+                    {
+                        "description": "Item IDs",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.IDStatsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.IDStatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/stats/refresh": {
+            "post": {
+                "description": "Recompute the statistics snapshot served by GET /inventory/stats now rather than at the next periodic refresh, and return it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Refresh inventory statistics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/stock-sync": {
+            "post": {
+                "description": "Set the stock of the items with the listed SKUs in one transaction. Only stock changes; SKUs that match no item are counted as unmatched. A SKU listed more than once is set to its last entry's stock and counted once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Sync stock levels from a supplier feed",
+                "parameters": [
+                    {
+                        "description": "Stock level per SKU",
+                        "name": "entries",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.StockSyncEntry"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StockSyncResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/suggest": {
+            "get": {
+                "description": "Get distinct item names starting with the given prefix, for type-ahead search",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Suggest item names",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name prefix",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Maximum number of suggestions (1-20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/transact": {
+            "post": {
+                "description": "Apply stock deltas to several items in one transaction. If any item is missing, would drop below zero stock or would exceed MAX_STOCK, no item is changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Apply stock changes atomically",
+                "parameters": [
+                    {
+                        "description": "Stock operations",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TransactRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransactResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransactErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransactErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/tree": {
+            "get": {
+                "description": "Get the active items nested under the category hierarchy, with item counts and stock totalled over each subtree. Items in no category are listed separately.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Get items grouped by category",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ItemTreeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/validate": {
+            "post": {
+                "description": "Run the create validation over a list of items and report the result for each row. Nothing is written to the database.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Validate items without creating them",
+                "parameters": [
+                    {
+                        "description": "Items to validate",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidateItemsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidateItemsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/value": {
+            "get": {
+                "description": "Get the total value (price * stock) in each currency and count of the items matching the same filters as the item listing. The overall total_value is null when the items use more than one currency.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Get the value of filtered inventory",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by item name (partial match)",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by minimum stock level",
+                        "name": "min_stock",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Filter by minimum price",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Filter by maximum price",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Filter by exact price; cannot be combined with min_price or max_price",
+                        "name": "price_eq",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by availability; only active items are counted by default",
+                        "name": "active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include discontinued items when active is not set",
+                        "name": "include_inactive",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.InventoryValueResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/{id}": {
+            "get": {
+                "description": "Get a specific inventory item by its ID. Soft-deleted items are not found unless include_deleted is set, which requires the API key and returns them with deleted_at.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Get an item by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also find soft-deleted items (requires the API key)",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "EUR",
+                        "description": "Add converted_price in this currency, defaulting to DISPLAY_CURRENCY; left out when no exchange rate is available",
+                        "name": "display_currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Item"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Changes whenever the item is updated"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "When the item was last updated"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing inventory item",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Update an item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated item data",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateItemRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who is making the change, recorded as updated_by",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Owner token of the lock held on the item",
+                        "name": "X-Lock-Owner",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Respond 412 instead of updating when the item changed since this HTTP date",
+                        "name": "If-Unmodified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ItemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Locked",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete an inventory item by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Delete an item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Permanently delete the item (requires authentication)",
+                        "name": "hard",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Respond 200 with a confirmation body instead of 204",
+                        "name": "echo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "return=representation also responds 200 with a confirmation body",
+                        "name": "Prefer",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Respond 412 instead of deleting when the item changed since this HTTP date",
+                        "name": "If-Unmodified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeleteResponse"
+                        }
+                    },
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "head": {
+                "description": "Get a specific inventory item by its ID. Soft-deleted items are not found unless include_deleted is set, which requires the API key and returns them with deleted_at.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Get an item by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also find soft-deleted items (requires the API key)",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "EUR",
+                        "description": "Add converted_price in this currency, defaulting to DISPLAY_CURRENCY; left out when no exchange rate is available",
+                        "name": "display_currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Item"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Changes whenever the item is updated"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "When the item was last updated"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Apply an RFC 7386 JSON merge patch to an item. Absent fields are left untouched and a null image_url removes the image; other fields cannot be null.",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Merge patch an item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Merge patch of the item",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateItemRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who is making the change, recorded as updated_by",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Owner token of the lock held on the item",
+                        "name": "X-Lock-Owner",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Respond 412 instead of updating when the item changed since this HTTP date",
+                        "name": "If-Unmodified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ItemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Locked",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/{id}/clone": {
+            "post": {
+                "description": "Copy an existing item into a new item, applying optional overrides",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Clone an item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Source item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to override on the clone",
+                        "name": "item",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateItemRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who is cloning the item, recorded as created_by and updated_by on the clone",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ItemResponse"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the clone"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/{id}/lock": {
+            "post": {
+                "description": "Take an advisory lock on an item so updates from other owners are rejected until it is released or expires. Locking again with the same owner renews the lock.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Lock an item for editing",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Lock owner token and TTL (default 300 seconds)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.LockRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ItemLock"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Locked",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Release the lock held on an item by the owner in the X-Lock-Owner header. Succeeds when the item is not locked.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Release an item lock",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Owner token of the lock",
+                        "name": "X-Lock-Owner",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Locked",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/{id}/movements": {
+            "get": {
+                "description": "Get a page of the recorded stock changes of an item, oldest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Get the stock ledger of an item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of movements per page (max 100, defaults to DEFAULT_PAGE_SIZE)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor for pagination",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PaginatedMovementsResponse"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "RFC 5988 links to the first and next pages"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of movements of the item"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/{id}/related": {
+            "get": {
+                "description": "Get active items related to an item: in the same category (or also uncategorized), in the same currency and priced within 20% of it, closest in price first. The item itself is never included.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Get related items",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Maximum number of related items (max 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RelatedItemsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/{id}/stock": {
+            "get": {
+                "description": "Get only the stock and availability of an item, for cheap \"in stock?\" checks. Responses may be cached for a few seconds.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Get the stock of an item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StockResponse"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "public, max-age=5"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/{id}/tags": {
+            "get": {
+                "description": "Get the tags attached to an item in alphabetical order",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Get an item's tags",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.BatchStatsRequest": {
+            "type": "object",
+            "required": [
+                "filters"
+            ],
+            "properties": {
+                "filters": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/models.NamedFilter"
+                    }
+                }
+            }
+        },
+        "models.BulkTagRequest": {
+            "type": "object",
+            "required": [
+                "tags"
+            ],
+            "properties": {
+                "filter": {
+                    "$ref": "#/definitions/models.FilterRequest"
+                },
+                "tags": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "minItems": 1,
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "clearance"
+                    ]
+                }
+            }
+        },
+        "models.Category": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Accessories"
+                },
+                "parent_id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
+        "models.CategoryCount": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "example": "Accessories"
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "id": {
+                    "type": "string",
+                    "example": "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+                }
+            }
+        },
+        "models.CategoryNode": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CategoryNode"
+                    }
+                },
+                "id": {
+                    "type": "string",
+                    "example": "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+                },
+                "item_count": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Item"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Accessories"
+                },
+                "total_stock": {
+                    "type": "integer",
+                    "example": 340
+                }
+            }
+        },
+        "models.CompareItemsRequest": {
+            "type": "object",
+            "required": [
+                "ids"
+            ],
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "maxItems": 5,
+                    "minItems": 2,
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "550e8400-e29b-41d4-a716-446655440000"
+                    ]
+                }
+            }
+        },
+        "models.CompareItemsResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ItemComparison"
+                    }
+                }
+            }
+        },
+        "models.Conversion": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 919.99
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "rate": {
+                    "type": "number",
+                    "example": 0.92
+                }
+            }
+        },
+        "models.CreateCategoryRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1,
+                    "example": "Accessories"
+                },
+                "parent_id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                }
+            }
+        },
+        "models.CreateItemRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "price",
+                "stock"
+            ],
+            "properties": {
+                "auto_reorder": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "category_id": {
+                    "type": "string",
+                    "example": "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+                },
+                "currency": {
+                    "type": "string",
+                    "enum": [
+                        "USD",
+                        "EUR",
+                        "GBP",
+                        "JPY",
+                        "CHF",
+                        "CAD",
+                        "AUD",
+                        "CNY"
+                    ],
+                    "example": "USD"
+                },
+                "height_mm": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 20
+                },
+                "image_url": {
+                    "type": "string",
+                    "maxLength": 2048,
+                    "example": "https://cdn.example.com/images/laptop.png"
+                },
+                "length_mm": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 360
+                },
+                "name": {
+                    "type": "string",
+                    "minLength": 1,
+                    "example": "Laptop"
+                },
+                "price": {
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 999.99
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "initial delivery"
+                },
+                "reorder_point": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 15
+                },
+                "stock": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 50
+                },
+                "weight_grams": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 2100
+                },
+                "width_mm": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 250
+                }
+            }
+        },
+        "models.CursorInfo": {
+            "type": "object",
+            "properties": {
+                "current_value": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "exists": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "moved": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "sort_by": {
+                    "type": "string",
+                    "example": "created_at"
+                },
+                "sort_order": {
+                    "type": "string",
+                    "example": "desc"
+                },
+                "value": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                }
+            }
+        },
+        "models.DeleteResponse": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                }
+            }
+        },
+        "models.DuplicateGroup": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Item"
+                    }
+                },
+                "normalized_name": {
+                    "type": "string",
+                    "example": "usb cable"
+                }
+            }
+        },
+        "models.DuplicatesResponse": {
+            "type": "object",
+            "properties": {
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DuplicateGroup"
+                    }
+                }
+            }
+        },
+        "models.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FieldRule": {
+            "type": "object",
+            "properties": {
+                "enum": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "USD"
+                    ]
+                },
+                "format": {
+                    "type": "string",
+                    "example": "uri"
+                },
+                "max": {
+                    "type": "number",
+                    "example": 100000000
+                },
+                "max_length": {
+                    "type": "integer",
+                    "example": 255
+                },
+                "min": {
+                    "type": "number",
+                    "example": 0
+                },
+                "min_length": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "required": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "type": {
+                    "type": "string",
+                    "example": "string"
+                }
+            }
+        },
+        "models.FilterRequest": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "created_after": {
+                    "description": "CreatedAfter and CreatedBefore bound the creation time, the first\ninclusively and the second exclusively",
+                    "type": "string",
+                    "example": "2026-10-01T00:00:00Z"
+                },
+                "created_before": {
+                    "type": "string",
+                    "example": "2026-11-01T00:00:00Z"
+                },
+                "include_inactive": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "max_price": {
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 2000
+                },
+                "min_price": {
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 100
+                },
+                "min_stock": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 10
+                },
+                "name": {
+                    "type": "string",
+                    "example": "laptop"
+                },
+                "price_eq": {
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 9.99
+                },
+                "uncategorized": {
+                    "description": "Uncategorized matches only the items filed under no category",
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "models.GroupedItemsResponse": {
+            "type": "object",
+            "properties": {
+                "counts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "field": {
+                    "type": "string",
+                    "example": "category"
+                },
+                "groups": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/models.Item"
+                        }
+                    }
+                },
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.IDStatsRequest": {
+            "type": "object",
+            "required": [
+                "ids"
+            ],
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "550e8400-e29b-41d4-a716-446655440000"
+                    ]
+                }
+            }
+        },
+        "models.IDStatsResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "total_stock": {
+                    "type": "integer",
+                    "example": 75
+                },
+                "total_value": {
+                    "type": "number",
+                    "example": 2049.97
+                },
+                "total_value_by_currency": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                }
+            }
+        },
+        "models.InventoryValueResponse": {
+            "type": "object",
+            "properties": {
+                "item_count": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "total_value": {
+                    "type": "number",
+                    "example": 15499.5
+                },
+                "total_value_by_currency": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                }
+            }
+        },
+        "models.Item": {
+            "type": "object",
+            "required": [
+                "name",
+                "price",
+                "stock"
+            ],
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "auto_reorder": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "category_id": {
+                    "type": "string",
+                    "example": "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+                },
+                "converted_price": {
+                    "$ref": "#/definitions/models.Conversion"
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "created_by": {
+                    "type": "string",
+                    "example": "jane.doe"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "deleted_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "height_mm": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "image_url": {
+                    "type": "string",
+                    "example": "https://cdn.example.com/images/laptop.png"
+                },
+                "item_number": {
+                    "type": "integer",
+                    "example": 1042
+                },
+                "length_mm": {
+                    "type": "integer",
+                    "example": 360
+                },
+                "name": {
+                    "type": "string",
+                    "minLength": 1,
+                    "example": "Laptop"
+                },
+                "price": {
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 999.99
+                },
+                "reorder_point": {
+                    "type": "integer",
+                    "example": 15
+                },
+                "sku": {
+                    "type": "string",
+                    "example": "LAPTOP-15-BLK"
+                },
+                "stock": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 50
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "updated_by": {
+                    "type": "string",
+                    "example": "jane.doe"
+                },
+                "volume_mm3": {
+                    "type": "integer",
+                    "example": 1800000
+                },
+                "weight_grams": {
+                    "type": "integer",
+                    "example": 2100
+                },
+                "width_mm": {
+                    "type": "integer",
+                    "example": 250
+                }
+            }
+        },
+        "models.ItemComparison": {
+            "type": "object",
+            "properties": {
+                "item": {
+                    "$ref": "#/definitions/models.Item"
+                },
+                "price_delta": {
+                    "type": "number",
+                    "example": -200
+                },
+                "stock_delta": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "models.ItemLock": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "item_id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "owner": {
+                    "type": "string",
+                    "example": "3f2b8c1e-editor-session"
+                }
+            }
+        },
+        "models.ItemResponse": {
+            "type": "object",
+            "required": [
+                "name",
+                "price",
+                "stock"
+            ],
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "auto_reorder": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "category_id": {
+                    "type": "string",
+                    "example": "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+                },
+                "converted_price": {
+                    "$ref": "#/definitions/models.Conversion"
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "created_by": {
+                    "type": "string",
+                    "example": "jane.doe"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "deleted_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "height_mm": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "image_url": {
+                    "type": "string",
+                    "example": "https://cdn.example.com/images/laptop.png"
+                },
+                "item_number": {
+                    "type": "integer",
+                    "example": 1042
+                },
+                "length_mm": {
+                    "type": "integer",
+                    "example": 360
+                },
+                "name": {
+                    "type": "string",
+                    "minLength": 1,
+                    "example": "Laptop"
+                },
+                "price": {
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 999.99
+                },
+                "reorder_point": {
+                    "type": "integer",
+                    "example": 15
+                },
+                "sku": {
+                    "type": "string",
+                    "example": "LAPTOP-15-BLK"
+                },
+                "stock": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 50
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "updated_by": {
+                    "type": "string",
+                    "example": "jane.doe"
+                },
+                "volume_mm3": {
+                    "type": "integer",
+                    "example": 1800000
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "price 99999.99 exceeds the reasonable maximum of 10000.00"
+                    ]
+                },
+                "weight_grams": {
+                    "type": "integer",
+                    "example": 2100
+                },
+                "width_mm": {
+                    "type": "integer",
+                    "example": 250
+                }
+            }
+        },
+        "models.ItemSchemaResponse": {
+            "type": "object",
+            "properties": {
+                "create": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.FieldRule"
+                    }
+                },
+                "update": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.FieldRule"
+                    }
+                }
+            }
+        },
+        "models.ItemTreeResponse": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CategoryNode"
+                    }
+                },
+                "uncategorized": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Item"
+                    }
+                }
+            }
+        },
+        "models.ListAggregate": {
+            "type": "object",
+            "properties": {
+                "avg_price": {
+                    "type": "number",
+                    "example": 129.99
+                },
+                "avg_price_by_currency": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "total_stock": {
+                    "type": "integer",
+                    "example": 340
+                },
+                "total_value": {
+                    "type": "number",
+                    "example": 15499.5
+                },
+                "total_value_by_currency": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                }
+            }
+        },
+        "models.LockRequest": {
+            "type": "object",
+            "required": [
+                "owner"
+            ],
+            "properties": {
+                "owner": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1,
+                    "example": "3f2b8c1e-editor-session"
+                },
+                "ttl_seconds": {
+                    "type": "integer",
+                    "maximum": 3600,
+                    "minimum": 1,
+                    "example": 300
+                }
+            }
+        },
+        "models.MergeItemsRequest": {
+            "type": "object",
+            "required": [
+                "keep_id",
+                "merge_ids"
+            ],
+            "properties": {
+                "keep_id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "merge_ids": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "string"
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    }
+                    "example": [
+                        "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+                    ]
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "duplicate catalog entries"
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.CreateItemRequest": {
+        },
+        "models.NamedFilter": {
             "type": "object",
             "required": [
-                "name",
-                "price",
-                "stock"
+                "name"
             ],
             "properties": {
+                "filter": {
+                    "$ref": "#/definitions/models.FilterRequest"
+                },
                 "name": {
                     "type": "string",
-                    "maxLength": 255,
+                    "maxLength": 100,
                     "minLength": 1,
-                    "example": "Laptop"
+                    "example": "low_stock"
+                }
+            }
+        },
+        "models.PaginatedMovementsResponse": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "type": "boolean"
                 },
-                "price": {
-                    "type": "number",
-                    "minimum": 0,
-                    "example": 999.99
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StockMovement"
+                    }
                 },
-                "stock": {
-                    "type": "integer",
-                    "minimum": 0,
-                    "example": 50
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "models.ErrorResponse": {
+        "models.PaginatedResponse": {
             "type": "object",
             "properties": {
-                "code": {
-                    "type": "integer"
+                "aggregate": {
+                    "$ref": "#/definitions/models.ListAggregate"
                 },
-                "error": {
-                    "type": "string"
+                "has_more": {
+                    "type": "boolean"
                 },
-                "message": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Item"
+                    }
+                },
+                "next_cursor": {
                     "type": "string"
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "models.Item": {
+        "models.RelatedItemsResponse": {
             "type": "object",
-            "required": [
-                "name",
-                "price",
-                "stock"
-            ],
             "properties": {
-                "created_at": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Item"
+                    }
+                }
+            }
+        },
+        "models.RowValidation": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "price must be at least 0"
+                    ]
+                },
+                "row": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "valid": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "models.SchemaMigration": {
+            "type": "object",
+            "properties": {
+                "applied_at": {
                     "type": "string",
                     "format": "date-time"
                 },
-                "deleted_at": {
+                "name": {
+                    "type": "string",
+                    "example": "002_create_items_table.sql"
+                }
+            }
+        },
+        "models.StockMovement": {
+            "type": "object",
+            "properties": {
+                "created_at": {
                     "type": "string",
                     "format": "date-time"
                 },
+                "delta": {
+                    "type": "integer",
+                    "example": -5
+                },
                 "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "item_id": {
                     "type": "string",
                     "example": "550e8400-e29b-41d4-a716-446655440000"
                 },
-                "name": {
+                "reason": {
+                    "type": "string",
+                    "example": "customer order"
+                },
+                "resulting_stock": {
+                    "type": "integer",
+                    "example": 45
+                }
+            }
+        },
+        "models.StockOperation": {
+            "type": "object",
+            "required": [
+                "delta",
+                "id"
+            ],
+            "properties": {
+                "delta": {
+                    "type": "integer",
+                    "example": -2
+                },
+                "id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "reason": {
                     "type": "string",
                     "maxLength": 255,
-                    "minLength": 1,
-                    "example": "Laptop"
+                    "example": "order 1042"
+                }
+            }
+        },
+        "models.StockResponse": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "integer",
+                    "example": 50
                 },
-                "price": {
-                    "type": "number",
-                    "minimum": 0,
-                    "example": 999.99
+                "id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
                 },
                 "stock": {
                     "type": "integer",
-                    "minimum": 0,
                     "example": 50
-                },
-                "updated_at": {
+                }
+            }
+        },
+        "models.StockSyncEntry": {
+            "type": "object",
+            "required": [
+                "sku",
+                "stock"
+            ],
+            "properties": {
+                "sku": {
                     "type": "string",
-                    "format": "date-time"
+                    "maxLength": 64,
+                    "example": "LAPTOP-15-BLK"
+                },
+                "stock": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 40
                 }
             }
         },
-        "models.PaginatedResponse": {
+        "models.StockSyncResponse": {
             "type": "object",
             "properties": {
-                "has_more": {
-                    "type": "boolean"
+                "unmatched": {
+                    "type": "integer",
+                    "example": 2
                 },
-                "items": {
+                "unmatched_skus": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Item"
-                    }
+                        "type": "string"
+                    },
+                    "example": [
+                        "DISCONTINUED-1"
+                    ]
                 },
-                "next_cursor": {
+                "updated": {
+                    "type": "integer",
+                    "example": 118
+                }
+            }
+        },
+        "models.TransactErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "error": {
                     "type": "string"
                 },
-                "total": {
-                    "type": "integer"
+                "failed_id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "failed_index": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "message": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TransactRequest": {
+            "type": "object",
+            "required": [
+                "operations"
+            ],
+            "properties": {
+                "operations": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/models.StockOperation"
+                    }
+                }
+            }
+        },
+        "models.TransactResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Item"
+                    }
                 }
             }
         },
         "models.UpdateItemRequest": {
             "type": "object",
             "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "auto_reorder": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "category_id": {
+                    "type": "string",
+                    "example": "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+                },
+                "currency": {
+                    "type": "string",
+                    "enum": [
+                        "USD",
+                        "EUR",
+                        "GBP",
+                        "JPY",
+                        "CHF",
+                        "CAD",
+                        "AUD",
+                        "CNY"
+                    ],
+                    "example": "EUR"
+                },
+                "height_mm": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 25
+                },
+                "image_url": {
+                    "type": "string",
+                    "maxLength": 2048,
+                    "example": "https://cdn.example.com/images/laptop-v2.png"
+                },
+                "length_mm": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 380
+                },
                 "name": {
                     "type": "string",
-                    "maxLength": 255,
                     "minLength": 1,
                     "example": "Updated Laptop"
                 },
@@ -488,10 +4027,65 @@ const docTemplate = `{
                     "minimum": 0,
                     "example": 1099.99
                 },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "stock count correction"
+                },
+                "reorder_point": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 20
+                },
                 "stock": {
                     "type": "integer",
                     "minimum": 0,
                     "example": 75
+                },
+                "weight_grams": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 2300
+                },
+                "width_mm": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 260
+                }
+            }
+        },
+        "models.ValidateItemsRequest": {
+            "type": "object",
+            "required": [
+                "items"
+            ],
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/models.CreateItemRequest"
+                    }
+                }
+            }
+        },
+        "models.ValidateItemsResponse": {
+            "type": "object",
+            "properties": {
+                "invalid": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RowValidation"
+                    }
+                },
+                "valid": {
+                    "type": "integer",
+                    "example": 9
                 }
             }
         }
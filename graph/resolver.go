@@ -0,0 +1,356 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"inventory-api/models"
+	"inventory-api/utils"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// Resolver resolves the GraphQL schema by delegating to ItemService
+type Resolver struct {
+	itemService *utils.ItemService
+	// readOnly rejects every mutation with utils.ErrReadOnly, as READ_ONLY
+	// does for REST writes
+	readOnly bool
+}
+
+func NewResolver(service *utils.ItemService, readOnly bool) *Resolver {
+	return &Resolver{itemService: service, readOnly: readOnly}
+}
+
+// NewSchema parses Schema against a resolver backed by service
+func NewSchema(service *utils.ItemService, readOnly bool) (*graphql.Schema, error) {
+	return graphql.ParseSchema(Schema, NewResolver(service, readOnly))
+}
+
+type ItemFilterInput struct {
+	Name     *string
+	MinStock *int32
+	MinPrice *float64
+	MaxPrice *float64
+}
+
+type ItemSortInput struct {
+	SortBy    *string
+	SortOrder *string
+}
+
+type PaginationInput struct {
+	Limit  *int32
+	Cursor *string
+}
+
+type CreateItemInput struct {
+	Name     string
+	Stock    int32
+	Price    float64
+	Currency *string
+	ImageUrl *string
+	Reason   *string
+}
+
+type UpdateItemInput struct {
+	Name     *string
+	Stock    *int32
+	Price    *float64
+	Currency *string
+	ImageUrl *string
+	Reason   *string
+}
+
+func (r *Resolver) Item(args struct{ ID graphql.ID }) (*ItemResolver, error) {
+	id := string(args.ID)
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, fmt.Errorf("invalid UUID format")
+	}
+
+	item, err := r.itemService.GetItem(id)
+	if err != nil {
+		if err.Error() == "item not found" {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &ItemResolver{item: item}, nil
+}
+
+func (r *Resolver) Items(args struct {
+	Filter     *ItemFilterInput
+	Sort       *ItemSortInput
+	Pagination *PaginationInput
+}) (*ItemPageResolver, error) {
+	var pagination models.PaginationRequest
+	if args.Pagination != nil {
+		if args.Pagination.Limit != nil {
+			pagination.Limit = int(*args.Pagination.Limit)
+		}
+		if args.Pagination.Cursor != nil {
+			pagination.Cursor = *args.Pagination.Cursor
+		}
+	}
+
+	var filters models.FilterRequest
+	if args.Filter != nil {
+		if args.Filter.Name != nil {
+			filters.Name = *args.Filter.Name
+		}
+		if args.Filter.MinStock != nil {
+			minStock := int(*args.Filter.MinStock)
+			filters.MinStock = &minStock
+		}
+		filters.MinPrice = args.Filter.MinPrice
+		filters.MaxPrice = args.Filter.MaxPrice
+	}
+
+	sortReq := models.SortRequest{SortBy: "created_at", SortOrder: "desc"}
+	if args.Sort != nil {
+		if args.Sort.SortBy != nil {
+			sortReq.SortBy = *args.Sort.SortBy
+		}
+		if args.Sort.SortOrder != nil {
+			sortReq.SortOrder = *args.Sort.SortOrder
+		}
+	}
+
+	for _, req := range []interface{}{pagination, filters, sortReq} {
+		if err := binding.Validator.ValidateStruct(req); err != nil {
+			return nil, err
+		}
+	}
+
+	response, err := r.itemService.GetItems(&pagination, &filters, &sortReq, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ItemPageResolver{page: response}, nil
+}
+
+func (r *Resolver) ItemStats() (*ItemStatsResolver, error) {
+	stats, err := r.itemService.GetItemStats(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ItemStatsResolver{stats: stats}, nil
+}
+
+func (r *Resolver) CreateItem(args struct{ Input CreateItemInput }) (*ItemResolver, error) {
+	if r.readOnly {
+		return nil, utils.ErrReadOnly
+	}
+
+	req := models.CreateItemRequest{
+		Name:  args.Input.Name,
+		Stock: int(args.Input.Stock),
+		Price: args.Input.Price,
+	}
+	if args.Input.Currency != nil {
+		req.Currency = *args.Input.Currency
+	}
+	if args.Input.ImageUrl != nil {
+		req.ImageURL = *args.Input.ImageUrl
+	}
+	if args.Input.Reason != nil {
+		req.Reason = *args.Input.Reason
+	}
+
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+
+	item, err := r.itemService.CreateItem(&req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ItemResolver{item: item}, nil
+}
+
+func (r *Resolver) UpdateItem(args struct {
+	ID    graphql.ID
+	Input UpdateItemInput
+}) (*ItemResolver, error) {
+	if r.readOnly {
+		return nil, utils.ErrReadOnly
+	}
+
+	id := string(args.ID)
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, fmt.Errorf("invalid UUID format")
+	}
+
+	req := models.UpdateItemRequest{
+		Name:     args.Input.Name,
+		Price:    args.Input.Price,
+		Currency: args.Input.Currency,
+		ImageURL: args.Input.ImageUrl,
+	}
+	if args.Input.Stock != nil {
+		stock := int(*args.Input.Stock)
+		req.Stock = &stock
+	}
+	if args.Input.Reason != nil {
+		req.Reason = *args.Input.Reason
+	}
+
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+
+	item, err := r.itemService.UpdateItem(id, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ItemResolver{item: item}, nil
+}
+
+func (r *Resolver) DeleteItem(args struct{ ID graphql.ID }) (bool, error) {
+	if r.readOnly {
+		return false, utils.ErrReadOnly
+	}
+
+	id := string(args.ID)
+	if _, err := uuid.Parse(id); err != nil {
+		return false, fmt.Errorf("invalid UUID format")
+	}
+
+	if err := r.itemService.DeleteItem(id); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ItemResolver resolves the Item type
+type ItemResolver struct {
+	item *models.Item
+}
+
+func (r *ItemResolver) ID() graphql.ID {
+	return graphql.ID(r.item.ID.String())
+}
+
+func (r *ItemResolver) Name() string {
+	return r.item.Name
+}
+
+func (r *ItemResolver) Stock() int32 {
+	return int32(r.item.Stock)
+}
+
+func (r *ItemResolver) Price() float64 {
+	return r.item.Price
+}
+
+func (r *ItemResolver) Currency() string {
+	return r.item.Currency
+}
+
+func (r *ItemResolver) ImageUrl() *string {
+	if r.item.ImageURL == "" {
+		return nil
+	}
+	return &r.item.ImageURL
+}
+
+func (r *ItemResolver) CreatedAt() string {
+	return r.item.CreatedAt.In(models.DisplayLocation()).Format(time.RFC3339)
+}
+
+func (r *ItemResolver) UpdatedAt() string {
+	return r.item.UpdatedAt.In(models.DisplayLocation()).Format(time.RFC3339)
+}
+
+// ItemPageResolver resolves the ItemPage type
+type ItemPageResolver struct {
+	page *models.PaginatedResponse
+}
+
+func (r *ItemPageResolver) Items() []*ItemResolver {
+	items := make([]*ItemResolver, len(r.page.Items))
+	for i := range r.page.Items {
+		items[i] = &ItemResolver{item: &r.page.Items[i]}
+	}
+	return items
+}
+
+func (r *ItemPageResolver) NextCursor() *string {
+	if r.page.NextCursor == "" {
+		return nil
+	}
+	return &r.page.NextCursor
+}
+
+func (r *ItemPageResolver) HasMore() bool {
+	return r.page.HasMore
+}
+
+func (r *ItemPageResolver) Total() int32 {
+	return int32(r.page.Total)
+}
+
+// ItemStatsResolver resolves the ItemStats type from the map returned by
+// ItemService.GetItemStats
+type ItemStatsResolver struct {
+	stats map[string]interface{}
+}
+
+func (r *ItemStatsResolver) TotalItems() int32 {
+	value, _ := r.stats["total_items"].(int64)
+	return int32(value)
+}
+
+func (r *ItemStatsResolver) TotalValue() *float64 {
+	value, ok := r.stats["total_value"].(float64)
+	if !ok {
+		return nil
+	}
+	return &value
+}
+
+func (r *ItemStatsResolver) TotalValueByCurrency() []*CurrencyValueResolver {
+	byCurrency, _ := r.stats["total_value_by_currency"].(map[string]float64)
+
+	values := make([]*CurrencyValueResolver, 0, len(byCurrency))
+	for currency, value := range byCurrency {
+		values = append(values, &CurrencyValueResolver{currency: currency, value: value})
+	}
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].currency < values[j].currency
+	})
+	return values
+}
+
+func (r *ItemStatsResolver) AveragePrice() float64 {
+	value, _ := r.stats["average_price"].(float64)
+	return value
+}
+
+func (r *ItemStatsResolver) LowStockItems() int32 {
+	value, _ := r.stats["low_stock_items"].(int64)
+	return int32(value)
+}
+
+// CurrencyValueResolver resolves the CurrencyValue type
+type CurrencyValueResolver struct {
+	currency string
+	value    float64
+}
+
+func (r *CurrencyValueResolver) Currency() string {
+	return r.currency
+}
+
+func (r *CurrencyValueResolver) Value() float64 {
+	return r.value
+}
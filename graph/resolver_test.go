@@ -0,0 +1,146 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"inventory-api/models"
+	"inventory-api/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func execQuery(t *testing.T, testDB *utils.TestDB, query string, variables map[string]interface{}) (map[string]interface{}, []string) {
+	return execSchemaQuery(t, testDB, false, query, variables)
+}
+
+// execSchemaQuery runs query against a schema that rejects mutations when readOnly is set
+func execSchemaQuery(t *testing.T, testDB *utils.TestDB, readOnly bool, query string, variables map[string]interface{}) (map[string]interface{}, []string) {
+	schema, err := NewSchema(utils.NewItemServiceWithDB(testDB.DB), readOnly)
+	require.NoError(t, err)
+
+	response := schema.Exec(context.Background(), query, "", variables)
+
+	var errs []string
+	for _, err := range response.Errors {
+		errs = append(errs, err.Message)
+	}
+
+	var data map[string]interface{}
+	if response.Data != nil {
+		require.NoError(t, json.Unmarshal(response.Data, &data))
+	}
+	return data, errs
+}
+
+func TestResolver_Item(t *testing.T) {
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	item := testDB.CreateTestItem(t, "Laptop", 10, 999.99)
+
+	data, errs := execQuery(t, testDB, `query($id: ID!) { item(id: $id) { id name stock price currency } }`,
+		map[string]interface{}{"id": item.ID.String()})
+	require.Empty(t, errs)
+
+	assert.Equal(t, map[string]interface{}{
+		"id":       item.ID.String(),
+		"name":     "Laptop",
+		"stock":    float64(10),
+		"price":    999.99,
+		"currency": "USD",
+	}, data["item"])
+}
+
+func TestResolver_Items(t *testing.T) {
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	testDB.CreateTestItem(t, "Laptop", 10, 999.99)
+	testDB.CreateTestItem(t, "Mouse", 100, 25.99)
+
+	data, errs := execQuery(t, testDB, `{
+		items(filter: {minStock: 50}, sort: {sortBy: "name", sortOrder: "asc"}) {
+			items { name }
+			hasMore
+			total
+		}
+	}`, nil)
+	require.Empty(t, errs)
+
+	page := data["items"].(map[string]interface{})
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "Mouse"}}, page["items"])
+	assert.Equal(t, false, page["hasMore"])
+	assert.Equal(t, float64(1), page["total"])
+
+	_, errs = execQuery(t, testDB, `{ items(pagination: {limit: 500}) { total } }`, nil)
+	assert.NotEmpty(t, errs)
+}
+
+func TestResolver_Mutations(t *testing.T) {
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	data, errs := execQuery(t, testDB, `mutation {
+		createItem(input: {name: "Keyboard", stock: 5, price: 75.5, currency: "EUR"}) { id name stock currency }
+	}`, nil)
+	require.Empty(t, errs)
+
+	created := data["createItem"].(map[string]interface{})
+	assert.Equal(t, "Keyboard", created["name"])
+	assert.Equal(t, "EUR", created["currency"])
+	id := created["id"].(string)
+
+	data, errs = execQuery(t, testDB, `mutation($id: ID!) { updateItem(id: $id, input: {stock: 8}) { stock } }`,
+		map[string]interface{}{"id": id})
+	require.Empty(t, errs)
+	assert.Equal(t, float64(8), data["updateItem"].(map[string]interface{})["stock"])
+
+	data, errs = execQuery(t, testDB, `mutation($id: ID!) { deleteItem(id: $id) }`,
+		map[string]interface{}{"id": id})
+	require.Empty(t, errs)
+	assert.Equal(t, true, data["deleteItem"])
+
+	data, errs = execQuery(t, testDB, `query($id: ID!) { item(id: $id) { id } }`,
+		map[string]interface{}{"id": id})
+	require.Empty(t, errs)
+	assert.Nil(t, data["item"])
+}
+
+func TestResolver_CreateItemValidation(t *testing.T) {
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	_, errs := execQuery(t, testDB, `mutation {
+		createItem(input: {name: "Bad", stock: -1, price: 1}) { id }
+	}`, nil)
+	assert.NotEmpty(t, errs)
+}
+
+func TestResolver_ReadOnly(t *testing.T) {
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	item := testDB.CreateTestItem(t, "Laptop", 10, 999.99)
+	vars := map[string]interface{}{"id": item.ID.String()}
+
+	mutations := []string{
+		`mutation { createItem(input: {name: "Mouse", stock: 1, price: 25.99}) { id } }`,
+		`mutation($id: ID!) { updateItem(id: $id, input: {stock: 4}) { stock } }`,
+		`mutation($id: ID!) { deleteItem(id: $id) }`,
+	}
+	for _, mutation := range mutations {
+		_, errs := execSchemaQuery(t, testDB, true, mutation, vars)
+		assert.Equal(t, []string{"service is read-only"}, errs, mutation)
+	}
+
+	data, errs := execSchemaQuery(t, testDB, true, `query($id: ID!) { item(id: $id) { stock } }`, vars)
+	require.Empty(t, errs, "queries keep working")
+	assert.Equal(t, float64(10), data["item"].(map[string]interface{})["stock"])
+
+	var count int64
+	require.NoError(t, testDB.DB.Model(&models.Item{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
@@ -0,0 +1,88 @@
+package graph
+
+// Schema is the GraphQL schema served at /graphql. It mirrors the REST API
+// and is resolved by Resolver.
+const Schema = `
+schema {
+	query: Query
+	mutation: Mutation
+}
+
+type Query {
+	item(id: ID!): Item
+	items(filter: ItemFilter, sort: ItemSort, pagination: Pagination): ItemPage!
+	itemStats: ItemStats!
+}
+
+type Mutation {
+	createItem(input: CreateItemInput!): Item!
+	updateItem(id: ID!, input: UpdateItemInput!): Item!
+	deleteItem(id: ID!): Boolean!
+}
+
+type Item {
+	id: ID!
+	name: String!
+	stock: Int!
+	price: Float!
+	currency: String!
+	imageUrl: String
+	createdAt: String!
+	updatedAt: String!
+}
+
+type ItemPage {
+	items: [Item!]!
+	nextCursor: String
+	hasMore: Boolean!
+	total: Int!
+}
+
+type ItemStats {
+	totalItems: Int!
+	totalValue: Float
+	totalValueByCurrency: [CurrencyValue!]!
+	averagePrice: Float!
+	lowStockItems: Int!
+}
+
+type CurrencyValue {
+	currency: String!
+	value: Float!
+}
+
+input ItemFilter {
+	name: String
+	minStock: Int
+	minPrice: Float
+	maxPrice: Float
+}
+
+input ItemSort {
+	sortBy: String
+	sortOrder: String
+}
+
+input Pagination {
+	limit: Int
+	cursor: String
+}
+
+input CreateItemInput {
+	name: String!
+	stock: Int!
+	price: Float!
+	currency: String
+	imageUrl: String
+	reason: String
+}
+
+input UpdateItemInput {
+	name: String
+	stock: Int
+	price: Float
+	currency: String
+	imageUrl: String
+	reason: String
+}
+`
@@ -0,0 +1,219 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+
+	"inventory-api/inventorypb"
+	"inventory-api/models"
+	"inventory-api/utils"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Server implements the InventoryService gRPC API on top of ItemService
+type Server struct {
+	inventorypb.UnimplementedInventoryServiceServer
+	itemService *utils.ItemService
+	// readOnly rejects every write with codes.Unavailable, as READ_ONLY
+	// does for REST writes
+	readOnly bool
+}
+
+func NewServer(service *utils.ItemService, readOnly bool) *Server {
+	return &Server{itemService: service, readOnly: readOnly}
+}
+
+// NewGRPCServer returns a gRPC server with the inventory service registered
+func NewGRPCServer(service *utils.ItemService, readOnly bool) *grpc.Server {
+	server := grpc.NewServer()
+	inventorypb.RegisterInventoryServiceServer(server, NewServer(service, readOnly))
+	return server
+}
+
+func (s *Server) CreateItem(ctx context.Context, req *inventorypb.CreateItemRequest) (*inventorypb.Item, error) {
+	if s.readOnly {
+		return nil, toStatus(utils.ErrReadOnly)
+	}
+
+	createReq := models.CreateItemRequest{
+		Name:     req.GetName(),
+		Stock:    int(req.GetStock()),
+		Price:    req.GetPrice(),
+		Currency: req.GetCurrency(),
+		ImageURL: req.GetImageUrl(),
+		Reason:   req.GetReason(),
+	}
+	if err := binding.Validator.ValidateStruct(createReq); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	item, err := s.itemService.CreateItem(&createReq)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toProtoItem(item), nil
+}
+
+func (s *Server) GetItem(ctx context.Context, req *inventorypb.GetItemRequest) (*inventorypb.Item, error) {
+	if err := validateID(req.GetId()); err != nil {
+		return nil, err
+	}
+
+	item, err := s.itemService.GetItem(req.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toProtoItem(item), nil
+}
+
+func (s *Server) UpdateItem(ctx context.Context, req *inventorypb.UpdateItemRequest) (*inventorypb.Item, error) {
+	if s.readOnly {
+		return nil, toStatus(utils.ErrReadOnly)
+	}
+	if err := validateID(req.GetId()); err != nil {
+		return nil, err
+	}
+
+	updateReq := models.UpdateItemRequest{
+		Name:     req.Name,
+		Price:    req.Price,
+		Currency: req.Currency,
+		ImageURL: req.ImageUrl,
+		Reason:   req.GetReason(),
+	}
+	if req.Stock != nil {
+		stock := int(req.GetStock())
+		updateReq.Stock = &stock
+	}
+	if err := binding.Validator.ValidateStruct(updateReq); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	item, err := s.itemService.UpdateItem(req.GetId(), &updateReq)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toProtoItem(item), nil
+}
+
+func (s *Server) DeleteItem(ctx context.Context, req *inventorypb.DeleteItemRequest) (*inventorypb.DeleteItemResponse, error) {
+	if s.readOnly {
+		return nil, toStatus(utils.ErrReadOnly)
+	}
+	if err := validateID(req.GetId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.itemService.DeleteItem(req.GetId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &inventorypb.DeleteItemResponse{}, nil
+}
+
+func (s *Server) ListItems(ctx context.Context, req *inventorypb.ListItemsRequest) (*inventorypb.ListItemsResponse, error) {
+	pagination := models.PaginationRequest{Limit: int(req.GetLimit()), Cursor: req.GetCursor()}
+
+	filters := models.FilterRequest{
+		Name:     req.GetName(),
+		MinPrice: req.MinPrice,
+		MaxPrice: req.MaxPrice,
+	}
+	if req.MinStock != nil {
+		minStock := int(req.GetMinStock())
+		filters.MinStock = &minStock
+	}
+
+	sort := models.SortRequest{SortBy: req.GetSortBy(), SortOrder: req.GetSortOrder()}
+	if sort.SortBy == "" {
+		sort.SortBy = "created_at"
+	}
+	if sort.SortOrder == "" {
+		sort.SortOrder = "desc"
+	}
+
+	for _, r := range []interface{}{pagination, filters, sort} {
+		if err := binding.Validator.ValidateStruct(r); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	response, err := s.itemService.GetItems(&pagination, &filters, &sort, nil)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	items := make([]*inventorypb.Item, len(response.Items))
+	for i := range response.Items {
+		items[i] = toProtoItem(&response.Items[i])
+	}
+
+	return &inventorypb.ListItemsResponse{
+		Items:      items,
+		NextCursor: response.NextCursor,
+		HasMore:    response.HasMore,
+		Total:      response.Total,
+	}, nil
+}
+
+func (s *Server) GetItemStats(ctx context.Context, req *inventorypb.GetItemStatsRequest) (*inventorypb.ItemStats, error) {
+	stats, err := s.itemService.GetItemStats(nil)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	result := &inventorypb.ItemStats{}
+	result.TotalItems, _ = stats["total_items"].(int64)
+	result.AveragePrice, _ = stats["average_price"].(float64)
+	result.LowStockItems, _ = stats["low_stock_items"].(int64)
+	result.TotalValueByCurrency, _ = stats["total_value_by_currency"].(map[string]float64)
+	if totalValue, ok := stats["total_value"].(float64); ok {
+		result.TotalValue = &totalValue
+	}
+
+	return result, nil
+}
+
+func validateID(id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return status.Error(codes.InvalidArgument, "the provided ID is not a valid UUID")
+	}
+	return nil
+}
+
+// toStatus maps item service errors to gRPC status codes
+func toStatus(err error) error {
+	switch {
+	case err.Error() == "item not found":
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, utils.ErrInvalidCursor), errors.Is(err, utils.ErrInvalidSort), errors.Is(err, utils.ErrFilterTooBroad), errors.Is(err, utils.ErrValueTooLarge):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, utils.ErrDatabaseBusy), errors.Is(err, utils.ErrCircuitOpen), errors.Is(err, utils.ErrReadOnly):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		utils.Error.Printf("gRPC request failed: %v", err)
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func toProtoItem(item *models.Item) *inventorypb.Item {
+	return &inventorypb.Item{
+		Id:        item.ID.String(),
+		Name:      item.Name,
+		Stock:     int32(item.Stock),
+		Price:     item.Price,
+		Currency:  item.Currency,
+		ImageUrl:  item.ImageURL,
+		CreatedAt: timestamppb.New(item.CreatedAt),
+		UpdatedAt: timestamppb.New(item.UpdatedAt),
+	}
+}
@@ -0,0 +1,173 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"inventory-api/inventorypb"
+	"inventory-api/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+func newTestClient(t *testing.T) inventorypb.InventoryServiceClient {
+	testDB := utils.NewTestDB(t)
+	t.Cleanup(testDB.Close)
+
+	return dialTestServer(t, NewGRPCServer(utils.NewItemServiceWithDB(testDB.DB), false))
+}
+
+// dialTestServer serves server over an in-memory listener and returns a client for it
+func dialTestServer(t *testing.T, server *grpc.Server) inventorypb.InventoryServiceClient {
+	listener := bufconn.Listen(1024 * 1024)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return inventorypb.NewInventoryServiceClient(conn)
+}
+
+func TestServer_ItemLifecycle(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	created, err := client.CreateItem(ctx, &inventorypb.CreateItemRequest{Name: "Laptop", Stock: 10, Price: 999.99})
+	require.NoError(t, err)
+	assert.Equal(t, "Laptop", created.GetName())
+	assert.Equal(t, "USD", created.GetCurrency())
+
+	fetched, err := client.GetItem(ctx, &inventorypb.GetItemRequest{Id: created.GetId()})
+	require.NoError(t, err)
+	assert.Equal(t, created.GetId(), fetched.GetId())
+
+	updated, err := client.UpdateItem(ctx, &inventorypb.UpdateItemRequest{Id: created.GetId(), Stock: proto.Int32(4)})
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), updated.GetStock())
+	assert.Equal(t, "Laptop", updated.GetName())
+
+	list, err := client.ListItems(ctx, &inventorypb.ListItemsRequest{})
+	require.NoError(t, err)
+	assert.Len(t, list.GetItems(), 1)
+	assert.Equal(t, int64(1), list.GetTotal())
+
+	stats, err := client.GetItemStats(ctx, &inventorypb.GetItemStatsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"USD": 4 * 999.99}, stats.GetTotalValueByCurrency())
+
+	_, err = client.DeleteItem(ctx, &inventorypb.DeleteItemRequest{Id: created.GetId()})
+	require.NoError(t, err)
+
+	_, err = client.GetItem(ctx, &inventorypb.GetItemRequest{Id: created.GetId()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestServer_ErrorCodes(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		call     func() error
+		expected codes.Code
+	}{
+		{
+			name: "invalid id",
+			call: func() error {
+				_, err := client.GetItem(ctx, &inventorypb.GetItemRequest{Id: "not-a-uuid"})
+				return err
+			},
+			expected: codes.InvalidArgument,
+		},
+		{
+			name: "missing item",
+			call: func() error {
+				_, err := client.DeleteItem(ctx, &inventorypb.DeleteItemRequest{Id: "550e8400-e29b-41d4-a716-446655440000"})
+				return err
+			},
+			expected: codes.NotFound,
+		},
+		{
+			name: "invalid create request",
+			call: func() error {
+				_, err := client.CreateItem(ctx, &inventorypb.CreateItemRequest{Name: "", Stock: 1, Price: 1})
+				return err
+			},
+			expected: codes.InvalidArgument,
+		},
+		{
+			name: "invalid cursor",
+			call: func() error {
+				_, err := client.ListItems(ctx, &inventorypb.ListItemsRequest{Cursor: "garbage"})
+				return err
+			},
+			expected: codes.InvalidArgument,
+		},
+		{
+			name: "limit too high",
+			call: func() error {
+				_, err := client.ListItems(ctx, &inventorypb.ListItemsRequest{Limit: 500})
+				return err
+			},
+			expected: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, status.Code(tt.call()))
+		})
+	}
+}
+
+func TestServer_ReadOnly(t *testing.T) {
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	item := testDB.CreateTestItem(t, "Laptop", 10, 999.99)
+	client := dialTestServer(t, NewGRPCServer(utils.NewItemServiceWithDB(testDB.DB), true))
+	ctx := context.Background()
+
+	writes := map[string]func() error{
+		"create": func() error {
+			_, err := client.CreateItem(ctx, &inventorypb.CreateItemRequest{Name: "Mouse", Stock: 1, Price: 25.99})
+			return err
+		},
+		"update": func() error {
+			_, err := client.UpdateItem(ctx, &inventorypb.UpdateItemRequest{Id: item.ID.String(), Stock: proto.Int32(4)})
+			return err
+		},
+		"delete": func() error {
+			_, err := client.DeleteItem(ctx, &inventorypb.DeleteItemRequest{Id: item.ID.String()})
+			return err
+		},
+	}
+	for name, write := range writes {
+		err := write()
+		assert.Equal(t, codes.Unavailable, status.Code(err), name)
+		assert.Equal(t, "service is read-only", status.Convert(err).Message(), name)
+	}
+
+	fetched, err := client.GetItem(ctx, &inventorypb.GetItemRequest{Id: item.ID.String()})
+	require.NoError(t, err, "reads keep working")
+	assert.Equal(t, int32(10), fetched.GetStock())
+
+	list, err := client.ListItems(ctx, &inventorypb.ListItemsRequest{})
+	require.NoError(t, err)
+	assert.Len(t, list.GetItems(), 1)
+}
@@ -3,12 +3,16 @@ package main
 import (
 	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata"
 
+	"inventory-api/grpcserver"
+	"inventory-api/models"
 	"inventory-api/routes"
 	"inventory-api/utils"
 
@@ -43,6 +47,20 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// Timestamps are stored in UTC and rendered in the display timezone
+	displayLocation, err := time.LoadLocation(cfg.Server.DisplayTimezone)
+	if err != nil {
+		log.Fatalf("Failed to load display timezone: %v", err)
+	}
+	models.SetDisplayLocation(displayLocation)
+
+	// Name searches and stored search names share one normalization
+	searchNormalization, err := models.ParseSearchNormalization(cfg.Search.Normalization)
+	if err != nil {
+		log.Fatalf("Invalid search normalization: %v", err)
+	}
+	models.SetSearchNormalization(searchNormalization)
+
 	if os.Getenv("GIN_MODE") == "" {
 		gin.SetMode(gin.ReleaseMode)
 	}
@@ -57,19 +75,41 @@ func main() {
 		env = "development"
 	}
 
-	if env == "development" {
-		utils.Info.Println("Running AutoMigrate in development mode...")
+	// An embedded SQLite database has no separate migration step, so it is
+	// always migrated; applied migrations are skipped
+	if env == "development" || cfg.Database.Driver == utils.DriverSQLite {
+		utils.Info.Println("Running database migrations...")
 		if err := utils.Migrate(); err != nil {
 			utils.Error.Printf("Failed to migrate database: %v", err)
 		}
 	}
 
-	itemService := utils.NewItemService()
+	itemService := utils.NewItemServiceWithConfig(utils.DB, cfg)
 	if err := itemService.SeedDatabase(); err != nil {
 		utils.Error.Printf("Failed to seed database: %v", err)
 	}
+	if n, err := itemService.RefreshSearchNames(); err != nil {
+		utils.Error.Printf("Failed to refresh search names: %v", err)
+	} else if n > 0 {
+		utils.Info.Printf("Refreshed the search names of %d items", n)
+	}
+
+	// Periodically purge soft-deleted items past their retention period
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	var purgeDone <-chan struct{}
+	if cfg.Retention.PurgeInterval > 0 {
+		purgeDone = utils.StartPurgeJob(purgeCtx, itemService, cfg.Retention.PurgeInterval)
+	}
 
-	router := routes.SetupRoutes(cfg)
+	// Keep the stats snapshot served by /inventory/stats fresh
+	statsCtx, stopStats := context.WithCancel(context.Background())
+	var statsDone <-chan struct{}
+	if cfg.Cache.StatsRefreshInterval > 0 {
+		statsDone = utils.StartStatsRefreshJob(statsCtx, itemService, cfg.Cache.StatsRefreshInterval)
+	}
+
+	// REST, GraphQL and gRPC share one item service, and so one cache
+	router := routes.SetupRoutesWithService(cfg, itemService)
 
 	server := &http.Server{
 		Addr:         ":" + cfg.Server.Port,
@@ -79,6 +119,20 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	grpcServer := grpcserver.NewGRPCServer(itemService, cfg.Server.ReadOnly)
+	go func() {
+		listener, err := net.Listen("tcp", ":"+cfg.Server.GRPCPort)
+		if err != nil {
+			utils.Error.Printf("Failed to listen for gRPC: %v", err)
+			return
+		}
+
+		utils.Info.Printf("gRPC server starting on port %s", cfg.Server.GRPCPort)
+		if err := grpcServer.Serve(listener); err != nil {
+			utils.Error.Printf("Failed to start gRPC server: %v", err)
+		}
+	}()
+
 	go func() {
 		utils.Info.Printf("Server starting on port %s", cfg.Server.Port)
 		utils.Info.Printf("API Documentation available at: http://localhost:%s/api/v1/swagger/index.html", cfg.Server.Port)
@@ -95,6 +149,15 @@ func main() {
 
 	utils.Info.Println("Shutting down server...")
 
+	stopPurge()
+	if purgeDone != nil {
+		<-purgeDone
+	}
+	stopStats()
+	if statsDone != nil {
+		<-statsDone
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -102,5 +165,17 @@ func main() {
 		utils.Error.Printf("Server forced to shutdown: %v", err)
 	}
 
+	// Stop accepting RPCs and wait for in-flight ones, up to the shutdown deadline
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(grpcStopped)
+	}()
+	select {
+	case <-grpcStopped:
+	case <-ctx.Done():
+		grpcServer.Stop()
+	}
+
 	utils.Info.Println("Server exited")
 }
@@ -1,4 +1,8 @@
 -- Migration 001: Drop existing tables if they exist
--- This migration drops the items table to ensure a clean start
+-- This migration drops the items tables to ensure a clean start
 
+DROP TABLE IF EXISTS item_tags CASCADE;
+DROP TABLE IF EXISTS item_locks CASCADE;
+DROP TABLE IF EXISTS stock_movements CASCADE;
 DROP TABLE IF EXISTS items CASCADE;
+DROP TABLE IF EXISTS categories CASCADE;
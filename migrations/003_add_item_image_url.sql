@@ -0,0 +1,4 @@
+-- Migration 003: Add image_url to the items table
+-- This migration adds an optional product image URL to each item
+
+ALTER TABLE items ADD COLUMN IF NOT EXISTS image_url VARCHAR(2048);
@@ -0,0 +1,5 @@
+-- Migration 006: Add sku to the items table
+-- This migration adds an optional, unique stock keeping unit used to sync items from external systems
+
+ALTER TABLE items ADD COLUMN IF NOT EXISTS sku VARCHAR(64);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_items_sku ON items (sku);
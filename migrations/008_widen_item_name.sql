@@ -0,0 +1,4 @@
+-- Migration 008: Widen the name column of the items table
+-- Name length is now limited by MAX_NAME_LENGTH, so the column itself is unbounded
+
+ALTER TABLE items ALTER COLUMN name TYPE TEXT;
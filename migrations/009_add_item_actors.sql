@@ -0,0 +1,5 @@
+-- Migration 009: Add created_by and updated_by to the items table
+-- This migration records who created and last modified each item; existing items are attributed to "system"
+
+ALTER TABLE items ADD COLUMN IF NOT EXISTS created_by VARCHAR(255) NOT NULL DEFAULT 'system';
+ALTER TABLE items ADD COLUMN IF NOT EXISTS updated_by VARCHAR(255) NOT NULL DEFAULT 'system';
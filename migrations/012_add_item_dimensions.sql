@@ -0,0 +1,7 @@
+-- Migration 012: Add weight and dimensions to the items table
+-- This migration records the physical attributes needed for shipping calculations
+
+ALTER TABLE items ADD COLUMN IF NOT EXISTS weight_grams INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE items ADD COLUMN IF NOT EXISTS length_mm INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE items ADD COLUMN IF NOT EXISTS width_mm INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE items ADD COLUMN IF NOT EXISTS height_mm INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,18 @@
+-- Migration 013: Add a sequential item_number to the items table
+-- This migration gives every item a short number for systems that cannot handle UUIDs; the UUID stays the primary key
+
+CREATE SEQUENCE IF NOT EXISTS item_number_seq;
+ALTER TABLE items ADD COLUMN IF NOT EXISTS item_number BIGINT;
+ALTER SEQUENCE item_number_seq OWNED BY items.item_number;
+
+-- Number existing items in the order they were created
+UPDATE items SET item_number = numbered.item_number
+FROM (
+    SELECT id, nextval('item_number_seq') AS item_number
+    FROM (SELECT id FROM items WHERE item_number IS NULL ORDER BY created_at, id) AS ordered
+) AS numbered
+WHERE items.id = numbered.id;
+
+ALTER TABLE items ALTER COLUMN item_number SET DEFAULT nextval('item_number_seq');
+ALTER TABLE items ALTER COLUMN item_number SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_items_item_number ON items (item_number);
@@ -0,0 +1,10 @@
+-- Migration 016: Add search_name to the items table
+-- This migration stores each name normalized for searching; name filters match it instead of the raw name
+
+ALTER TABLE items ADD COLUMN IF NOT EXISTS search_name VARCHAR(1024) NOT NULL DEFAULT '';
+
+-- Backfill with the default normalization: lowercase, strip punctuation, collapse spaces.
+-- The application brings the names in line on startup if SEARCH_NORMALIZATION differs.
+UPDATE items SET search_name = btrim(regexp_replace(regexp_replace(lower(name), '[[:punct:]]', '', 'g'), '\s+', ' ', 'g'));
+
+CREATE INDEX IF NOT EXISTS idx_items_search_name ON items (search_name);
@@ -0,0 +1,8 @@
+-- Migration 001 (SQLite): Drop existing tables if they exist
+-- This migration drops the items tables to ensure a clean start
+
+DROP TABLE IF EXISTS item_tags;
+DROP TABLE IF EXISTS item_locks;
+DROP TABLE IF EXISTS stock_movements;
+DROP TABLE IF EXISTS items;
+DROP TABLE IF EXISTS categories;
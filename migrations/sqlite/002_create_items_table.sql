@@ -0,0 +1,26 @@
+-- Migration 002 (SQLite): Create the items table
+-- This migration creates the items table; ids are generated by the application
+
+CREATE TABLE items (
+    -- id is the primary key for the table (UUID stored as text)
+    id TEXT PRIMARY KEY,
+    -- name is the name of the item
+    name VARCHAR(255) NOT NULL,
+    -- stock is the current stock level
+    stock INTEGER NOT NULL DEFAULT 0,
+    -- price is the price of the item
+    price DECIMAL(10, 2) NOT NULL,
+    -- created_at is the timestamp when the item was created
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    -- updated_at is the timestamp when the item was last updated
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    -- deleted_at is used for soft deletes
+    deleted_at DATETIME
+);
+
+-- Create indexes for performance
+CREATE INDEX IF NOT EXISTS idx_items_name ON items (name);
+CREATE INDEX IF NOT EXISTS idx_items_stock ON items (stock);
+CREATE INDEX IF NOT EXISTS idx_items_price ON items (price);
+CREATE INDEX IF NOT EXISTS idx_items_created_at ON items (created_at);
+CREATE INDEX IF NOT EXISTS idx_items_deleted_at ON items (deleted_at);
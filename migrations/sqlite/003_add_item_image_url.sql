@@ -0,0 +1,4 @@
+-- Migration 003 (SQLite): Add image_url to the items table
+-- This migration adds an optional product image URL to each item
+
+ALTER TABLE items ADD COLUMN image_url VARCHAR(2048);
@@ -0,0 +1,4 @@
+-- Migration 004 (SQLite): Add currency to the items table
+-- This migration adds an ISO 4217 currency code to each item, defaulting existing rows to USD
+
+ALTER TABLE items ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'USD';
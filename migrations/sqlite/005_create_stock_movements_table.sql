@@ -0,0 +1,19 @@
+-- Migration 005 (SQLite): Create the stock_movements table
+-- This migration adds a ledger recording every change to an item's stock
+
+CREATE TABLE IF NOT EXISTS stock_movements (
+    -- id orders movements in the sequence they were recorded
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    -- item_id is the item whose stock changed
+    item_id TEXT NOT NULL REFERENCES items (id) ON DELETE CASCADE,
+    -- delta is the signed change in stock
+    delta INTEGER NOT NULL,
+    -- reason describes why the stock changed
+    reason VARCHAR(255),
+    -- resulting_stock is the stock level after the change
+    resulting_stock INTEGER NOT NULL,
+    -- created_at is the timestamp when the change was recorded
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_movements_item_id ON stock_movements (item_id);
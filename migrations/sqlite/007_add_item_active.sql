@@ -0,0 +1,5 @@
+-- Migration 007 (SQLite): Add active to the items table
+-- This migration marks items as active or discontinued; existing items stay active
+
+ALTER TABLE items ADD COLUMN active BOOLEAN NOT NULL DEFAULT TRUE;
+CREATE INDEX IF NOT EXISTS idx_items_active ON items (active);
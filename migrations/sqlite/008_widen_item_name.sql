@@ -0,0 +1,3 @@
+-- Migration 008 (SQLite): Widen the name column of the items table
+-- SQLite does not enforce VARCHAR lengths, so the column needs no change and
+-- name length is limited by MAX_NAME_LENGTH alone
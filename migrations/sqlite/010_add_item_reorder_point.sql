@@ -0,0 +1,5 @@
+-- Migration 010 (SQLite): Add reorder_point and auto_reorder to the items table
+-- This migration lets each item set the stock level at which it counts as low on stock
+
+ALTER TABLE items ADD COLUMN reorder_point INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE items ADD COLUMN auto_reorder BOOLEAN NOT NULL DEFAULT FALSE;
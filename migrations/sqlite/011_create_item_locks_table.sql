@@ -0,0 +1,13 @@
+-- Migration 011 (SQLite): Create the item_locks table
+-- This migration adds advisory locks that editing UIs hold on an item while it is being edited
+
+CREATE TABLE IF NOT EXISTS item_locks (
+    -- item_id is the locked item; an item has at most one lock
+    item_id TEXT PRIMARY KEY REFERENCES items (id) ON DELETE CASCADE,
+    -- owner is the token identifying who holds the lock
+    owner VARCHAR(255) NOT NULL,
+    -- expires_at is when the lock lapses if it is not renewed or released
+    expires_at DATETIME NOT NULL,
+    -- created_at is the timestamp when the lock was taken
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
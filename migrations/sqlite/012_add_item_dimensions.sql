@@ -0,0 +1,7 @@
+-- Migration 012 (SQLite): Add weight and dimensions to the items table
+-- This migration records the physical attributes needed for shipping calculations
+
+ALTER TABLE items ADD COLUMN weight_grams INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE items ADD COLUMN length_mm INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE items ADD COLUMN width_mm INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE items ADD COLUMN height_mm INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,9 @@
+-- Migration 013 (SQLite): Add a sequential item_number to the items table
+-- SQLite has no sequences; the application numbers new items after the highest item_number
+
+ALTER TABLE items ADD COLUMN item_number INTEGER;
+
+-- Number existing items in the order they were inserted
+UPDATE items SET item_number = rowid;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_items_item_number ON items (item_number);
@@ -0,0 +1,20 @@
+-- Migration 014 (SQLite): Create the categories table
+-- This migration adds a category hierarchy and files items under a category
+
+CREATE TABLE IF NOT EXISTS categories (
+    -- id is the primary key for the table (UUID)
+    id TEXT PRIMARY KEY,
+    -- name is the name of the category
+    name VARCHAR(255) NOT NULL,
+    -- parent_id is the enclosing category; top level categories have none
+    parent_id TEXT REFERENCES categories (id) ON DELETE SET NULL,
+    -- created_at is the timestamp when the category was created
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    -- updated_at is the timestamp when the category was last updated
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories (parent_id);
+
+ALTER TABLE items ADD COLUMN category_id TEXT REFERENCES categories (id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_items_category_id ON items (category_id);
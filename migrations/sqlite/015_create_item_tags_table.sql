@@ -0,0 +1,14 @@
+-- Migration 015 (SQLite): Create the item_tags table
+-- This migration adds free-form tags that group items across categories
+
+CREATE TABLE IF NOT EXISTS item_tags (
+    -- item_id is the tagged item
+    item_id TEXT NOT NULL REFERENCES items (id) ON DELETE CASCADE,
+    -- tag is the tag attached to the item
+    tag VARCHAR(64) NOT NULL,
+    -- created_at is the timestamp when the tag was attached
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (item_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS idx_item_tags_tag ON item_tags (tag);
@@ -0,0 +1,8 @@
+-- Migration 016 (SQLite): Add search_name to the items table
+-- SQLite has no regular expressions, so the backfill only lowercases; the application finishes normalizing on startup
+
+ALTER TABLE items ADD COLUMN search_name VARCHAR(1024) NOT NULL DEFAULT '';
+
+UPDATE items SET search_name = trim(lower(name));
+
+CREATE INDEX IF NOT EXISTS idx_items_search_name ON items (search_name);
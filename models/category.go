@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MaxCategoryDepth is how many levels categories may nest, counting the
+// top level. It also bounds every walk along parent links, so a cycle in
+// the data cannot loop forever.
+const MaxCategoryDepth = 8
+
+// Category groups items. Categories form a hierarchy through their parent,
+// such as Electronics > Accessories; top level categories have no parent.
+type Category struct {
+	ID             uuid.UUID  `json:"id" gorm:"type:uuid;primary_key" swaggertype:"string" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	Name           string     `json:"name" gorm:"not null;size:255" example:"Accessories"`
+	ParentID       *uuid.UUID `json:"parent_id,omitempty" gorm:"type:uuid;index" swaggertype:"string" example:"550e8400-e29b-41d4-a716-446655440000"`
+	ParentCategory *Category  `json:"-" gorm:"foreignKey:ParentID"`
+	CreatedAt      time.Time  `json:"created_at" swaggertype:"string" format:"date-time"`
+	UpdatedAt      time.Time  `json:"updated_at" swaggertype:"string" format:"date-time"`
+}
+
+// TableName returns the table name for the Category model
+func (Category) TableName() string {
+	return "categories"
+}
+
+// BeforeCreate hook to generate UUID if not set
+func (c *Category) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
+
+// CreateCategoryRequest represents the request payload for creating a category
+type CreateCategoryRequest struct {
+	Name     string `json:"name" binding:"required,min=1,max=255" example:"Accessories"`
+	ParentID string `json:"parent_id,omitempty" binding:"omitempty,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+}
+
+// CategoryCount is a category with the number of active items filed
+// directly under it
+type CategoryCount struct {
+	ID       uuid.UUID `json:"id" swaggertype:"string" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	Category string    `json:"category" example:"Accessories"`
+	Count    int64     `json:"count" example:"12"`
+}
+
+// CategoryNode is a category in the item tree with the active items filed
+// directly under it and its subcategories. ItemCount and TotalStock add up
+// the whole subtree.
+type CategoryNode struct {
+	ID         uuid.UUID       `json:"id" swaggertype:"string" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	Name       string          `json:"name" example:"Accessories"`
+	ItemCount  int             `json:"item_count" example:"12"`
+	TotalStock int             `json:"total_stock" example:"340"`
+	Items      []Item          `json:"items"`
+	Children   []*CategoryNode `json:"children"`
+}
+
+// ItemTreeResponse lists the top level categories with their subcategories
+// nested inside, and the active items that are in no category
+type ItemTreeResponse struct {
+	Categories    []*CategoryNode `json:"categories"`
+	Uncategorized []Item          `json:"uncategorized"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import "math"
+
+// MinCompareItems and MaxCompareItems bound how many items can be compared at once
+const (
+	MinCompareItems = 2
+	MaxCompareItems = 5
+)
+
+// CompareItemsRequest represents the items to compare, the first being the
+// one the others are compared against
+type CompareItemsRequest struct {
+	IDs []string `json:"ids" binding:"required,min=2,max=5,unique,dive,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+}
+
+// ItemComparison is an item with its price and stock relative to the first
+// compared item; the first item has zero deltas
+type ItemComparison struct {
+	Item       Item    `json:"item"`
+	PriceDelta float64 `json:"price_delta" example:"-200.00"`
+	StockDelta int     `json:"stock_delta" example:"12"`
+}
+
+// CompareItemsResponse lists the compared items in the order they were requested
+type CompareItemsResponse struct {
+	Items []ItemComparison `json:"items"`
+}
+
+// NewItemComparison compares item against base. Prices have two decimals,
+// so the price delta is rounded to cents to drop floating point noise.
+func NewItemComparison(item, base Item) ItemComparison {
+	return ItemComparison{
+		Item:       item,
+		PriceDelta: math.Round((item.Price-base.Price)*100) / 100,
+		StockDelta: item.Stock - base.Stock,
+	}
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"encoding/xml"
+	"math"
+	"sort"
+)
+
+// CurrencyAmounts maps currency codes to an amount in that currency
+type CurrencyAmounts map[string]float64
+
+// MarshalXML renders each amount as an element with its currency as an
+// attribute, in currency order, as encoding/xml cannot render maps
+func (a CurrencyAmounts) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	currencies := make([]string, 0, len(a))
+	for currency := range a {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, currency := range currencies {
+		amount := xml.StartElement{
+			Name: xml.Name{Local: "amount"},
+			Attr: []xml.Attr{{Name: xml.Name{Local: "currency"}, Value: currency}},
+		}
+		if err := e.EncodeElement(a[currency], amount); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// Conversion is an item price converted into the currency a client asked
+// to display prices in. The item keeps its original price and currency.
+type Conversion struct {
+	Amount   float64 `json:"amount" xml:"amount" example:"919.99"`
+	Currency string  `json:"currency" xml:"currency" example:"EUR"`
+	Rate     float64 `json:"rate" xml:"rate" example:"0.92"`
+}
+
+// NewConversion converts price at rate into currency, rounding the amount
+// to cents like stored prices
+func NewConversion(price, rate float64, currency string) *Conversion {
+	return &Conversion{
+		Amount:   math.Round(price*rate*100) / 100,
+		Currency: currency,
+		Rate:     rate,
+	}
+}
+
+// IsCurrencyCode reports whether code looks like an ISO 4217 code: three
+// uppercase letters
+func IsCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,16 @@
+package models
+
+// CursorInfo describes a decoded list cursor: the item the previous page
+// ended on and its value in the sorted column when the cursor was issued.
+// Exists is false once that item has been deleted. Moved reports that the
+// item's sorted value has changed since, so later pages continue from where
+// the item used to be.
+type CursorInfo struct {
+	ID           string `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	SortBy       string `json:"sort_by" example:"created_at"`
+	SortOrder    string `json:"sort_order" example:"desc"`
+	Value        string `json:"value" example:"2024-01-15T10:30:00Z"`
+	Exists       bool   `json:"exists" example:"true"`
+	CurrentValue string `json:"current_value,omitempty" example:"2024-01-15T10:30:00Z"`
+	Moved        bool   `json:"moved" example:"false"`
+}
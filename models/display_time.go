@@ -0,0 +1,75 @@
+package models
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"time"
+)
+
+// displayLocation is the timezone created_at and updated_at are rendered in.
+// Timestamps are always stored in UTC; only responses are converted.
+var displayLocation = time.UTC
+
+// SetDisplayLocation sets the timezone timestamps are rendered in. It is
+// meant to be called once at startup, before any response is written.
+func SetDisplayLocation(loc *time.Location) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	displayLocation = loc
+}
+
+// DisplayLocation returns the timezone timestamps are rendered in
+func DisplayLocation() *time.Location {
+	return displayLocation
+}
+
+// itemResponse is an Item as it is serialized in responses. It has the
+// fields of Item but none of its methods, so marshaling it does not recurse.
+type itemResponse Item
+
+// displayItem returns a copy of item with its timestamps in the display timezone
+func displayItem(item Item) itemResponse {
+	item.CreatedAt = item.CreatedAt.In(displayLocation)
+	item.UpdatedAt = item.UpdatedAt.In(displayLocation)
+	return itemResponse(item)
+}
+
+// MarshalJSON renders created_at and updated_at in the display timezone
+func (i Item) MarshalJSON() ([]byte, error) {
+	return json.Marshal(displayItem(i))
+}
+
+// MarshalXML renders created_at and updated_at in the display timezone
+func (i Item) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = xml.Name{Local: "item"}
+	return e.EncodeElement(displayItem(i), start)
+}
+
+// MarshalJSON renders the item with its timestamps in the display timezone.
+// Without it the promoted Item.MarshalJSON would drop the warnings.
+func (r ItemResponse) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		itemResponse
+		Warnings []string `json:"warnings,omitempty"`
+	}{displayItem(r.Item), r.Warnings})
+}
+
+// categoryResponse is a Category as it is serialized in responses
+type categoryResponse Category
+
+// MarshalJSON renders created_at and updated_at in the display timezone
+func (c Category) MarshalJSON() ([]byte, error) {
+	c.CreatedAt = c.CreatedAt.In(displayLocation)
+	c.UpdatedAt = c.UpdatedAt.In(displayLocation)
+	return json.Marshal(categoryResponse(c))
+}
+
+// stockMovementResponse is a StockMovement as it is serialized in responses
+type stockMovementResponse StockMovement
+
+// MarshalJSON renders created_at in the display timezone
+func (m StockMovement) MarshalJSON() ([]byte, error) {
+	m.CreatedAt = m.CreatedAt.In(displayLocation)
+	return json.Marshal(stockMovementResponse(m))
+}
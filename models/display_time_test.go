@@ -0,0 +1,77 @@
+package models
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItem_MarshalJSON_DisplayLocation(t *testing.T) {
+	defer SetDisplayLocation(time.UTC)
+
+	stored := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	item := Item{ID: uuid.New(), Name: "Laptop", CreatedAt: stored, UpdatedAt: stored.Add(time.Hour)}
+
+	t.Run("utc by default", func(t *testing.T) {
+		data, err := json.Marshal(item)
+		require.NoError(t, err)
+
+		var fields map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &fields))
+		assert.Equal(t, "2024-01-15T10:30:00Z", fields["created_at"])
+		assert.Equal(t, "2024-01-15T11:30:00Z", fields["updated_at"])
+	})
+
+	t.Run("display timezone", func(t *testing.T) {
+		SetDisplayLocation(time.FixedZone("CET", 3600))
+
+		data, err := json.Marshal(&item)
+		require.NoError(t, err)
+
+		var fields map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &fields))
+		assert.Equal(t, "2024-01-15T11:30:00+01:00", fields["created_at"])
+		assert.Equal(t, "2024-01-15T12:30:00+01:00", fields["updated_at"])
+		assert.Equal(t, "Laptop", fields["name"])
+		assert.Equal(t, time.UTC, item.CreatedAt.Location(), "the item itself keeps UTC")
+
+		var decoded Item
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.True(t, stored.Equal(decoded.CreatedAt), "the instant is unchanged")
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		SetDisplayLocation(time.FixedZone("EST", -5*3600))
+
+		data, err := xml.Marshal(PaginatedResponse{Items: []Item{item}})
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "<items><item><id>")
+		assert.Contains(t, string(data), "<created_at>2024-01-15T05:30:00-05:00</created_at>")
+
+		data, err = xml.Marshal(item)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "<item><id>")
+	})
+
+	t.Run("item response", func(t *testing.T) {
+		SetDisplayLocation(time.FixedZone("CET", 3600))
+
+		data, err := json.Marshal(ItemResponse{Item: item, Warnings: []string{"price too high"}})
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"created_at":"2024-01-15T11:30:00+01:00"`)
+		assert.Contains(t, string(data), `"warnings":["price too high"]`)
+	})
+
+	t.Run("stock movement", func(t *testing.T) {
+		SetDisplayLocation(time.FixedZone("CET", 3600))
+
+		data, err := json.Marshal(StockMovement{ItemID: item.ID, Delta: 1, CreatedAt: stored})
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"created_at":"2024-01-15T11:30:00+01:00"`)
+	})
+}
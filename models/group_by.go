@@ -0,0 +1,59 @@
+package models
+
+import "fmt"
+
+// GroupFields lists the fields items can be grouped by. It is the only list
+// of groupable fields: the groupfield binding rule and the service both
+// read it.
+var GroupFields = []string{"category", "currency", "price_range"}
+
+// IsGroupField reports whether items can be grouped by field
+func IsGroupField(field string) bool {
+	for _, f := range GroupFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxGroupedItems is the most items grouped in one response; counts are
+// not limited
+const MaxGroupedItems = 1000
+
+// UncategorizedGroup is the group of items that are in no category
+const UncategorizedGroup = "uncategorized"
+
+// PriceRangeBounds are the upper bounds of the price ranges items are
+// grouped into; prices at or above the last bound share an open range
+var PriceRangeBounds = []float64{10, 50, 100, 500, 1000}
+
+// PriceRange returns the label of the price range price falls into, such
+// as "10-50" for a price from 10 up to but not including 50
+func PriceRange(price float64) string {
+	lower := 0.0
+	for _, upper := range PriceRangeBounds {
+		if price < upper {
+			return fmt.Sprintf("%g-%g", lower, upper)
+		}
+		lower = upper
+	}
+	return fmt.Sprintf("%g+", lower)
+}
+
+// GroupByRequest represents item grouping parameters
+type GroupByRequest struct {
+	Field  string `form:"field" binding:"required,groupfield" example:"category"`
+	Counts bool   `form:"counts" example:"false"`
+}
+
+// GroupedItemsResponse buckets the matching items by the value of a field.
+// Groups holds the items of each group, or Counts only their number when
+// counts were asked for. Category groups are keyed by category ID, as names
+// need not be unique, and Labels gives the name of each.
+type GroupedItemsResponse struct {
+	Field  string            `json:"field" example:"category"`
+	Groups map[string][]Item `json:"groups,omitempty"`
+	Counts map[string]int    `json:"counts,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
@@ -1,20 +1,68 @@
 package models
 
 import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// DefaultCurrency is the ISO 4217 currency used when none is given
+const DefaultCurrency = "USD"
+
+// SystemActor is recorded as the creator or last editor of an item when the
+// request does not identify who made the change
+const SystemActor = "system"
+
+// MaxActorLength is the longest actor name recorded on an item
+const MaxActorLength = 255
+
+// MaxSKULength is the longest stock keeping unit an item may carry
+const MaxSKULength = 64
+
+// DefaultMaxNameLength is the longest item name accepted unless
+// MAX_NAME_LENGTH configures another limit
+const DefaultMaxNameLength = 255
+
+// DefaultMaxStock is the highest stock accepted unless MAX_STOCK
+// configures another limit
+const DefaultMaxStock = 100000000
+
+// DefaultMaxPrice is the highest price accepted unless MAX_PRICE
+// configures another limit
+const DefaultMaxPrice = 100000000.0
+
 type Item struct {
-	ID        uuid.UUID      `json:"id" gorm:"type:uuid;primary_key" swaggertype:"string" example:"550e8400-e29b-41d4-a716-446655440000"`
-	Name      string         `json:"name" gorm:"not null;size:255" binding:"required,min=1,max=255" example:"Laptop"`
-	Stock     int            `json:"stock" gorm:"not null;default:0" binding:"required,min=0" example:"50"`
-	Price     float64        `json:"price" gorm:"not null;type:decimal(10,2)" binding:"required,min=0" example:"999.99"`
-	CreatedAt time.Time      `json:"created_at" swaggertype:"string" format:"date-time"`
-	UpdatedAt time.Time      `json:"updated_at" swaggertype:"string" format:"date-time"`
-	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index" swaggertype:"string" format:"date-time"`
+	XMLName      xml.Name       `json:"-" xml:"item" gorm:"-"`
+	ID           uuid.UUID      `json:"id" xml:"id" gorm:"type:uuid;primary_key" swaggertype:"string" example:"550e8400-e29b-41d4-a716-446655440000"`
+	ItemNumber   int64          `json:"item_number" xml:"item_number" gorm:"uniqueIndex" example:"1042"`
+	Name         string         `json:"name" xml:"name" gorm:"not null" binding:"required,min=1" example:"Laptop"`
+	// SearchName is the name normalized for searching, kept up to date by BeforeSave
+	SearchName   string         `json:"-" xml:"-" gorm:"not null;size:1024;default:'';index"`
+	Stock        int            `json:"stock" xml:"stock" gorm:"not null;default:0" binding:"required,min=0" example:"50"`
+	Price        float64        `json:"price" xml:"price" gorm:"not null;type:decimal(10,2)" binding:"required,min=0" example:"999.99"`
+	Currency     string         `json:"currency" xml:"currency" gorm:"not null;size:3;default:'USD'" example:"USD"`
+	SKU          *string        `json:"sku,omitempty" xml:"sku,omitempty" gorm:"size:64;uniqueIndex" example:"LAPTOP-15-BLK"`
+	Active       bool           `json:"active" xml:"active" gorm:"not null;default:true;index" example:"true"`
+	ReorderPoint int            `json:"reorder_point" xml:"reorder_point" gorm:"not null;default:0" example:"15"`
+	AutoReorder  bool           `json:"auto_reorder" xml:"auto_reorder" gorm:"not null;default:false" example:"false"`
+	WeightGrams  int            `json:"weight_grams" xml:"weight_grams" gorm:"not null;default:0" example:"2100"`
+	LengthMM     int            `json:"length_mm" xml:"length_mm" gorm:"not null;default:0" example:"360"`
+	WidthMM      int            `json:"width_mm" xml:"width_mm" gorm:"not null;default:0" example:"250"`
+	HeightMM     int            `json:"height_mm" xml:"height_mm" gorm:"not null;default:0" example:"20"`
+	VolumeMM3    int64          `json:"volume_mm3" xml:"volume_mm3" gorm:"-" example:"1800000"`
+	Converted    *Conversion    `json:"converted_price,omitempty" xml:"converted_price,omitempty" gorm:"-"`
+	CategoryID   *uuid.UUID     `json:"category_id,omitempty" xml:"category_id,omitempty" gorm:"type:uuid;index" swaggertype:"string" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	ImageURL     string         `json:"image_url,omitempty" xml:"image_url,omitempty" gorm:"size:2048" example:"https://cdn.example.com/images/laptop.png"`
+	CreatedBy    string         `json:"created_by" xml:"created_by" gorm:"not null;size:255;default:system" example:"jane.doe"`
+	UpdatedBy    string         `json:"updated_by" xml:"updated_by" gorm:"not null;size:255;default:system" example:"jane.doe"`
+	CreatedAt    time.Time      `json:"created_at" xml:"created_at" swaggertype:"string" format:"date-time"`
+	UpdatedAt    time.Time      `json:"updated_at" xml:"updated_at" swaggertype:"string" format:"date-time"`
+	DeletedAt    gorm.DeletedAt `json:"deleted_at,omitempty" xml:"-" gorm:"index" swaggertype:"string" format:"date-time"`
 }
 
 // TableName returns the table name for the Item model
@@ -22,28 +70,198 @@ func (Item) TableName() string {
 	return "items"
 }
 
-// BeforeCreate hook to generate UUID if not set
+// BeforeCreate hook to generate UUID and item number and default the currency and actors if not set
 func (i *Item) BeforeCreate(tx *gorm.DB) error {
 	if i.ID == uuid.Nil {
 		i.ID = uuid.New()
 	}
+	if i.ItemNumber == 0 && tx != nil {
+		number, err := nextItemNumber(tx)
+		if err != nil {
+			return fmt.Errorf("failed to assign item number: %w", err)
+		}
+		i.ItemNumber = number
+	}
+	if i.Currency == "" {
+		i.Currency = DefaultCurrency
+	}
+	if i.CreatedBy == "" {
+		i.CreatedBy = SystemActor
+	}
+	if i.UpdatedBy == "" {
+		i.UpdatedBy = i.CreatedBy
+	}
+	return nil
+}
+
+// BeforeSave hook to keep the search name in step with the name
+func (i *Item) BeforeSave(tx *gorm.DB) error {
+	i.SearchName = NormalizeSearchName(i.Name)
+	return nil
+}
+
+// lastItemNumberKey holds the number last assigned by a create statement, so
+// items created together in one batch get consecutive numbers
+const lastItemNumberKey = "item_number:last"
+
+// nextItemNumber returns the next sequential item number. PostgreSQL draws it
+// from the item_number_seq sequence; SQLite has no sequences, so the number
+// follows the highest one assigned so far.
+func nextItemNumber(tx *gorm.DB) (int64, error) {
+	db := tx.Session(&gorm.Session{NewDB: true})
+
+	var next int64
+	if tx.Dialector.Name() == "postgres" {
+		err := db.Raw("SELECT nextval('item_number_seq')").Scan(&next).Error
+		return next, err
+	}
+
+	// Hooks for every item of a batch share the statement's settings
+	if last, ok := tx.Statement.Settings.Load(lastItemNumberKey); ok {
+		next = last.(int64) + 1
+	} else if err := db.Raw("SELECT COALESCE(MAX(item_number), 0) + 1 FROM items").Scan(&next).Error; err != nil {
+		return 0, err
+	}
+	tx.Statement.Settings.Store(lastItemNumberKey, next)
+
+	return next, nil
+}
+
+// Volume returns the item's volume in cubic millimetres from its dimensions.
+// It is reported as volume_mm3 but never stored.
+func (i *Item) Volume() int64 {
+	return int64(i.LengthMM) * int64(i.WidthMM) * int64(i.HeightMM)
+}
+
+// AfterFind hook to derive the volume of a loaded item
+func (i *Item) AfterFind(tx *gorm.DB) error {
+	i.VolumeMM3 = i.Volume()
+	return nil
+}
+
+// AfterSave hook to derive the volume of a created or updated item
+func (i *Item) AfterSave(tx *gorm.DB) error {
+	i.VolumeMM3 = i.Volume()
 	return nil
 }
 
 // CreateItemRequest represents the request payload for creating an item
 type CreateItemRequest struct {
-	Name  string  `json:"name" binding:"required,min=1,max=255" example:"Laptop"`
+	Name  string  `json:"name" binding:"required,min=1" example:"Laptop"`
 	Stock int     `json:"stock" binding:"required,min=0" example:"50"`
 	Price float64 `json:"price" binding:"required,min=0" example:"999.99"`
+	Currency string `json:"currency,omitempty" binding:"omitempty,oneof=USD EUR GBP JPY CHF CAD AUD CNY" example:"USD"`
+	ImageURL string `json:"image_url,omitempty" binding:"omitempty,max=2048,http_url" example:"https://cdn.example.com/images/laptop.png"`
+	ReorderPoint int  `json:"reorder_point,omitempty" binding:"omitempty,min=0" example:"15"`
+	AutoReorder  bool `json:"auto_reorder,omitempty" example:"false"`
+	WeightGrams int `json:"weight_grams,omitempty" binding:"omitempty,min=0" example:"2100"`
+	LengthMM    int `json:"length_mm,omitempty" binding:"omitempty,min=0" example:"360"`
+	WidthMM     int `json:"width_mm,omitempty" binding:"omitempty,min=0" example:"250"`
+	HeightMM    int `json:"height_mm,omitempty" binding:"omitempty,min=0" example:"20"`
+	CategoryID string `json:"category_id,omitempty" binding:"omitempty,uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	Reason   string `json:"reason,omitempty" binding:"omitempty,max=255" example:"initial delivery"`
+	// Actor is who is creating the item, taken from the request rather than the body
+	Actor string `json:"-"`
+}
+
+// ValidateItemsRequest represents a list of items to validate without creating them
+type ValidateItemsRequest struct {
+	Items []CreateItemRequest `json:"items" binding:"required,min=1,max=1000"`
+}
+
+// RowValidation reports the validation result of a single item by its position in the request
+type RowValidation struct {
+	Row    int      `json:"row" example:"0"`
+	Valid  bool     `json:"valid" example:"false"`
+	Errors []string `json:"errors,omitempty" example:"price must be at least 0"`
+}
+
+// ValidateItemsResponse represents the per-row results of a validation run
+type ValidateItemsResponse struct {
+	Results []RowValidation `json:"results"`
+	Valid   int             `json:"valid" example:"9"`
+	Invalid int             `json:"invalid" example:"1"`
+}
+
+// ItemResponse is an item returned from a write, with any soft validation
+// warnings that did not prevent the write
+type ItemResponse struct {
+	Item
+	Warnings []string `json:"warnings,omitempty" example:"price 99999.99 exceeds the reasonable maximum of 10000.00"`
 }
 
 // UpdateItemRequest represents the request payload for updating an item
 type UpdateItemRequest struct {
-	Name  *string  `json:"name,omitempty" binding:"omitempty,min=1,max=255" example:"Updated Laptop"`
+	Name  *string  `json:"name,omitempty" binding:"omitempty,min=1" example:"Updated Laptop"`
 	Stock *int     `json:"stock,omitempty" binding:"omitempty,min=0" example:"75"`
 	Price *float64 `json:"price,omitempty" binding:"omitempty,min=0" example:"1099.99"`
+	Currency *string `json:"currency,omitempty" binding:"omitempty,oneof=USD EUR GBP JPY CHF CAD AUD CNY" example:"EUR"`
+	ImageURL *string `json:"image_url,omitempty" binding:"omitempty,max=2048,http_url" example:"https://cdn.example.com/images/laptop-v2.png"`
+	Active   *bool   `json:"active,omitempty" example:"false"`
+	ReorderPoint *int  `json:"reorder_point,omitempty" binding:"omitempty,min=0" example:"20"`
+	AutoReorder  *bool `json:"auto_reorder,omitempty" example:"true"`
+	WeightGrams *int `json:"weight_grams,omitempty" binding:"omitempty,min=0" example:"2300"`
+	LengthMM    *int `json:"length_mm,omitempty" binding:"omitempty,min=0" example:"380"`
+	WidthMM     *int `json:"width_mm,omitempty" binding:"omitempty,min=0" example:"260"`
+	HeightMM    *int `json:"height_mm,omitempty" binding:"omitempty,min=0" example:"25"`
+	CategoryID *string `json:"category_id,omitempty" binding:"omitempty,uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	Reason   string  `json:"reason,omitempty" binding:"omitempty,max=255" example:"stock count correction"`
+	// Actor is who is making the change, taken from the request rather than the body
+	Actor string `json:"-"`
+	// LockOwner is the owner token of the lock the caller holds on the item, if any
+	LockOwner string `json:"-"`
+	// UnmodifiedSince rejects the update if the item changed after it; zero skips the check
+	UnmodifiedSince time.Time `json:"-"`
+	// ClearImageURL removes the item's image, set when a merge patch nulls image_url
+	ClearImageURL bool `json:"-"`
+	// ClearCategory takes the item out of its category, set when a merge patch nulls category_id
+	ClearCategory bool `json:"-"`
+}
+
+// mergePatchNullable lists the members a merge patch may set to null. Reason
+// is not an item field, so null simply means no reason was given.
+var mergePatchNullable = map[string]func(req *UpdateItemRequest){
+	"image_url":   func(req *UpdateItemRequest) { req.ClearImageURL = true },
+	"category_id": func(req *UpdateItemRequest) { req.ClearCategory = true },
+	"reason":      func(req *UpdateItemRequest) {},
+}
+
+// DecodeItemMergePatch decodes an RFC 7386 JSON merge patch into an update.
+// Members absent from the patch leave the item untouched, while a null member
+// clears a nullable field; nulling a required field is an error.
+func DecodeItemMergePatch(data []byte) (*UpdateItemRequest, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil || members == nil {
+		return nil, fmt.Errorf("merge patch must be a JSON object")
+	}
+
+	req := &UpdateItemRequest{}
+	for name, value := range members {
+		if string(value) != "null" {
+			continue
+		}
+		applyNull, ok := mergePatchNullable[name]
+		if !ok {
+			return nil, fmt.Errorf("%s cannot be null", name)
+		}
+		applyNull(req)
+		delete(members, name)
+	}
+
+	remaining, err := json.Marshal(members)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(remaining, req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
 }
 
+// MaxPageLimit is the largest page size a client may request
+const MaxPageLimit = 100
+
 // PaginationRequest represents pagination parameters
 type PaginationRequest struct {
 	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100" example:"10"`
@@ -52,29 +270,258 @@ type PaginationRequest struct {
 
 // FilterRequest represents filtering parameters
 type FilterRequest struct {
-	Name      string `form:"name" example:"laptop"`
-	MinStock  *int   `form:"min_stock" binding:"omitempty,min=0" example:"10"`
-	MinPrice  *float64 `form:"min_price" binding:"omitempty,min=0" example:"100.0"`
-	MaxPrice  *float64 `form:"max_price" binding:"omitempty,min=0" example:"2000.0"`
+	Name      string `form:"name" json:"name,omitempty" example:"laptop"`
+	MinStock  *int   `form:"min_stock" json:"min_stock,omitempty" binding:"omitempty,min=0" example:"10"`
+	MinPrice  *float64 `form:"min_price" json:"min_price,omitempty" binding:"omitempty,min=0" example:"100.0"`
+	MaxPrice  *float64 `form:"max_price" json:"max_price,omitempty" binding:"omitempty,min=0" example:"2000.0"`
+	PriceEq   *float64 `form:"price_eq" json:"price_eq,omitempty" binding:"omitempty,min=0,excluded_with=MinPrice MaxPrice" example:"9.99"`
+	Active    *bool    `form:"active" json:"active,omitempty" example:"true"`
+	IncludeInactive bool `form:"include_inactive" json:"include_inactive,omitempty" example:"false"`
+	// CreatedAfter and CreatedBefore bound the creation time, the first
+	// inclusively and the second exclusively
+	CreatedAfter  *time.Time `form:"created_after" json:"created_after,omitempty" example:"2026-10-01T00:00:00Z"`
+	CreatedBefore *time.Time `form:"created_before" json:"created_before,omitempty" example:"2026-11-01T00:00:00Z"`
+	// Uncategorized matches only the items filed under no category
+	Uncategorized bool `form:"uncategorized" json:"uncategorized,omitempty" example:"false"`
+}
+
+// Complexity estimates how expensive the filter is to run and explains what
+// drives the cost. A name search matches anywhere in the name, so it cannot
+// use the name index and costs the most; a range open at one end costs more
+// than a bounded price range or an exact price, which cost nothing.
+func (f *FilterRequest) Complexity() (int, []string) {
+	complexity := 0
+	var reasons []string
+
+	if f.Name != "" {
+		complexity += 3
+		reasons = append(reasons, "name matches anywhere in the item name and cannot use an index")
+	}
+	if f.MinStock != nil {
+		complexity++
+		reasons = append(reasons, "min_stock has no upper bound")
+	}
+	if (f.MinPrice == nil) != (f.MaxPrice == nil) {
+		complexity++
+		reasons = append(reasons, "the price range is open at one end")
+	}
+
+	return complexity, reasons
+}
+
+// IsEmpty reports whether the filter narrows nothing beyond the default of
+// matching only active items
+func (f *FilterRequest) IsEmpty() bool {
+	return f.Name == "" && f.MinStock == nil && f.MinPrice == nil && f.MaxPrice == nil && f.PriceEq == nil && f.Active == nil &&
+		f.CreatedAfter == nil && f.CreatedBefore == nil && !f.Uncategorized
+}
+
+// NamedFilter pairs a filter with the name its result is reported under
+type NamedFilter struct {
+	Name   string        `json:"name" binding:"required,min=1,max=100" example:"low_stock"`
+	Filter FilterRequest `json:"filter"`
+}
+
+// BatchStatsRequest represents the request payload for batch filtered counts
+type BatchStatsRequest struct {
+	Filters []NamedFilter `json:"filters" binding:"required,min=1,max=20,dive"`
+}
+
+// IDStatsRequest represents the request payload for stats over a set of items
+type IDStatsRequest struct {
+	IDs []string `json:"ids" binding:"required,min=1,max=100,dive,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+}
+
+// IDStatsResponse aggregates the items matching an IDStatsRequest.
+// IDs that do not exist are not counted. TotalValue is null when the
+// items use more than one currency.
+type IDStatsResponse struct {
+	Count                int64              `json:"count" example:"2"`
+	TotalValue           *float64           `json:"total_value" example:"2049.97"`
+	TotalValueByCurrency map[string]float64 `json:"total_value_by_currency"`
+	TotalStock           int64              `json:"total_stock" example:"75"`
+}
+
+// InventoryValueResponse represents the total value of the items matching a
+// filter in each currency. TotalValue is null when they use more than one.
+type InventoryValueResponse struct {
+	TotalValue           *float64           `json:"total_value" example:"15499.50"`
+	TotalValueByCurrency map[string]float64 `json:"total_value_by_currency"`
+	ItemCount            int64              `json:"item_count" example:"12"`
+}
+
+// ListAggregate totals every item matching a listing's filters, across all
+// pages rather than only the one returned. Value and price are given per
+// currency; TotalValue and AvgPrice are null when the items use more than one.
+type ListAggregate struct {
+	TotalValue           *float64        `json:"total_value" xml:"total_value,omitempty" example:"15499.50"`
+	TotalValueByCurrency CurrencyAmounts `json:"total_value_by_currency" xml:"total_value_by_currency" swaggertype:"object,number"`
+	TotalStock           int64           `json:"total_stock" xml:"total_stock" example:"340"`
+	AvgPrice             *float64        `json:"avg_price" xml:"avg_price,omitempty" example:"129.99"`
+	AvgPriceByCurrency   CurrencyAmounts `json:"avg_price_by_currency" xml:"avg_price_by_currency" swaggertype:"object,number"`
+}
+
+// SortColumns maps each field items can be sorted by to the column it
+// orders on. It is the only list of sortable fields: the sortfield binding
+// rule and the query builder both read it, so a field that is not listed
+// here can never reach an ORDER BY clause.
+var SortColumns = map[string]string{
+	"name":       "name",
+	"stock":      "stock",
+	"price":      "price",
+	"created_at": "created_at",
 }
 
 // SortRequest represents sorting parameters
 type SortRequest struct {
-	SortBy    string `form:"sort_by" binding:"omitempty,oneof=name stock price created_at" example:"name"`
+	SortBy    string `form:"sort_by" binding:"omitempty,sortfield" example:"name"`
 	SortOrder string `form:"sort_order" binding:"omitempty,oneof=asc desc" example:"asc"`
 }
 
+// SortColumn returns the column to order by for a sortable field, reporting
+// false for any field not in SortColumns
+func SortColumn(field string) (string, bool) {
+	column, ok := SortColumns[field]
+	return column, ok
+}
+
+// SuggestRequest represents name autocomplete parameters
+type SuggestRequest struct {
+	Query string `form:"q" binding:"required,min=1,max=255" example:"lap"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=20" example:"5"`
+}
+
+// DefaultSampleSize is how many items a random sample has when n is not given
+const DefaultSampleSize = 5
+
+// MaxSampleSize is the largest random sample a client may request
+const MaxSampleSize = 100
+
+// SampleRequest represents random sample parameters
+type SampleRequest struct {
+	N *int `form:"n" binding:"omitempty,min=1,max=100" example:"5"`
+}
+
+// DuplicateGroup is a set of items whose names match once lowercased and
+// trimmed, oldest first
+type DuplicateGroup struct {
+	NormalizedName string `json:"normalized_name" example:"usb cable"`
+	Items          []Item `json:"items"`
+}
+
+// DuplicatesResponse lists the groups of likely duplicate items
+type DuplicatesResponse struct {
+	Groups []DuplicateGroup `json:"groups"`
+}
+
+// ItemColumns lists the stored item columns that can be requested with the fields parameter
+var ItemColumns = []string{"id", "item_number", "name", "stock", "price", "currency", "sku", "active", "reorder_point", "auto_reorder", "weight_grams", "length_mm", "width_mm", "height_mm", "category_id", "image_url", "created_by", "updated_by", "created_at", "updated_at"}
+
+// ComputedItemFields maps the fields derived when an item is loaded, which
+// have no column of their own, to the columns they are computed from
+var ComputedItemFields = map[string][]string{
+	"volume_mm3": {"length_mm", "width_mm", "height_mm"},
+}
+
+// ItemFields lists every field that can be requested with the fields parameter
+var ItemFields = append(append([]string{}, ItemColumns...), "volume_mm3")
+
+// FieldsRequest represents response shaping parameters
+type FieldsRequest struct {
+	Fields string `form:"fields" example:"id,name,stock"`
+}
+
+// Parse returns the requested field names, or nil when all fields are wanted.
+// It returns an error for fields not listed in ItemFields.
+func (r FieldsRequest) Parse() ([]string, error) {
+	if strings.TrimSpace(r.Fields) == "" {
+		return nil, nil
+	}
+
+	known := make(map[string]bool, len(ItemFields))
+	for _, field := range ItemFields {
+		known[field] = true
+	}
+
+	var fields []string
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(r.Fields, ",") {
+		field = strings.TrimSpace(field)
+		if !known[field] {
+			return nil, fmt.Errorf("unknown field %q", field)
+		}
+		if !seen[field] {
+			seen[field] = true
+			fields = append(fields, field)
+		}
+	}
+
+	return fields, nil
+}
+
+// ProjectItems converts items to JSON objects holding only the given fields
+func ProjectItems(items []Item, fields []string) ([]map[string]interface{}, error) {
+	projected := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+
+		var full map[string]interface{}
+		if err := json.Unmarshal(data, &full); err != nil {
+			return nil, err
+		}
+
+		partial := make(map[string]interface{}, len(fields))
+		for _, field := range fields {
+			if value, ok := full[field]; ok {
+				partial[field] = value
+			}
+		}
+		projected = append(projected, partial)
+	}
+
+	return projected, nil
+}
+
+// PartialPaginatedResponse represents a paginated response limited to requested fields
+type PartialPaginatedResponse struct {
+	Items      []map[string]interface{} `json:"items"`
+	NextCursor string                   `json:"next_cursor,omitempty"`
+	HasMore    bool                     `json:"has_more"`
+	Total      int64                    `json:"total,omitempty"`
+	Aggregate  *ListAggregate           `json:"aggregate,omitempty"`
+}
+
 // PaginatedResponse represents a paginated response
 type PaginatedResponse struct {
-	Items      []Item `json:"items"`
-	NextCursor string `json:"next_cursor,omitempty"`
-	HasMore    bool   `json:"has_more"`
-	Total      int64  `json:"total,omitempty"`
+	XMLName    xml.Name `json:"-" xml:"page"`
+	Items      []Item   `json:"items" xml:"items>item"`
+	NextCursor string   `json:"next_cursor,omitempty" xml:"next_cursor,omitempty"`
+	HasMore    bool     `json:"has_more" xml:"has_more"`
+	Total      int64    `json:"total,omitempty" xml:"total,omitempty"`
+	Aggregate  *ListAggregate `json:"aggregate,omitempty" xml:"aggregate,omitempty"`
+}
+
+// StockResponse is the availability of an item, without the rest of it.
+// Nothing is reserved yet, so Available always equals Stock.
+type StockResponse struct {
+	ID        uuid.UUID `json:"id" swaggertype:"string" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Stock     int       `json:"stock" example:"50"`
+	Available int       `json:"available" example:"50"`
+}
+
+// DeleteResponse confirms a deletion when the client asks for a response body
+type DeleteResponse struct {
+	Deleted bool   `json:"deleted" example:"true"`
+	ID      string `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
 }
 
 // ErrorResponse represents an error response
 type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message,omitempty"`
-	Code    int    `json:"code,omitempty"`
+	Error     string `json:"error"`
+	Message   string `json:"message,omitempty"`
+	Code      int    `json:"code,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
 }
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Bounds on how long an item lock is held before it expires
+const (
+	DefaultLockTTL = 5 * time.Minute
+	MaxLockTTL     = time.Hour
+)
+
+// ItemLock is an advisory lock on an item, held by an owner until it is
+// released or expires. Only updates by the owner are accepted while it is held.
+type ItemLock struct {
+	ItemID    uuid.UUID `json:"item_id" gorm:"type:uuid;primaryKey" swaggertype:"string" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Owner     string    `json:"owner" gorm:"size:255;not null" example:"3f2b8c1e-editor-session"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null" swaggertype:"string" format:"date-time"`
+	CreatedAt time.Time `json:"created_at" swaggertype:"string" format:"date-time"`
+}
+
+// TableName returns the table name for the ItemLock model
+func (ItemLock) TableName() string {
+	return "item_locks"
+}
+
+// LockRequest represents the request payload for locking an item
+type LockRequest struct {
+	Owner      string `json:"owner" binding:"required,min=1,max=255" example:"3f2b8c1e-editor-session"`
+	TTLSeconds int    `json:"ttl_seconds,omitempty" binding:"omitempty,min=1,max=3600" example:"300"`
+}
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FieldRule describes how a request field is validated. Min and Max bound
+// numbers, MinLength and MaxLength bound the length of strings.
+type FieldRule struct {
+	Type      string   `json:"type" example:"string"`
+	Required  bool     `json:"required" example:"true"`
+	Min       *float64 `json:"min,omitempty" example:"0"`
+	Max       *float64 `json:"max,omitempty" example:"100000000"`
+	MinLength *int     `json:"min_length,omitempty" example:"1"`
+	MaxLength *int     `json:"max_length,omitempty" example:"255"`
+	Enum      []string `json:"enum,omitempty" example:"USD"`
+	Format    string   `json:"format,omitempty" example:"uri"`
+}
+
+// ItemSchemaResponse lists the validation rules of the item create and
+// update requests, keyed by JSON field name
+type ItemSchemaResponse struct {
+	Create map[string]FieldRule `json:"create"`
+	Update map[string]FieldRule `json:"update"`
+}
+
+// bindingFormats maps the binding tags that constrain a string's format to
+// the name the schema reports them under
+var bindingFormats = map[string]string{
+	"http_url": "uri",
+	"uuid":     "uuid",
+}
+
+// RequestRules derives the rules of the JSON fields of the struct req
+// points to from their binding tags. Fields hidden from JSON are skipped.
+func RequestRules(req interface{}) map[string]FieldRule {
+	t := reflect.TypeOf(req)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	rules := make(map[string]FieldRule, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		kind := field.Type.Kind()
+		if kind == reflect.Ptr {
+			kind = field.Type.Elem().Kind()
+		}
+		rule := FieldRule{Type: schemaType(kind)}
+
+		for _, tag := range strings.Split(field.Tag.Get("binding"), ",") {
+			key, value, _ := strings.Cut(tag, "=")
+			switch key {
+			case "required":
+				rule.Required = true
+			case "min", "max":
+				rule.setBound(key, value)
+			case "oneof":
+				rule.Enum = strings.Fields(value)
+			default:
+				if format, ok := bindingFormats[key]; ok {
+					rule.Format = format
+				}
+			}
+		}
+
+		rules[name] = rule
+	}
+
+	return rules
+}
+
+// setBound records a min or max binding tag, which bounds the length of
+// strings and the value of numbers
+func (r *FieldRule) setBound(key, value string) {
+	bound, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return
+	}
+
+	if r.Type == "string" {
+		length := int(bound)
+		if key == "min" {
+			r.MinLength = &length
+		} else {
+			r.MaxLength = &length
+		}
+		return
+	}
+
+	if key == "min" {
+		r.Min = &bound
+	} else {
+		r.Max = &bound
+	}
+}
+
+// schemaType names kind the way JSON Schema does
+func schemaType(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Struct, reflect.Map:
+		return "object"
+	default:
+		return "string"
+	}
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxTagLength is the longest tag an item may carry
+const MaxTagLength = 64
+
+// ItemTag attaches a free-form tag to an item. An item carries each tag at
+// most once.
+type ItemTag struct {
+	ItemID    uuid.UUID `json:"item_id" gorm:"type:uuid;primaryKey" swaggertype:"string" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Tag       string    `json:"tag" gorm:"size:64;primaryKey" example:"clearance"`
+	CreatedAt time.Time `json:"created_at" swaggertype:"string" format:"date-time"`
+}
+
+// TableName returns the table name for the ItemTag model
+func (ItemTag) TableName() string {
+	return "item_tags"
+}
+
+// BulkTagRequest attaches tags to every item matching a filter
+type BulkTagRequest struct {
+	Filter FilterRequest `json:"filter"`
+	Tags   []string      `json:"tags" binding:"required,min=1,max=50,unique,dive,min=1,max=64" example:"clearance"`
+}
@@ -51,6 +51,28 @@ func TestItem_BeforeCreate(t *testing.T) {
 	}
 }
 
+func TestItem_BeforeCreate_Currency(t *testing.T) {
+	item := Item{}
+	require.NoError(t, item.BeforeCreate(nil))
+	assert.Equal(t, DefaultCurrency, item.Currency)
+
+	item = Item{Currency: "EUR"}
+	require.NoError(t, item.BeforeCreate(nil))
+	assert.Equal(t, "EUR", item.Currency)
+}
+
+func TestItem_Volume(t *testing.T) {
+	item := Item{LengthMM: 360, WidthMM: 250, HeightMM: 20}
+	assert.Equal(t, int64(1800000), item.Volume())
+
+	require.NoError(t, item.AfterFind(nil))
+	assert.Equal(t, int64(1800000), item.VolumeMM3)
+
+	item.HeightMM = 0
+	require.NoError(t, item.AfterSave(nil))
+	assert.Equal(t, int64(0), item.VolumeMM3, "an item missing a dimension has no volume")
+}
+
 func TestCreateItemRequest_Validation(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -114,7 +136,7 @@ func TestCreateItemRequest_Validation(t *testing.T) {
 		{
 			name: "very long name should fail",
 			request: CreateItemRequest{
-				Name:  string(make([]byte, 256)), // 256 characters
+				Name:  string(make([]byte, DefaultMaxNameLength+1)),
 				Stock: 10,
 				Price: 99.99,
 			},
@@ -128,7 +150,7 @@ func TestCreateItemRequest_Validation(t *testing.T) {
 			// This is a simplified validation test
 			hasError := false
 			
-			if tt.request.Name == "" || len(tt.request.Name) > 255 {
+			if tt.request.Name == "" || len(tt.request.Name) > DefaultMaxNameLength {
 				hasError = true
 			}
 			if tt.request.Stock < 0 {
@@ -211,7 +233,7 @@ func TestUpdateItemRequest_Validation(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			hasError := false
 			
-			if tt.request.Name != nil && (*tt.request.Name == "" || len(*tt.request.Name) > 255) {
+			if tt.request.Name != nil && (*tt.request.Name == "" || len(*tt.request.Name) > DefaultMaxNameLength) {
 				hasError = true
 			}
 			if tt.request.Stock != nil && *tt.request.Stock < 0 {
@@ -504,6 +526,125 @@ func TestPaginatedResponse_Structure(t *testing.T) {
 	assert.Equal(t, int64(100), response.Total)
 }
 
+func TestFieldsRequest_Parse(t *testing.T) {
+	tests := []struct {
+		name     string
+		fields   string
+		expected []string
+		wantErr  bool
+	}{
+		{
+			name:     "empty returns all fields",
+			fields:   "",
+			expected: nil,
+		},
+		{
+			name:     "known fields",
+			fields:   "id,name,stock",
+			expected: []string{"id", "name", "stock"},
+		},
+		{
+			name:     "trims spaces and removes duplicates",
+			fields:   " name , name,price",
+			expected: []string{"name", "price"},
+		},
+		{
+			name:    "unknown field",
+			fields:  "id,password",
+			wantErr: true,
+		},
+		{
+			name:    "empty entry",
+			fields:  "id,,name",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := FieldsRequest{Fields: tt.fields}.Parse()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, fields)
+		})
+	}
+}
+
+func TestProjectItems(t *testing.T) {
+	items := []Item{{ID: uuid.New(), Name: "Laptop", Stock: 5, Price: 999.99}}
+
+	projected, err := ProjectItems(items, []string{"id", "name"})
+	require.NoError(t, err)
+	require.Len(t, projected, 1)
+
+	assert.Equal(t, map[string]interface{}{
+		"id":   items[0].ID.String(),
+		"name": "Laptop",
+	}, projected[0])
+}
+
+func TestDecodeItemMergePatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		patch    string
+		expected *UpdateItemRequest
+		wantErr  bool
+	}{
+		{
+			name:     "set a nullable field",
+			patch:    `{"image_url": "https://cdn.example.com/a.png"}`,
+			expected: &UpdateItemRequest{ImageURL: stringPtr("https://cdn.example.com/a.png")},
+		},
+		{
+			name:     "null clears a nullable field",
+			patch:    `{"image_url": null, "stock": 3}`,
+			expected: &UpdateItemRequest{ClearImageURL: true, Stock: intPtr(3)},
+		},
+		{
+			name:     "omitted fields are left alone",
+			patch:    `{"name": "Renamed"}`,
+			expected: &UpdateItemRequest{Name: stringPtr("Renamed")},
+		},
+		{
+			name:     "null reason is no reason",
+			patch:    `{"reason": null}`,
+			expected: &UpdateItemRequest{},
+		},
+		{
+			name:    "required field cannot be null",
+			patch:   `{"name": null}`,
+			wantErr: true,
+		},
+		{
+			name:    "patch must be an object",
+			patch:   `null`,
+			wantErr: true,
+		},
+		{
+			name:    "wrong type",
+			patch:   `{"stock": "many"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := DecodeItemMergePatch([]byte(tt.patch))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, req)
+		})
+	}
+}
+
 // Helper functions for creating pointers
 func stringPtr(s string) *string {
 	return &s
@@ -516,3 +657,43 @@ func intPtr(i int) *int {
 func float64Ptr(f float64) *float64 {
 	return &f
 }
+
+func TestFilterRequest_Complexity(t *testing.T) {
+	tests := []struct {
+		name       string
+		filter     FilterRequest
+		complexity int
+		reasons    int
+	}{
+		{name: "no filters", filter: FilterRequest{}, complexity: 0},
+		{name: "exact price", filter: FilterRequest{PriceEq: float64Ptr(9.99)}, complexity: 0},
+		{name: "bounded price range", filter: FilterRequest{MinPrice: float64Ptr(1), MaxPrice: float64Ptr(10)}, complexity: 0},
+		{name: "open price range", filter: FilterRequest{MaxPrice: float64Ptr(10)}, complexity: 1, reasons: 1},
+		{name: "min stock", filter: FilterRequest{MinStock: intPtr(5)}, complexity: 1, reasons: 1},
+		{name: "name search", filter: FilterRequest{Name: "widget"}, complexity: 3, reasons: 1},
+		{
+			name:       "name search with open ranges",
+			filter:     FilterRequest{Name: "widget", MinStock: intPtr(5), MinPrice: float64Ptr(1)},
+			complexity: 5,
+			reasons:    3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			complexity, reasons := tt.filter.Complexity()
+			assert.Equal(t, tt.complexity, complexity)
+			assert.Len(t, reasons, tt.reasons)
+		})
+	}
+}
+
+func TestFilterRequest_IsEmpty(t *testing.T) {
+	active := false
+	assert.True(t, (&FilterRequest{}).IsEmpty())
+	assert.True(t, (&FilterRequest{IncludeInactive: true}).IsEmpty())
+	assert.False(t, (&FilterRequest{Name: "widget"}).IsEmpty())
+	assert.False(t, (&FilterRequest{MinStock: intPtr(0)}).IsEmpty())
+	assert.False(t, (&FilterRequest{PriceEq: float64Ptr(9.99)}).IsEmpty())
+	assert.False(t, (&FilterRequest{Active: &active}).IsEmpty())
+}
@@ -0,0 +1,20 @@
+package models
+
+// RelatedPriceSpread is how far, as a fraction of the item's price, the
+// price of a related item may be above or below it
+const RelatedPriceSpread = 0.2
+
+// DefaultRelatedLimit is how many related items are returned when no limit
+// is asked for
+const DefaultRelatedLimit = 5
+
+// RelatedItemsRequest represents how many related items to return
+type RelatedItemsRequest struct {
+	Limit int `form:"limit" binding:"omitempty,min=1,max=20" example:"5"`
+}
+
+// RelatedItemsResponse lists the items related to an item, closest in
+// price first
+type RelatedItemsResponse struct {
+	Items []Item `json:"items"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// SchemaMigration records a migration file applied to the database
+type SchemaMigration struct {
+	Name      string    `json:"name" gorm:"primaryKey;size:255" example:"002_create_items_table.sql"`
+	AppliedAt time.Time `json:"applied_at" gorm:"not null" swaggertype:"string" format:"date-time"`
+}
+
+// TableName returns the table name for the SchemaMigration model
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Steps of the normalization applied to item names for searching,
+// selectable via SEARCH_NORMALIZATION
+const (
+	SearchLowercase        = "lowercase"
+	SearchStripPunctuation = "strip_punctuation"
+	SearchCollapseSpaces   = "collapse_spaces"
+	SearchRemoveSpaces     = "remove_spaces"
+)
+
+// DefaultSearchNormalization is the normalization used unless configured
+// otherwise
+const DefaultSearchNormalization = SearchLowercase + "," + SearchStripPunctuation + "," + SearchCollapseSpaces
+
+// SearchNormalization says which steps NormalizeSearchName applies. They
+// always run in the order of the fields, whatever order they are listed in.
+type SearchNormalization struct {
+	Lowercase        bool
+	StripPunctuation bool
+	CollapseSpaces   bool
+	RemoveSpaces     bool
+}
+
+// searchNormalization is the normalization stored search names and name
+// searches go through
+var searchNormalization, _ = ParseSearchNormalization(DefaultSearchNormalization)
+
+// ParseSearchNormalization parses a comma-separated list of normalization
+// steps, such as "lowercase,strip_punctuation,collapse_spaces". An empty
+// list leaves names as they are.
+func ParseSearchNormalization(raw string) (SearchNormalization, error) {
+	var n SearchNormalization
+	for _, step := range strings.Split(raw, ",") {
+		switch strings.TrimSpace(step) {
+		case "":
+		case SearchLowercase:
+			n.Lowercase = true
+		case SearchStripPunctuation:
+			n.StripPunctuation = true
+		case SearchCollapseSpaces:
+			n.CollapseSpaces = true
+		case SearchRemoveSpaces:
+			n.RemoveSpaces = true
+		default:
+			return SearchNormalization{}, fmt.Errorf("unknown search normalization step %q, expected %s, %s, %s or %s",
+				strings.TrimSpace(step), SearchLowercase, SearchStripPunctuation, SearchCollapseSpaces, SearchRemoveSpaces)
+		}
+	}
+	return n, nil
+}
+
+// SetSearchNormalization sets the normalization applied to item names for
+// searching. It is meant to be called once at startup; search names stored
+// under a different normalization are brought in line by the item service.
+func SetSearchNormalization(n SearchNormalization) {
+	searchNormalization = n
+}
+
+// NormalizeSearchName returns name as stored in search_name and matched by
+// name searches, so that "iPhone!" and "iphone" find the same items
+func NormalizeSearchName(name string) string {
+	n := searchNormalization
+	if n.Lowercase {
+		name = strings.ToLower(name)
+	}
+	if n.StripPunctuation {
+		name = strings.Map(func(r rune) rune {
+			if unicode.IsPunct(r) || unicode.IsSymbol(r) {
+				return -1
+			}
+			return r
+		}, name)
+	}
+	switch {
+	case n.RemoveSpaces:
+		name = strings.Join(strings.Fields(name), "")
+	case n.CollapseSpaces:
+		name = strings.Join(strings.Fields(name), " ")
+	}
+	return name
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeSearchName(t *testing.T) {
+	tests := []struct {
+		name          string
+		normalization string
+		input         string
+		expected      string
+	}{
+		{name: "default lowercases", normalization: DefaultSearchNormalization, input: "iPhone", expected: "iphone"},
+		{name: "default strips punctuation", normalization: DefaultSearchNormalization, input: "I-Phone 15 (Pro)!", expected: "iphone 15 pro"},
+		{name: "default collapses spaces", normalization: DefaultSearchNormalization, input: "  i   phone\t15 ", expected: "i phone 15"},
+		{name: "remove spaces", normalization: "lowercase,remove_spaces", input: "I Phone 15", expected: "iphone15"},
+		{name: "steps run in a fixed order", normalization: "collapse_spaces,lowercase", input: "USB  Cable", expected: "usb cable"},
+		{name: "no steps", normalization: "", input: "I-Phone  15", expected: "I-Phone  15"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer SetSearchNormalization(searchNormalization)
+
+			n, err := ParseSearchNormalization(tt.normalization)
+			require.NoError(t, err)
+			SetSearchNormalization(n)
+			assert.Equal(t, tt.expected, NormalizeSearchName(tt.input))
+		})
+	}
+}
+
+func TestParseSearchNormalization(t *testing.T) {
+	n, err := ParseSearchNormalization(" lowercase , strip_punctuation ")
+	require.NoError(t, err)
+	assert.Equal(t, SearchNormalization{Lowercase: true, StripPunctuation: true}, n)
+
+	_, err = ParseSearchNormalization("lowercase,stem")
+	assert.ErrorContains(t, err, `"stem"`)
+}
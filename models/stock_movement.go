@@ -0,0 +1,90 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Default reasons recorded when a stock change does not supply one
+const (
+	MovementReasonInitial     = "initial"
+	MovementReasonAdjustment  = "adjustment"
+	MovementReasonTransaction = "transaction"
+	MovementReasonStockSync   = "stock_sync"
+	MovementReasonMerge       = "merge"
+)
+
+// StockMovement is a ledger entry recording a single change to an item's stock
+type StockMovement struct {
+	ID             uint      `json:"id" gorm:"primaryKey" example:"1"`
+	ItemID         uuid.UUID `json:"item_id" gorm:"type:uuid;not null;index" swaggertype:"string" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Delta          int       `json:"delta" gorm:"not null" example:"-5"`
+	Reason         string    `json:"reason" gorm:"size:255" example:"customer order"`
+	ResultingStock int       `json:"resulting_stock" gorm:"not null" example:"45"`
+	CreatedAt      time.Time `json:"created_at" swaggertype:"string" format:"date-time"`
+}
+
+// PaginatedMovementsResponse is a page of stock movements, shaped like PaginatedResponse
+type PaginatedMovementsResponse struct {
+	Items      []StockMovement `json:"items"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+	HasMore    bool            `json:"has_more"`
+	Total      int64           `json:"total,omitempty"`
+}
+
+// TableName returns the table name for the StockMovement model
+func (StockMovement) TableName() string {
+	return "stock_movements"
+}
+
+// StockOperation changes the stock of a single item by delta
+type StockOperation struct {
+	ID     string `json:"id" binding:"required,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Delta  int    `json:"delta" binding:"required" example:"-2"`
+	Reason string `json:"reason,omitempty" binding:"omitempty,max=255" example:"order 1042"`
+}
+
+// TransactRequest represents a set of stock operations applied all-or-nothing
+type TransactRequest struct {
+	Operations []StockOperation `json:"operations" binding:"required,min=1,max=100,dive"`
+}
+
+// TransactResponse lists the items as they are after a successful transaction
+type TransactResponse struct {
+	Items []Item `json:"items"`
+}
+
+// MaxStockSyncEntries is the most SKUs a single stock sync may set
+const MaxStockSyncEntries = 10000
+
+// StockSyncEntry sets the stock of the item with the given SKU, as listed in a supplier feed
+type StockSyncEntry struct {
+	SKU   string `json:"sku" binding:"required,max=64" example:"LAPTOP-15-BLK"`
+	Stock *int   `json:"stock" binding:"required,min=0" example:"40"`
+}
+
+// StockSyncResponse counts the SKUs of a stock sync that matched an item and those that did not
+type StockSyncResponse struct {
+	Updated       int      `json:"updated" example:"118"`
+	Unmatched     int      `json:"unmatched" example:"2"`
+	UnmatchedSKUs []string `json:"unmatched_skus,omitempty" example:"DISCONTINUED-1"`
+}
+
+// MergeItemsRequest names the item to keep and the duplicates merged into it
+type MergeItemsRequest struct {
+	KeepID   string   `json:"keep_id" binding:"required,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	MergeIDs []string `json:"merge_ids" binding:"required,min=1,max=100,unique,dive,uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	Reason   string   `json:"reason,omitempty" binding:"omitempty,max=255" example:"duplicate catalog entries"`
+	// Actor is who is merging the items, taken from the request rather than the body
+	Actor string `json:"-"`
+	// LockOwner is the owner token of the locks the caller holds on the items, if any
+	LockOwner string `json:"-"`
+}
+
+// TransactErrorResponse reports which operation caused a transaction to roll back
+type TransactErrorResponse struct {
+	ErrorResponse
+	FailedIndex int    `json:"failed_index" example:"1"`
+	FailedID    string `json:"failed_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// init registers the custom binding rules used by the request types, so
+// they apply wherever requests are validated with gin's validator
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		// sortfield accepts only the fields listed in SortColumns
+		_ = v.RegisterValidation("sortfield", func(fl validator.FieldLevel) bool {
+			_, ok := SortColumn(fl.Field().String())
+			return ok
+		})
+		// groupfield accepts only the fields listed in GroupFields
+		_ = v.RegisterValidation("groupfield", func(fl validator.FieldLevel) bool {
+			return IsGroupField(fl.Field().String())
+		})
+	}
+}
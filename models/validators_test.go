@@ -0,0 +1,37 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortFieldRule(t *testing.T) {
+	for field := range SortColumns {
+		assert.NoError(t, binding.Validator.ValidateStruct(SortRequest{SortBy: field}), field)
+	}
+	assert.NoError(t, binding.Validator.ValidateStruct(SortRequest{}))
+
+	for _, field := range []string{"invalid_field", "stock;DROP TABLE items", "Stock", "stock "} {
+		assert.Error(t, binding.Validator.ValidateStruct(SortRequest{SortBy: field}), field)
+	}
+}
+
+func TestGroupFieldRule(t *testing.T) {
+	for _, field := range GroupFields {
+		assert.NoError(t, binding.Validator.ValidateStruct(GroupByRequest{Field: field}), field)
+	}
+
+	for _, field := range []string{"", "name", "category;DROP TABLE items", "Category"} {
+		assert.Error(t, binding.Validator.ValidateStruct(GroupByRequest{Field: field}), field)
+	}
+}
+
+func TestPriceRange(t *testing.T) {
+	assert.Equal(t, "0-10", PriceRange(0))
+	assert.Equal(t, "0-10", PriceRange(9.99))
+	assert.Equal(t, "10-50", PriceRange(10))
+	assert.Equal(t, "500-1000", PriceRange(999.99))
+	assert.Equal(t, "1000+", PriceRange(1000))
+}
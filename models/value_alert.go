@@ -0,0 +1,11 @@
+package models
+
+// ValueAlert compares the total inventory value with the configured
+// maximum. Both are in DefaultCurrency, other currencies being converted
+// at the configured exchange rates.
+type ValueAlert struct {
+	Current   float64 `json:"current" example:"1250000.00"`
+	Threshold float64 `json:"threshold" example:"1000000.00"`
+	Currency  string  `json:"currency" example:"USD"`
+	Exceeded  bool    `json:"exceeded" example:"true"`
+}
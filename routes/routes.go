@@ -7,69 +7,191 @@ import (
 	"time"
 
 	"inventory-api/controllers"
+	"inventory-api/graph"
 	"inventory-api/utils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/graph-gophers/graphql-go/relay"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-// SetupRoutes configures all application routes
+// SetupRoutes configures all application routes, served by a new item service
 func SetupRoutes(cfg *utils.Config) *gin.Engine {
+	return SetupRoutesWithService(cfg, utils.NewItemServiceWithConfig(utils.DB, cfg))
+}
+
+// SetupRoutesWithService configures all application routes, served by itemService
+func SetupRoutesWithService(cfg *utils.Config, itemService *utils.ItemService) *gin.Engine {
 	router := gin.New()
 
-	router.Use(gin.Logger())
-	router.Use(gin.Recovery())
+	if err := utils.SetTrustedProxies(router, cfg.Server.TrustedProxies); err != nil {
+		utils.Error.Printf("Invalid trusted proxies, trusting none: %v", err)
+	}
+
+	router.Use(utils.RequestLogMiddleware(gin.DefaultWriter, cfg.Server.LogSampleRate))
+	router.Use(utils.RecoveryMiddleware())
 	router.Use(utils.CORSMiddleware())
 
 	// Apply rate limiting only to API routes, not to Swagger or health endpoints
+	limiter, err := utils.NewLimiter(cfg)
+	if err != nil {
+		utils.Error.Printf("Failed to create rate limiter, falling back to in-memory: %v", err)
+		limiter = utils.NewRateLimiter(cfg.RateLimit.Requests, cfg.RateLimit.Burst)
+	}
+
+	allowlist, err := utils.NewAllowlist(cfg.RateLimit.Allowlist)
+	if err != nil {
+		utils.Error.Printf("Invalid rate limit allowlist, ignoring it: %v", err)
+	}
+
+	rateLimit := utils.RateLimitMiddleware(limiter, utils.NewKeyFunc(cfg.RateLimit.KeyStrategy, cfg.RateLimit.APIKeys), allowlist)
+	auth := utils.AuthMiddleware(cfg.Auth.APIKey)
+	// Attached to every route that writes, so READ_ONLY leaves reads untouched
+	write := utils.ReadOnlyMiddleware(cfg.Server.ReadOnly)
+	// Runs handlers making several writes in one transaction, committed only on success
+	tx := utils.TransactionMiddleware(itemService.DB())
+
 	apiGroup := router.Group("/api")
-	apiGroup.Use(utils.RateLimitMiddleware(cfg.RateLimit.Requests, cfg.RateLimit.Burst))
+	apiGroup.Use(rateLimit)
+	if cfg.RateLimit.MaxConcurrent > 0 {
+		apiGroup.Use(utils.ConcurrencyLimitMiddleware(utils.NewConcurrencyLimiter(cfg.RateLimit.MaxConcurrent), allowlist))
+	}
+	apiGroup.Use(utils.QuerySizeMiddleware(cfg.Server.MaxQueryLength, cfg.Server.MaxQueryParams))
+	apiGroup.Use(auth)
+	apiGroup.Use(utils.JSONContentTypeMiddleware())
+
+	buildInfo := utils.GetBuildInfo()
+
+	// Build information, for verifying what is deployed
+	router.GET("/version", func(c *gin.Context) {
+		c.JSON(http.StatusOK, buildInfo)
+	})
 
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
 		// Check database health
 		if err := utils.Health(); err != nil {
 			c.JSON(http.StatusServiceUnavailable, gin.H{
-				"status": "unhealthy",
-				"error":  err.Error(),
+				"status":          "unhealthy",
+				"error":           err.Error(),
+				"circuit_breaker": utils.BreakerState(),
 			})
 			return
 		}
 
+		// A lagging replica serves stale reads, but the service stays up
+		status := "healthy"
+		replica := utils.ReplicaStatus()
+		if replica != nil && replica.Status == utils.ReplicaDegraded {
+			status = "degraded"
+		}
+
 		// Get system info
 		var m runtime.MemStats
 		runtime.ReadMemStats(&m)
 
-		c.JSON(http.StatusOK, gin.H{
-			"status":    "healthy",
-			"timestamp": time.Now().UTC(),
-			"version":   "1.0.0",
+		health := gin.H{
+			"status":          status,
+			"timestamp":       time.Now().UTC(),
+			"version":         buildInfo.Version,
+			"commit":          buildInfo.Commit,
+			"build_time":      buildInfo.BuildTime,
+			"circuit_breaker": utils.BreakerState(),
 			"system": gin.H{
 				"goroutines": runtime.NumGoroutine(),
 				"memory_mb":  m.Alloc / 1024 / 1024,
 				"gc_runs":    m.NumGC,
 			},
-		})
+		}
+		if replica != nil {
+			health["replica"] = replica
+		}
+		c.JSON(http.StatusOK, health)
 	})
 
 	// Swagger documentation (no rate limiting)
 	router.GET("/api/v1/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	if cfg.Cache.Warm && !cfg.Cache.Disabled {
+		if n, err := itemService.WarmCache(cfg.Cache.WarmSize); err != nil {
+			utils.Error.Printf("Failed to warm item cache: %v", err)
+		} else {
+			utils.Info.Printf("Warmed item cache with %d items", n)
+		}
+	}
+
+	// GraphQL endpoint (with rate limiting), resolved by the same item service as REST
+	schema, err := graph.NewSchema(itemService, cfg.Server.ReadOnly)
+	if err != nil {
+		utils.Error.Printf("Failed to parse GraphQL schema: %v", err)
+	} else {
+		router.POST("/graphql", rateLimit, auth, gin.WrapH(&relay.Handler{Schema: schema}))
+	}
+
 	// API v1 routes (with rate limiting)
 	v1 := apiGroup.Group("/v1")
 	{
 		inventory := v1.Group("/inventory")
 		{
-			itemController := controllers.NewItemController()
+			itemController := controllers.NewItemControllerWithService(itemService)
 
 			inventory.GET("", itemController.GetItems)
-			inventory.POST("", itemController.CreateItem)
+			inventory.HEAD("", utils.HeadMiddleware(), itemController.GetItems)
+			inventory.POST("", write, itemController.CreateItem)
+			inventory.GET("/suggest", itemController.SuggestNames)
+			inventory.GET("/low-stock", itemController.GetLowStockItems)
+			inventory.GET("/sample", itemController.SampleItems)
+			inventory.GET("/value", itemController.GetInventoryValue)
+			inventory.GET("/export.jsonl", itemController.ExportItems)
+			inventory.GET("/tree", itemController.GetItemTree)
+			inventory.GET("/categories", itemController.GetCategoryCounts)
+			inventory.GET("/schema", itemController.GetItemSchema)
+			inventory.GET("/group-by", itemController.GroupItems)
+			inventory.GET("/duplicates", itemController.GetDuplicates)
 			inventory.GET("/stats", itemController.GetItemStats)
-			inventory.POST("/seed", itemController.SeedDatabase)
+			inventory.POST("/stats/refresh", itemController.RefreshStats)
+			inventory.POST("/stats/batch", itemController.GetBatchStats)
+			inventory.POST("/stats/for-ids", itemController.GetStatsForIDs)
+			inventory.POST("/compare", itemController.CompareItems)
+			inventory.POST("/transact", write, tx, itemController.Transact)
+			inventory.POST("/stock-sync", write, tx, itemController.SyncStock)
+			inventory.POST("/merge", write, tx, itemController.MergeItems)
+			inventory.POST("/bulk-soft-delete", write, tx, itemController.BulkSoftDelete)
+			inventory.POST("/bulk-tag", write, tx, itemController.BulkTag)
+			inventory.POST("/validate", itemController.ValidateItems)
+			inventory.PUT("/by-sku/:sku", write, itemController.UpsertItemBySKU)
+			if cfg.Server.EnableSeedEndpoint {
+				inventory.POST("/seed", write, itemController.SeedDatabase)
+			}
+			inventory.POST("/purge", utils.RequireAuth(), write, itemController.PurgeDeletedItems)
+			inventory.GET("/number/:n", itemController.GetItemByNumber)
+			inventory.GET("/cursor/decode", utils.RequireAuth(), itemController.DecodeCursor)
 			inventory.GET("/:id", itemController.GetItem)
-			inventory.PUT("/:id", itemController.UpdateItem)
-			inventory.DELETE("/:id", itemController.DeleteItem)
+			inventory.HEAD("/:id", utils.HeadMiddleware(), itemController.GetItem)
+			inventory.GET("/:id/movements", itemController.GetItemMovements)
+			inventory.GET("/:id/stock", itemController.GetItemStock)
+			inventory.GET("/:id/tags", itemController.GetItemTags)
+			inventory.GET("/:id/related", itemController.GetRelatedItems)
+			inventory.PUT("/:id", write, itemController.UpdateItem)
+			inventory.PATCH("/:id", write, itemController.PatchItem)
+			inventory.POST("/:id/clone", write, itemController.CloneItem)
+			inventory.POST("/:id/lock", write, itemController.LockItem)
+			inventory.DELETE("/:id/lock", write, itemController.UnlockItem)
+			inventory.DELETE("/:id", write, itemController.DeleteItem)
+		}
+
+		categoryController := controllers.NewCategoryControllerWithService(itemService)
+		v1.POST("/categories", write, categoryController.CreateCategory)
+
+		admin := v1.Group("/admin", utils.RequireAuth())
+		{
+			adminController := controllers.NewAdminController(utils.DB, cfg)
+			itemController := controllers.NewItemControllerWithService(itemService)
+
+			admin.GET("/migrations", adminController.GetMigrations)
+			admin.GET("/config", adminController.GetConfig)
+			admin.POST("/normalize-prices", write, itemController.NormalizePrices)
 		}
 	}
 
@@ -0,0 +1,216 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"inventory-api/models"
+	"inventory-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetupRoutes_SeedEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("CONFIG_FILE", "")
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+	previous := utils.DB
+	utils.DB = testDB.DB
+	t.Cleanup(func() { utils.DB = previous })
+
+	tests := []struct {
+		name     string
+		env      string
+		flag     string
+		expected int
+	}{
+		{name: "enabled outside production", env: "development", flag: "", expected: http.StatusOK},
+		{name: "disabled in production", env: "production", flag: "", expected: http.StatusNotFound},
+		{name: "explicitly enabled in production", env: "production", flag: "true", expected: http.StatusOK},
+		{name: "explicitly disabled", env: "development", flag: "false", expected: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ENV", tt.env)
+			t.Setenv("ENABLE_SEED_ENDPOINT", tt.flag)
+
+			cfg, err := utils.Load()
+			require.NoError(t, err)
+			router := SetupRoutes(cfg)
+
+			mounted := false
+			for _, route := range router.Routes() {
+				if route.Method == http.MethodPost && route.Path == "/api/v1/inventory/seed" {
+					mounted = true
+				}
+			}
+			assert.Equal(t, tt.expected == http.StatusOK, mounted)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/inventory/seed", nil))
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
+
+func TestSetupRoutes_Version(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("CONFIG_FILE", "")
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+	previous := utils.DB
+	utils.DB = testDB.DB
+	t.Cleanup(func() { utils.DB = previous })
+
+	cfg, err := utils.Load()
+	require.NoError(t, err)
+	router := SetupRoutes(cfg)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/version", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var info utils.BuildInfo
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &info))
+	assert.Equal(t, utils.GetBuildInfo(), info)
+	assert.Equal(t, "dev", info.Version)
+	assert.Equal(t, "unknown", info.Commit)
+	assert.Equal(t, "unknown", info.BuildTime)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var health map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &health))
+	assert.Equal(t, "dev", health["version"])
+	assert.Equal(t, "unknown", health["commit"])
+	assert.Equal(t, "unknown", health["build_time"])
+}
+
+func TestSetupRoutes_ReadOnly(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("ENV", "development")
+	t.Setenv("RATE_LIMIT_REQUESTS", "1000")
+	t.Setenv("RATE_LIMIT_BURST", "1000")
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+	previous := utils.DB
+	utils.DB = testDB.DB
+	t.Cleanup(func() { utils.DB = previous })
+
+	item := models.Item{Name: "Existing Item", Stock: 5, Price: 9.99}
+	require.NoError(t, testDB.DB.Create(&item).Error)
+	itemPath := "/api/v1/inventory/" + item.ID.String()
+
+	serve := func(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("writes are rejected and reads succeed", func(t *testing.T) {
+		t.Setenv("READ_ONLY", "true")
+		cfg, err := utils.Load()
+		require.NoError(t, err)
+		router := SetupRoutes(cfg)
+
+		writes := []struct {
+			method string
+			path   string
+			body   string
+		}{
+			{http.MethodPost, "/api/v1/inventory", `{"name":"New Item","stock":1,"price":1}`},
+			{http.MethodPut, itemPath, `{"stock":3}`},
+			{http.MethodPatch, itemPath, `{"stock":3}`},
+			{http.MethodDelete, itemPath, ""},
+			{http.MethodPost, itemPath + "/lock", ""},
+			{http.MethodPost, "/api/v1/inventory/seed", ""},
+		}
+		for _, write := range writes {
+			w := serve(router, write.method, write.path, write.body)
+			require.Equal(t, http.StatusServiceUnavailable, w.Code, "%s %s", write.method, write.path)
+
+			var response models.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, "service is read-only", response.Message)
+		}
+
+		var stored models.Item
+		require.NoError(t, testDB.DB.First(&stored, "id = ?", item.ID).Error)
+		assert.Equal(t, 5, stored.Stock, "rejected writes leave the item untouched")
+
+		assert.Equal(t, http.StatusOK, serve(router, http.MethodGet, "/api/v1/inventory", "").Code)
+		assert.Equal(t, http.StatusOK, serve(router, http.MethodGet, itemPath, "").Code)
+		assert.Equal(t, http.StatusOK, serve(router, http.MethodPost, "/api/v1/inventory/stats/for-ids",
+			`{"ids":["`+item.ID.String()+`"]}`).Code, "POST routes that only read stay available")
+	})
+
+	t.Run("writes succeed when disabled", func(t *testing.T) {
+		t.Setenv("READ_ONLY", "false")
+		cfg, err := utils.Load()
+		require.NoError(t, err)
+		router := SetupRoutes(cfg)
+
+		w := serve(router, http.MethodPost, "/api/v1/inventory", `{"name":"New Item","stock":1,"price":1}`)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+}
+
+func TestSetupRoutesWithService_Transaction(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("RATE_LIMIT_REQUESTS", "1000")
+	t.Setenv("RATE_LIMIT_BURST", "1000")
+
+	// Transactions are opened on the service's database, not the global one
+	previous := utils.DB
+	utils.DB = nil
+	t.Cleanup(func() { utils.DB = previous })
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	cfg, err := utils.Load()
+	require.NoError(t, err)
+	router := SetupRoutesWithService(cfg, utils.NewItemServiceWithDB(testDB.DB))
+
+	laptop := testDB.CreateTestItem(t, "Laptop", 5, 999.99)
+	mouse := testDB.CreateTestItem(t, "Mouse", 1, 25.99)
+
+	transact := func(body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/inventory/transact", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	stock := func(item *models.Item) int {
+		var current models.Item
+		require.NoError(t, testDB.DB.First(&current, "id = ?", item.ID).Error)
+		return current.Stock
+	}
+
+	assert.Equal(t, http.StatusOK, transact(`{"operations":[{"id":"`+laptop.ID.String()+`","delta":-2},{"id":"`+mouse.ID.String()+`","delta":3}]}`))
+	assert.Equal(t, 3, stock(laptop))
+	assert.Equal(t, 4, stock(mouse))
+
+	assert.Equal(t, http.StatusConflict, transact(`{"operations":[{"id":"`+laptop.ID.String()+`","delta":-1},{"id":"`+mouse.ID.String()+`","delta":-10}]}`))
+	assert.Equal(t, 3, stock(laptop), "a failed request leaves every item unchanged")
+	assert.Equal(t, 4, stock(mouse))
+}
@@ -2,12 +2,19 @@ package integrations
 
 import (
 	"bytes"
+	"database/sql/driver"
+	"encoding/base64"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"inventory-api/controllers"
 	"inventory-api/models"
@@ -17,6 +24,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 )
 
 func TestItemHandler_CreateItem(t *testing.T) {
@@ -110,6 +118,38 @@ func TestItemHandler_CreateItem(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			expectedError:  "Invalid request body",
 		},
+		{
+			name: "item with image url",
+			requestBody: models.CreateItemRequest{
+				Name:     "Pictured Item",
+				Stock:    10,
+				Price:    99.99,
+				ImageURL: "https://cdn.example.com/images/item.png",
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "invalid image url",
+			requestBody: models.CreateItemRequest{
+				Name:     "Test Item",
+				Stock:    10,
+				Price:    99.99,
+				ImageURL: "not a url",
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid request body",
+		},
+		{
+			name: "non-http image url",
+			requestBody: models.CreateItemRequest{
+				Name:     "Test Item",
+				Stock:    10,
+				Price:    99.99,
+				ImageURL: "ftp://cdn.example.com/images/item.png",
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid request body",
+		},
 	}
 
 	for _, tt := range tests {
@@ -295,6 +335,23 @@ func TestItemHandler_UpdateItem(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			expectedError:  "Invalid request body",
 		},
+		{
+			name:   "update image url",
+			itemID: item.ID.String(),
+			requestBody: models.UpdateItemRequest{
+				ImageURL: utils.StringPtr("https://cdn.example.com/images/updated.png"),
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "javascript image url",
+			itemID: item.ID.String(),
+			requestBody: models.UpdateItemRequest{
+				ImageURL: utils.StringPtr("javascript:alert(1)"),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid request body",
+		},
 	}
 
 	for _, tt := range tests {
@@ -640,3 +697,4988 @@ func TestItemHandler_ConcurrentRequests(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, response.Items, numRequests)
 }
+
+func TestItemHandler_HardDeleteItem(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+	router.Use(utils.AuthMiddleware("test-api-key"))
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+
+	router.DELETE("/inventory/:id", handler.DeleteItem)
+
+	liveItem := testDB.CreateTestItem(t, "Live Item", 10, 99.99)
+	softDeletedItem := testDB.CreateTestItem(t, "Soft Deleted Item", 10, 99.99)
+	require.NoError(t, testDB.DB.Delete(softDeletedItem).Error)
+	unauthorizedItem := testDB.CreateTestItem(t, "Protected Item", 10, 99.99)
+
+	tests := []struct {
+		name           string
+		itemID         string
+		query          string
+		apiKey         string
+		expectedStatus int
+		expectedError  string
+		expectedRows   int64
+	}{
+		{
+			name:           "hard delete live item",
+			itemID:         liveItem.ID.String(),
+			query:          "?hard=true",
+			apiKey:         "test-api-key",
+			expectedStatus: http.StatusNoContent,
+			expectedRows:   0,
+		},
+		{
+			name:           "hard delete soft-deleted item",
+			itemID:         softDeletedItem.ID.String(),
+			query:          "?hard=true",
+			apiKey:         "test-api-key",
+			expectedStatus: http.StatusNoContent,
+			expectedRows:   0,
+		},
+		{
+			name:           "hard delete non-existent item",
+			itemID:         uuid.New().String(),
+			query:          "?hard=true",
+			apiKey:         "test-api-key",
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "Item not found",
+		},
+		{
+			name:           "hard delete without api key",
+			itemID:         unauthorizedItem.ID.String(),
+			query:          "?hard=true",
+			expectedStatus: http.StatusUnauthorized,
+			expectedError:  "Unauthorized",
+			expectedRows:   1,
+		},
+		{
+			name:           "hard delete with wrong api key",
+			itemID:         unauthorizedItem.ID.String(),
+			query:          "?hard=true",
+			apiKey:         "wrong-key",
+			expectedStatus: http.StatusUnauthorized,
+			expectedError:  "Unauthorized",
+			expectedRows:   1,
+		},
+		{
+			name:           "default delete stays soft",
+			itemID:         unauthorizedItem.ID.String(),
+			expectedStatus: http.StatusNoContent,
+			expectedRows:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodDelete, "/inventory/"+tt.itemID+tt.query, nil)
+			if tt.apiKey != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.apiKey)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.expectedError != "" {
+				var errorResp models.ErrorResponse
+				err := json.Unmarshal(w.Body.Bytes(), &errorResp)
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedError, errorResp.Error)
+			}
+
+			if tt.expectedStatus != http.StatusNotFound {
+				var count int64
+				err := testDB.DB.Unscoped().Model(&models.Item{}).Where("id = ?", tt.itemID).Count(&count).Error
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedRows, count)
+			}
+		})
+	}
+}
+
+func TestItemHandler_GetBatchStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+
+	router.POST("/inventory/stats/batch", handler.GetBatchStats)
+
+	testDB.CreateTestItem(t, "Laptop", 5, 999.99)
+	testDB.CreateTestItem(t, "Monitor", 8, 599.99)
+	testDB.CreateTestItem(t, "Mouse", 200, 25.99)
+	testDB.CreateTestItem(t, "Keyboard", 150, 75.50)
+
+	t.Run("three named filters", func(t *testing.T) {
+		reqBody, err := json.Marshal(models.BatchStatsRequest{
+			Filters: []models.NamedFilter{
+				{Name: "over_500", Filter: models.FilterRequest{MinPrice: utils.Float64Ptr(500)}},
+				{Name: "well_stocked", Filter: models.FilterRequest{MinStock: utils.IntPtr(100)}},
+				{Name: "mid_range", Filter: models.FilterRequest{MinPrice: utils.Float64Ptr(50), MaxPrice: utils.Float64Ptr(600)}},
+			},
+		})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/inventory/stats/batch", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var counts map[string]int64
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &counts))
+		assert.Equal(t, map[string]int64{
+			"over_500":     2,
+			"well_stocked": 2,
+			"mid_range":    2,
+		}, counts)
+	})
+
+	tests := []struct {
+		name        string
+		requestBody string
+	}{
+		{
+			name:        "empty filter list",
+			requestBody: `{"filters": []}`,
+		},
+		{
+			name:        "missing filter name",
+			requestBody: `{"filters": [{"filter": {"min_stock": 1}}]}`,
+		},
+		{
+			name:        "invalid filter value",
+			requestBody: `{"filters": [{"name": "negative", "filter": {"min_stock": -1}}]}`,
+		},
+		{
+			name:        "duplicate filter names",
+			requestBody: `{"filters": [{"name": "a", "filter": {}}, {"name": "a", "filter": {}}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/inventory/stats/batch", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var errorResp models.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errorResp))
+			assert.Equal(t, "Invalid request body", errorResp.Error)
+		})
+	}
+}
+
+func TestItemHandler_ImageURLRoundTrip(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+
+	router.POST("/inventory", handler.CreateItem)
+	router.GET("/inventory/:id", handler.GetItem)
+
+	reqBody, err := json.Marshal(models.CreateItemRequest{
+		Name:     "Pictured Item",
+		Stock:    10,
+		Price:    99.99,
+		ImageURL: "https://cdn.example.com/images/item.png",
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/inventory", bytes.NewBuffer(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created models.Item
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	location := w.Header().Get("Location")
+	assert.Equal(t, "/inventory/"+created.ID.String(), location)
+
+	req = httptest.NewRequest(http.MethodGet, location, nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var fetched models.Item
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &fetched))
+	assert.Equal(t, "https://cdn.example.com/images/item.png", fetched.ImageURL)
+}
+
+func TestItemHandler_Currency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+
+	router.POST("/inventory", handler.CreateItem)
+	router.PUT("/inventory/:id", handler.UpdateItem)
+
+	tests := []struct {
+		name             string
+		requestBody      string
+		expectedStatus   int
+		expectedCurrency string
+	}{
+		{
+			name:             "defaults to USD",
+			requestBody:      `{"name": "Laptop", "stock": 5, "price": 999.99}`,
+			expectedStatus:   http.StatusCreated,
+			expectedCurrency: "USD",
+		},
+		{
+			name:             "supported currency",
+			requestBody:      `{"name": "Laptop", "stock": 5, "price": 899.99, "currency": "EUR"}`,
+			expectedStatus:   http.StatusCreated,
+			expectedCurrency: "EUR",
+		},
+		{
+			name:           "unknown currency code",
+			requestBody:    `{"name": "Laptop", "stock": 5, "price": 999.99, "currency": "XYZ"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "lowercase currency code",
+			requestBody:    `{"name": "Laptop", "stock": 5, "price": 999.99, "currency": "usd"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/inventory", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.expectedCurrency != "" {
+				var item models.Item
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &item))
+				assert.Equal(t, tt.expectedCurrency, item.Currency)
+			}
+		})
+	}
+
+	t.Run("invalid currency on update", func(t *testing.T) {
+		item := testDB.CreateTestItem(t, "Monitor", 5, 299.99)
+
+		req := httptest.NewRequest(http.MethodPut, "/inventory/"+item.ID.String(), bytes.NewBufferString(`{"currency": "DOGE"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestItemHandler_GetItemStats_MixedCurrencies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+
+	router.GET("/inventory/stats", handler.GetItemStats)
+
+	testDB.CreateTestItem(t, "Dollar Item", 10, 100.0)
+	euroItem := &models.Item{Name: "Euro Item", Stock: 2, Price: 50.0, Currency: "EUR"}
+	require.NoError(t, testDB.DB.Create(euroItem).Error)
+
+	req := httptest.NewRequest(http.MethodGet, "/inventory/stats", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var stats map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+
+	assert.Nil(t, stats["total_value"])
+	assert.Equal(t, map[string]interface{}{
+		"USD": float64(1000),
+		"EUR": float64(100),
+	}, stats["total_value_by_currency"])
+}
+
+func TestItemHandler_GetItems_InvalidCursor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+
+	router.GET("/inventory", handler.GetItems)
+
+	testDB.CreateTestItem(t, "Item 1", 10, 100.0)
+	testDB.CreateTestItem(t, "Item 2", 20, 200.0)
+
+	validCursor := fetchNextCursor(t, router)
+	encode := func(payload string) string {
+		return base64.StdEncoding.EncodeToString([]byte(payload))
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "truncated", cursor: validCursor[:len(validCursor)/2]},
+		{name: "not base64", cursor: "not*base64!"},
+		{name: "not json", cursor: encode("not json")},
+		{name: "empty id", cursor: encode(`{"id":"","sort_by":"created_at","sort_order":"desc","value":"2024-01-01T00:00:00Z"}`)},
+		{name: "invalid id", cursor: encode(`{"id":"abc","sort_by":"created_at","sort_order":"desc","value":"2024-01-01T00:00:00Z"}`)},
+		{name: "invalid created_at", cursor: encode(`{"id":"` + uuid.New().String() + `","sort_by":"created_at","sort_order":"desc","value":"yesterday"}`)},
+		{name: "unknown sort field", cursor: encode(`{"id":"` + uuid.New().String() + `","sort_by":"password","sort_order":"desc","value":"x"}`)},
+		{name: "different sort", cursor: encode(`{"id":"` + uuid.New().String() + `","sort_by":"price","sort_order":"asc","value":"1.5"}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/inventory?cursor="+url.QueryEscape(tt.cursor), nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var response models.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, "Invalid cursor", response.Error)
+		})
+	}
+}
+
+func TestItemHandler_GetItems_SignedCursor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	cfg := &utils.Config{Pagination: utils.PaginationConfig{CursorSecret: "test-secret"}}
+	handler := controllers.NewItemControllerWithService(utils.NewItemServiceWithConfig(testDB.DB, cfg))
+
+	router.GET("/inventory", handler.GetItems)
+
+	testDB.CreateTestItem(t, "Item 1", 10, 100.0)
+	testDB.CreateTestItem(t, "Item 2", 20, 200.0)
+
+	signedCursor := fetchNextCursor(t, router)
+	payload, _, found := strings.Cut(signedCursor, ".")
+	require.True(t, found, "cursor should carry a signature")
+
+	t.Run("valid signature", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/inventory?limit=1&cursor="+url.QueryEscape(signedCursor), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response models.PaginatedResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Items, 1)
+	})
+
+	for name, cursor := range map[string]string{
+		"missing signature":  payload,
+		"tampered signature": payload + ".AAAA",
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/inventory?cursor="+url.QueryEscape(cursor), nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+// fetchNextCursor requests a single item page and returns its next cursor
+func fetchNextCursor(t *testing.T, router *gin.Engine) string {
+	req := httptest.NewRequest(http.MethodGet, "/inventory?limit=1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response models.PaginatedResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.NotEmpty(t, response.NextCursor)
+	return response.NextCursor
+}
+
+func TestItemHandler_PurgeDeletedItems(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+	router.Use(utils.AuthMiddleware("test-api-key"))
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	cfg := &utils.Config{Retention: utils.RetentionConfig{SoftDeleteDays: 30}}
+	handler := controllers.NewItemControllerWithService(utils.NewItemServiceWithConfig(testDB.DB, cfg))
+
+	router.POST("/inventory/purge", utils.RequireAuth(), handler.PurgeDeletedItems)
+
+	oldItem := testDB.CreateTestItem(t, "Old Deleted Item", 10, 99.99)
+	require.NoError(t, testDB.DB.Delete(oldItem).Error)
+	require.NoError(t, testDB.DB.Unscoped().Model(oldItem).Update("deleted_at", time.Now().AddDate(0, 0, -60)).Error)
+	testDB.CreateTestItem(t, "Live Item", 10, 99.99)
+
+	t.Run("requires api key", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/inventory/purge", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("purges expired items", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/inventory/purge", nil)
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]int64
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, int64(1), response["purged"])
+
+		var count int64
+		require.NoError(t, testDB.DB.Unscoped().Model(&models.Item{}).Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+	})
+}
+
+func TestItemHandler_GetItems_Fields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+
+	router.GET("/inventory", handler.GetItems)
+
+	testDB.CreateTestItem(t, "Item 1", 10, 100.0)
+	testDB.CreateTestItem(t, "Item 2", 20, 200.0)
+
+	t.Run("returns only requested fields", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/inventory?fields=id,name,stock&limit=1", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Items      []map[string]interface{} `json:"items"`
+			NextCursor string                   `json:"next_cursor"`
+			HasMore    bool                     `json:"has_more"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Items, 1)
+
+		item := response.Items[0]
+		assert.Len(t, item, 3)
+		assert.Contains(t, item, "id")
+		assert.Contains(t, item, "name")
+		assert.Contains(t, item, "stock")
+		assert.NotContains(t, item, "price")
+		assert.NotContains(t, item, "created_at")
+		assert.NotContains(t, item, "updated_at")
+
+		// The cursor still works even though created_at was not requested
+		assert.True(t, response.HasMore)
+		assert.NotEmpty(t, response.NextCursor)
+	})
+
+	t.Run("computes volume from its dimensions", func(t *testing.T) {
+		require.NoError(t, testDB.DB.Model(&models.Item{}).Where("name = ?", "Item 1").
+			Updates(map[string]interface{}{"length_mm": 100, "width_mm": 50, "height_mm": 20}).Error)
+
+		req := httptest.NewRequest(http.MethodGet, "/inventory?fields=name,volume_mm3,category_id&sort_by=name&sort_order=asc", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response struct {
+			Items []map[string]interface{} `json:"items"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Items, 2)
+
+		// The dimensions are read to compute the volume but not returned
+		assert.Equal(t, map[string]interface{}{"name": "Item 1", "volume_mm3": float64(100000)}, response.Items[0])
+		assert.Equal(t, map[string]interface{}{"name": "Item 2", "volume_mm3": float64(0)}, response.Items[1])
+	})
+
+	t.Run("rejects unknown fields", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/inventory?fields=id,secret", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var errorResp models.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errorResp))
+		assert.Equal(t, "Invalid fields parameter", errorResp.Error)
+	})
+}
+
+func TestItemHandler_GetItemMovements(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+
+	router.POST("/inventory", handler.CreateItem)
+	router.PUT("/inventory/:id", handler.UpdateItem)
+	router.GET("/inventory/:id/movements", handler.GetItemMovements)
+
+	body, _ := json.Marshal(models.CreateItemRequest{Name: "Ledger Item", Stock: 10, Price: 5.0})
+	req := httptest.NewRequest(http.MethodPost, "/inventory", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var item models.Item
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &item))
+
+	updates := []models.UpdateItemRequest{
+		{Stock: utils.IntPtr(7), Reason: "customer order"},
+		{Name: utils.StringPtr("Renamed Ledger Item")},
+		{Stock: utils.IntPtr(12)},
+	}
+	for _, update := range updates {
+		body, _ := json.Marshal(update)
+		req := httptest.NewRequest(http.MethodPut, "/inventory/"+item.ID.String(), bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+
+	t.Run("records movements in order", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/inventory/"+item.ID.String()+"/movements", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var page models.PaginatedMovementsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		movements := page.Items
+		require.Len(t, movements, 3)
+		assert.False(t, page.HasMore)
+
+		expected := []struct {
+			delta          int
+			reason         string
+			resultingStock int
+		}{
+			{delta: 10, reason: models.MovementReasonInitial, resultingStock: 10},
+			{delta: -3, reason: "customer order", resultingStock: 7},
+			{delta: 5, reason: models.MovementReasonAdjustment, resultingStock: 12},
+		}
+		for i, movement := range movements {
+			assert.Equal(t, item.ID, movement.ItemID)
+			assert.Equal(t, expected[i].delta, movement.Delta)
+			assert.Equal(t, expected[i].reason, movement.Reason)
+			assert.Equal(t, expected[i].resultingStock, movement.ResultingStock)
+		}
+	})
+
+	t.Run("unknown item", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/inventory/"+uuid.New().String()+"/movements", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/inventory/not-a-uuid/movements", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestItemHandler_GetItems_InvalidLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+
+	router.GET("/inventory", handler.GetItems)
+
+	for _, limit := range []string{"101", "0", "-1", "abc", "1.5"} {
+		t.Run("limit="+limit, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/inventory?limit="+limit, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var errorResp models.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errorResp))
+			assert.Equal(t, "Invalid pagination parameters", errorResp.Error)
+			assert.Equal(t, "limit must be between 1 and 100", errorResp.Message)
+		})
+	}
+}
+
+func TestItemHandler_CloneItem(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+
+	router.POST("/inventory/:id/clone", handler.CloneItem)
+
+	source := testDB.CreateTestItem(t, "Laptop", 10, 999.99)
+
+	tests := []struct {
+		name           string
+		itemID         string
+		body           string
+		expectedStatus int
+		expectedName   string
+		expectedStock  int
+		expectedPrice  float64
+	}{
+		{
+			name:           "clone without overrides",
+			itemID:         source.ID.String(),
+			expectedStatus: http.StatusCreated,
+			expectedName:   "Laptop",
+			expectedStock:  10,
+			expectedPrice:  999.99,
+		},
+		{
+			name:           "clone with overrides",
+			itemID:         source.ID.String(),
+			body:           `{"name":"Laptop 16GB","price":1199.99}`,
+			expectedStatus: http.StatusCreated,
+			expectedName:   "Laptop 16GB",
+			expectedStock:  10,
+			expectedPrice:  1199.99,
+		},
+		{
+			name:           "clone non-existent item",
+			itemID:         uuid.New().String(),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "clone with invalid id",
+			itemID:         "invalid-uuid",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "clone with invalid override",
+			itemID:         source.ID.String(),
+			body:           `{"stock":-1}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/inventory/"+tt.itemID+"/clone", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.expectedStatus == http.StatusCreated {
+				var clone models.Item
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &clone))
+				assert.NotEqual(t, source.ID, clone.ID)
+				assert.Equal(t, tt.expectedName, clone.Name)
+				assert.Equal(t, tt.expectedStock, clone.Stock)
+				assert.Equal(t, tt.expectedPrice, clone.Price)
+				assert.Equal(t, source.Currency, clone.Currency)
+			}
+		})
+	}
+
+	// The source item is left untouched
+	var stored models.Item
+	require.NoError(t, testDB.DB.First(&stored, "id = ?", source.ID).Error)
+	assert.Equal(t, "Laptop", stored.Name)
+	assert.Equal(t, 999.99, stored.Price)
+}
+
+func TestItemHandler_GetItems_EmptyResult(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+
+	router.GET("/inventory", handler.GetItems)
+
+	testDB.CreateTestItem(t, "Laptop", 10, 999.99)
+
+	tests := []struct {
+		name           string
+		queryParams    string
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "no matches defaults to empty list",
+			queryParams:    "?min_stock=1000",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "no matches with empty=404",
+			queryParams:    "?min_stock=1000&empty=404",
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "No items found",
+		},
+		{
+			name:           "matches with empty=404",
+			queryParams:    "?min_stock=5&empty=404",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid empty value",
+			queryParams:    "?empty=204",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid empty parameter",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/inventory"+tt.queryParams, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.expectedError != "" {
+				var errorResp models.ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errorResp))
+				assert.Equal(t, tt.expectedError, errorResp.Error)
+			} else {
+				var response models.PaginatedResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.NotNil(t, response.Items)
+			}
+		})
+	}
+}
+
+func TestItemHandler_SuggestNames(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+
+	router.GET("/inventory/suggest", handler.SuggestNames)
+
+	for _, name := range []string{"Laptop Stand", "Laptop", "Lamp", "Laptop", "Desk Laptop", "Lap_Top"} {
+		testDB.CreateTestItem(t, name, 1, 10.0)
+	}
+
+	tests := []struct {
+		name           string
+		queryParams    string
+		expectedStatus int
+		expected       []string
+	}{
+		{
+			name:           "prefix match is anchored and distinct",
+			queryParams:    "?q=lapt",
+			expectedStatus: http.StatusOK,
+			expected:       []string{"Laptop", "Laptop Stand"},
+		},
+		{
+			name:           "limit is enforced",
+			queryParams:    "?q=la&limit=2",
+			expectedStatus: http.StatusOK,
+			expected:       []string{"Lamp", "Lap_Top"},
+		},
+		{
+			name:           "wildcards match literally",
+			queryParams:    "?q=lap_",
+			expectedStatus: http.StatusOK,
+			expected:       []string{"Lap_Top"},
+		},
+		{
+			name:           "no matches",
+			queryParams:    "?q=zzz",
+			expectedStatus: http.StatusOK,
+			expected:       []string{},
+		},
+		{
+			name:           "missing query",
+			queryParams:    "",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "blank query",
+			queryParams:    "?q=%20%20",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "limit too high",
+			queryParams:    "?q=la&limit=21",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/inventory/suggest"+tt.queryParams, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.expectedStatus == http.StatusOK {
+				var names []string
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &names))
+				assert.Equal(t, tt.expected, names)
+			}
+		})
+	}
+}
+
+func TestItemHandler_Transact(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+
+	router.POST("/inventory/transact", handler.Transact)
+
+	laptop := testDB.CreateTestItem(t, "Laptop", 10, 999.99)
+	mouse := testDB.CreateTestItem(t, "Mouse", 3, 25.99)
+
+	stockOf := func(item *models.Item) int {
+		var stored models.Item
+		require.NoError(t, testDB.DB.First(&stored, "id = ?", item.ID).Error)
+		return stored.Stock
+	}
+
+	tests := []struct {
+		name           string
+		operations     []models.StockOperation
+		expectedStatus int
+		expectedIndex  int
+		laptopStock    int
+		mouseStock     int
+	}{
+		{
+			name: "applies all operations",
+			operations: []models.StockOperation{
+				{ID: laptop.ID.String(), Delta: -2},
+				{ID: mouse.ID.String(), Delta: -1},
+			},
+			expectedStatus: http.StatusOK,
+			laptopStock:    8,
+			mouseStock:     2,
+		},
+		{
+			name: "insufficient stock rolls back everything",
+			operations: []models.StockOperation{
+				{ID: laptop.ID.String(), Delta: -5},
+				{ID: mouse.ID.String(), Delta: -3},
+			},
+			expectedStatus: http.StatusConflict,
+			expectedIndex:  1,
+			laptopStock:    8,
+			mouseStock:     2,
+		},
+		{
+			name: "missing item rolls back everything",
+			operations: []models.StockOperation{
+				{ID: mouse.ID.String(), Delta: 5},
+				{ID: uuid.New().String(), Delta: -1},
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedIndex:  1,
+			laptopStock:    8,
+			mouseStock:     2,
+		},
+		{
+			name:           "empty operations",
+			operations:     []models.StockOperation{},
+			expectedStatus: http.StatusBadRequest,
+			laptopStock:    8,
+			mouseStock:     2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(models.TransactRequest{Operations: tt.operations})
+			req := httptest.NewRequest(http.MethodPost, "/inventory/transact", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.expectedStatus == http.StatusConflict || tt.expectedStatus == http.StatusNotFound {
+				var errorResp models.TransactErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errorResp))
+				assert.Equal(t, tt.expectedIndex, errorResp.FailedIndex)
+				assert.Equal(t, tt.operations[tt.expectedIndex].ID, errorResp.FailedID)
+			}
+
+			assert.Equal(t, tt.laptopStock, stockOf(laptop))
+			assert.Equal(t, tt.mouseStock, stockOf(mouse))
+		})
+	}
+}
+
+func TestItemHandler_Transact_Concurrent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	// In-memory SQLite databases are private to a connection
+	sqlDB, err := testDB.DB.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+
+	router.POST("/inventory/transact", handler.Transact)
+
+	item := testDB.CreateTestItem(t, "Limited Item", 5, 10.0)
+
+	const numRequests = 10
+	var wg sync.WaitGroup
+	statuses := make([]int, numRequests)
+	for i := 0; i < numRequests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			body, _ := json.Marshal(models.TransactRequest{
+				Operations: []models.StockOperation{{ID: item.ID.String(), Delta: -1}},
+			})
+			req := httptest.NewRequest(http.MethodPost, "/inventory/transact", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			statuses[i] = w.Code
+		}(i)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, status := range statuses {
+		if status == http.StatusOK {
+			succeeded++
+		} else {
+			assert.Equal(t, http.StatusConflict, status)
+		}
+	}
+	assert.Equal(t, 5, succeeded)
+
+	var stored models.Item
+	require.NoError(t, testDB.DB.First(&stored, "id = ?", item.ID).Error)
+	assert.Equal(t, 0, stored.Stock)
+}
+
+func TestItemHandler_PriceWarnings(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	cfg := &utils.Config{Validation: utils.ValidationConfig{MaxReasonablePrice: 10000}}
+	handler := controllers.NewItemControllerWithService(utils.NewItemServiceWithConfig(testDB.DB, cfg))
+
+	router.POST("/inventory", handler.CreateItem)
+	router.PUT("/inventory/:id", handler.UpdateItem)
+
+	send := func(method, path string, payload interface{}) (int, models.ItemResponse) {
+		body, _ := json.Marshal(payload)
+		req := httptest.NewRequest(method, path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response models.ItemResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w.Code, response
+	}
+
+	status, created := send(http.MethodPost, "/inventory", models.CreateItemRequest{Name: "Cheap Item", Stock: 1, Price: 99.99})
+	require.Equal(t, http.StatusCreated, status)
+	assert.Empty(t, created.Warnings)
+
+	status, expensive := send(http.MethodPost, "/inventory", models.CreateItemRequest{Name: "Pricey Item", Stock: 1, Price: 50000})
+	require.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, 50000.0, expensive.Price)
+	require.Len(t, expensive.Warnings, 1)
+	assert.Contains(t, expensive.Warnings[0], "exceeds the reasonable maximum")
+
+	price := 20000.0
+	status, updated := send(http.MethodPut, "/inventory/"+created.ID.String(), models.UpdateItemRequest{Price: &price})
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, price, updated.Price)
+	assert.Len(t, updated.Warnings, 1)
+
+	stock := 5
+	status, updated = send(http.MethodPut, "/inventory/"+created.ID.String(), models.UpdateItemRequest{Stock: &stock})
+	require.Equal(t, http.StatusOK, status)
+	assert.Empty(t, updated.Warnings)
+}
+
+func TestItemHandler_GetStatsForIDs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+
+	router.POST("/inventory/stats/for-ids", handler.GetStatsForIDs)
+
+	laptop := testDB.CreateTestItem(t, "Laptop", 5, 1000.00)
+	mouse := testDB.CreateTestItem(t, "Mouse", 20, 25.00)
+	testDB.CreateTestItem(t, "Keyboard", 150, 75.50)
+	euro := testDB.CreateTestItem(t, "Euro Monitor", 3, 200.00)
+	require.NoError(t, testDB.DB.Model(euro).Update("currency", "EUR").Error)
+	value := func(v float64) *float64 { return &v }
+
+	tooMany := make([]string, 101)
+	for i := range tooMany {
+		tooMany[i] = uuid.New().String()
+	}
+
+	tests := []struct {
+		name           string
+		ids            []string
+		expectedStatus int
+		expected       models.IDStatsResponse
+	}{
+		{
+			name:           "existing and missing ids",
+			ids:            []string{laptop.ID.String(), mouse.ID.String(), uuid.New().String()},
+			expectedStatus: http.StatusOK,
+			expected: models.IDStatsResponse{
+				Count: 2, TotalValue: value(5500.00), TotalValueByCurrency: map[string]float64{"USD": 5500.00}, TotalStock: 25,
+			},
+		},
+		{
+			name:           "mixed currencies",
+			ids:            []string{laptop.ID.String(), euro.ID.String()},
+			expectedStatus: http.StatusOK,
+			expected: models.IDStatsResponse{
+				Count: 2, TotalValueByCurrency: map[string]float64{"USD": 5000.00, "EUR": 600.00}, TotalStock: 8,
+			},
+		},
+		{
+			name:           "only missing ids",
+			ids:            []string{uuid.New().String()},
+			expectedStatus: http.StatusOK,
+			expected:       models.IDStatsResponse{TotalValue: value(0), TotalValueByCurrency: map[string]float64{}},
+		},
+		{
+			name:           "invalid uuid",
+			ids:            []string{laptop.ID.String(), "not-a-uuid"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "empty list",
+			ids:            []string{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "too many ids",
+			ids:            tooMany,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqBody, err := json.Marshal(models.IDStatsRequest{IDs: tt.ids})
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/inventory/stats/for-ids", bytes.NewBuffer(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var stats models.IDStatsResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+			assert.Equal(t, tt.expected, stats)
+		})
+	}
+}
+
+func TestItemHandler_GetItems_PaginationHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+
+	router.GET("/inventory", handler.GetItems)
+
+	for i := 0; i < 5; i++ {
+		testDB.CreateTestItem(t, fmt.Sprintf("Item %d", i), 10, 1.0)
+	}
+
+	linkPattern := regexp.MustCompile(`^<([^>]+)>; rel="(first|next)"$`)
+	parseLinks := func(t *testing.T, header string) map[string]*url.URL {
+		links := make(map[string]*url.URL)
+		if header == "" {
+			return links
+		}
+		for _, part := range strings.Split(header, ", ") {
+			match := linkPattern.FindStringSubmatch(part)
+			require.NotNil(t, match, "malformed link %q", part)
+			u, err := url.Parse(match[1])
+			require.NoError(t, err)
+			assert.True(t, u.IsAbs())
+			links[match[2]] = u
+		}
+		return links
+	}
+
+	get := func(t *testing.T, target string) (*httptest.ResponseRecorder, models.PaginatedResponse) {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response models.PaginatedResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w, response
+	}
+
+	w, first := get(t, "http://example.com/inventory?limit=2&min_stock=5")
+	assert.Equal(t, "5", w.Header().Get("X-Total-Count"))
+
+	links := parseLinks(t, w.Header().Get("Link"))
+	require.Contains(t, links, "next")
+	assert.NotContains(t, links, "first")
+
+	next := links["next"]
+	assert.Equal(t, "example.com", next.Host)
+	assert.Equal(t, "/inventory", next.Path)
+	assert.Equal(t, "2", next.Query().Get("limit"))
+	assert.Equal(t, "5", next.Query().Get("min_stock"))
+	assert.Equal(t, first.NextCursor, next.Query().Get("cursor"))
+
+	// Following the links walks every page
+	seen := len(first.Items)
+	for {
+		w, page := get(t, links["next"].String())
+		assert.Equal(t, "5", w.Header().Get("X-Total-Count"))
+		seen += len(page.Items)
+
+		links = parseLinks(t, w.Header().Get("Link"))
+		require.Contains(t, links, "first")
+		assert.Empty(t, links["first"].Query().Get("cursor"))
+		assert.Equal(t, "2", links["first"].Query().Get("limit"))
+
+		if !page.HasMore {
+			assert.NotContains(t, links, "next")
+			break
+		}
+	}
+	assert.Equal(t, 5, seen)
+
+	w, _ = get(t, "/inventory?min_stock=100")
+	assert.Equal(t, "0", w.Header().Get("X-Total-Count"))
+	assert.Empty(t, w.Header().Get("Link"))
+}
+
+func TestItemHandler_ValidateItems(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+
+	router.POST("/inventory/validate", handler.ValidateItems)
+
+	t.Run("mixed rows", func(t *testing.T) {
+		reqBody, err := json.Marshal(models.ValidateItemsRequest{
+			Items: []models.CreateItemRequest{
+				{Name: "Laptop", Stock: 5, Price: 999.99},
+				{Stock: 5, Price: 10.0},
+				{Name: "Mouse", Stock: 10, Price: 25.99, Currency: "XYZ", ImageURL: "ftp://example.com/mouse.png"},
+				{Name: "Keyboard", Stock: 3, Price: 75.50, Currency: "EUR"},
+			},
+		})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/inventory/validate", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response models.ValidateItemsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 2, response.Valid)
+		assert.Equal(t, 2, response.Invalid)
+		require.Len(t, response.Results, 4)
+
+		assert.Equal(t, models.RowValidation{Row: 0, Valid: true}, response.Results[0])
+		assert.Equal(t, models.RowValidation{Row: 1, Valid: false, Errors: []string{"name is required"}}, response.Results[1])
+		assert.Equal(t, 2, response.Results[2].Row)
+		assert.False(t, response.Results[2].Valid)
+		assert.ElementsMatch(t, []string{
+			"currency must be one of USD EUR GBP JPY CHF CAD AUD CNY",
+			"image_url must be an http or https URL",
+		}, response.Results[2].Errors)
+		assert.Equal(t, models.RowValidation{Row: 3, Valid: true}, response.Results[3])
+
+		var count int64
+		require.NoError(t, testDB.DB.Model(&models.Item{}).Count(&count).Error)
+		assert.Equal(t, int64(0), count)
+	})
+
+	t.Run("empty list", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/inventory/validate", strings.NewReader(`{"items": []}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestItemHandler_GetItems_DefaultPageSize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	cfg := &utils.Config{Pagination: utils.PaginationConfig{DefaultPageSize: 3}}
+	handler := controllers.NewItemControllerWithService(utils.NewItemServiceWithConfig(testDB.DB, cfg))
+
+	router.GET("/inventory", handler.GetItems)
+
+	for i := 0; i < 5; i++ {
+		testDB.CreateTestItem(t, fmt.Sprintf("Item %d", i), 10, 1.0)
+	}
+
+	tests := []struct {
+		name          string
+		query         string
+		expectedCount int
+	}{
+		{name: "limit omitted", query: "", expectedCount: 3},
+		{name: "explicit limit", query: "?limit=4", expectedCount: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/inventory"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var response models.PaginatedResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Len(t, response.Items, tt.expectedCount)
+			assert.True(t, response.HasMore)
+		})
+	}
+}
+
+func TestItemHandler_UpsertItemBySKU(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+
+	router.PUT("/inventory/by-sku/:sku", handler.UpsertItemBySKU)
+	router.DELETE("/inventory/:id", handler.DeleteItem)
+
+	var location string
+	upsert := func(t *testing.T, sku string, payload models.CreateItemRequest) (int, models.ItemResponse) {
+		body, err := json.Marshal(payload)
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPut, "/inventory/by-sku/"+sku, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		location = w.Header().Get("Location")
+
+		var response models.ItemResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w.Code, response
+	}
+
+	countBySKU := func(t *testing.T, sku string) int64 {
+		var count int64
+		require.NoError(t, testDB.DB.Unscoped().Model(&models.Item{}).Where("sku = ?", sku).Count(&count).Error)
+		return count
+	}
+
+	var created models.ItemResponse
+
+	t.Run("insert new sku", func(t *testing.T) {
+		var status int
+		status, created = upsert(t, "LAPTOP-15", models.CreateItemRequest{Name: "Laptop", Stock: 5, Price: 999.99})
+		require.Equal(t, http.StatusCreated, status)
+		assert.Equal(t, "/inventory/"+created.ID.String(), location)
+		require.NotNil(t, created.SKU)
+		assert.Equal(t, "LAPTOP-15", *created.SKU)
+		assert.Equal(t, "Laptop", created.Name)
+		assert.Equal(t, 5, created.Stock)
+		assert.Equal(t, int64(1), countBySKU(t, "LAPTOP-15"))
+	})
+
+	t.Run("update existing sku", func(t *testing.T) {
+		status, updated := upsert(t, "LAPTOP-15", models.CreateItemRequest{Name: "Laptop Pro", Stock: 8, Price: 1299.99, Currency: "EUR"})
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, created.ID, updated.ID)
+		assert.Equal(t, "Laptop Pro", updated.Name)
+		assert.Equal(t, 8, updated.Stock)
+		assert.Equal(t, 1299.99, updated.Price)
+		assert.Equal(t, "EUR", updated.Currency)
+		assert.Equal(t, int64(1), countBySKU(t, "LAPTOP-15"))
+
+		var movements []models.StockMovement
+		require.NoError(t, testDB.DB.Where("item_id = ?", created.ID).Order("id ASC").Find(&movements).Error)
+		require.Len(t, movements, 2)
+		assert.Equal(t, 5, movements[0].Delta)
+		assert.Equal(t, 3, movements[1].Delta)
+		assert.Equal(t, 8, movements[1].ResultingStock)
+	})
+
+	t.Run("repeated upsert is idempotent", func(t *testing.T) {
+		status, existing := upsert(t, "LAPTOP-15", models.CreateItemRequest{Name: "Laptop Pro", Stock: 8, Price: 1299.99, Currency: "EUR"})
+		require.Equal(t, http.StatusOK, status)
+		assert.Empty(t, location, "only a created item gets a Location")
+		assert.Equal(t, created.ID, existing.ID)
+		assert.Equal(t, created.ItemNumber, existing.ItemNumber)
+		assert.Equal(t, "Laptop Pro", existing.Name)
+		assert.Equal(t, int64(1), countBySKU(t, "LAPTOP-15"))
+	})
+
+	t.Run("restores soft-deleted item", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/inventory/"+created.ID.String(), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusNoContent, w.Code)
+
+		status, restored := upsert(t, "LAPTOP-15", models.CreateItemRequest{Name: "Laptop", Stock: 2, Price: 899.99})
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, created.ID, restored.ID)
+
+		var stored models.Item
+		require.NoError(t, testDB.DB.First(&stored, "id = ?", created.ID).Error)
+		assert.Equal(t, 2, stored.Stock)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		status, _ := upsert(t, "MOUSE-1", models.CreateItemRequest{Stock: 5, Price: 10})
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, int64(0), countBySKU(t, "MOUSE-1"))
+	})
+
+	t.Run("sku too long", func(t *testing.T) {
+		status, _ := upsert(t, strings.Repeat("A", models.MaxSKULength+1), models.CreateItemRequest{Name: "Mouse", Stock: 5, Price: 10})
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+}
+
+func TestItemHandler_GetItems_ExactPrice(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+
+	router.GET("/inventory", handler.GetItems)
+
+	testDB.CreateTestItem(t, "Cable", 10, 9.99)
+	testDB.CreateTestItem(t, "Adapter", 20, 9.99)
+	testDB.CreateTestItem(t, "Charger", 30, 19.99)
+	testDB.CreateTestItem(t, "Battery", 40, 9.98)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedNames  []string
+	}{
+		{
+			name:           "exact match",
+			query:          "price_eq=9.99",
+			expectedStatus: http.StatusOK,
+			expectedNames:  []string{"Adapter", "Cable"},
+		},
+		{
+			name:           "no match",
+			query:          "price_eq=5.00",
+			expectedStatus: http.StatusOK,
+			expectedNames:  []string{},
+		},
+		{
+			name:           "combined with min_price",
+			query:          "price_eq=9.99&min_price=5",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "combined with max_price",
+			query:          "price_eq=9.99&max_price=50",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "negative price",
+			query:          "price_eq=-1",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/inventory?"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response models.PaginatedResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+			names := []string{}
+			for _, item := range response.Items {
+				names = append(names, item.Name)
+			}
+			assert.ElementsMatch(t, tt.expectedNames, names)
+		})
+	}
+}
+
+func TestItemHandler_ActiveItems(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+
+	router.GET("/inventory", handler.GetItems)
+	router.GET("/inventory/stats", handler.GetItemStats)
+	router.PUT("/inventory/:id", handler.UpdateItem)
+
+	laptop := testDB.CreateTestItem(t, "Laptop", 5, 1000.00)
+	testDB.CreateTestItem(t, "Mouse", 20, 25.00)
+	assert.True(t, laptop.Active, "items are active by default")
+
+	// Discontinue the laptop
+	body, err := json.Marshal(models.UpdateItemRequest{Active: utils.BoolPtr(false)})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPut, "/inventory/"+laptop.ID.String(), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var updated models.ItemResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	assert.False(t, updated.Active)
+
+	tests := []struct {
+		name          string
+		query         string
+		expectedNames []string
+	}{
+		{name: "default lists active only", query: "", expectedNames: []string{"Mouse"}},
+		{name: "active true", query: "?active=true", expectedNames: []string{"Mouse"}},
+		{name: "active false", query: "?active=false", expectedNames: []string{"Laptop"}},
+		{name: "include inactive", query: "?include_inactive=true", expectedNames: []string{"Laptop", "Mouse"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/inventory"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var response models.PaginatedResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+			names := []string{}
+			for _, item := range response.Items {
+				names = append(names, item.Name)
+			}
+			assert.ElementsMatch(t, tt.expectedNames, names)
+		})
+	}
+
+	t.Run("stats exclude discontinued items", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/inventory/stats", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var stats map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+		assert.Equal(t, 500.0, stats["total_value"])
+		assert.Equal(t, 25.0, stats["average_price"])
+		assert.Equal(t, 0.0, stats["low_stock_items"])
+	})
+}
+
+func TestItemHandler_ContentNegotiation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+
+	router.GET("/inventory", handler.GetItems)
+	router.GET("/inventory/:id", handler.GetItem)
+
+	laptop := testDB.CreateTestItem(t, "Laptop", 5, 999.99)
+	testDB.CreateTestItem(t, "Mouse", 20, 25.99)
+
+	get := func(t *testing.T, target, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("single item", func(t *testing.T) {
+		for _, accept := range []string{"", "application/json", "text/html"} {
+			w := get(t, "/inventory/"+laptop.ID.String(), accept)
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Contains(t, w.Header().Get("Content-Type"), "application/json", "Accept %q", accept)
+
+			var item models.Item
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &item))
+			assert.Equal(t, laptop.ID, item.ID)
+		}
+
+		for _, accept := range []string{"application/xml", "text/xml", "application/xml;q=0.9, application/json;q=0.5"} {
+			w := get(t, "/inventory/"+laptop.ID.String(), accept)
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Contains(t, w.Header().Get("Content-Type"), "xml", "Accept %q", accept)
+
+			var item models.Item
+			require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &item))
+			assert.Equal(t, "item", item.XMLName.Local)
+			assert.Equal(t, laptop.ID, item.ID)
+			assert.Equal(t, "Laptop", item.Name)
+			assert.Equal(t, 5, item.Stock)
+			assert.Equal(t, 999.99, item.Price)
+			assert.True(t, item.Active)
+		}
+	})
+
+	t.Run("item list", func(t *testing.T) {
+		w := get(t, "/inventory?limit=1", "application/json")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var jsonPage models.PaginatedResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &jsonPage))
+		require.Len(t, jsonPage.Items, 1)
+
+		w = get(t, "/inventory?limit=1", "application/xml")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/xml")
+		assert.True(t, strings.HasPrefix(w.Body.String(), "<page><items><item>"), w.Body.String())
+
+		var xmlPage models.PaginatedResponse
+		require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &xmlPage))
+		require.Len(t, xmlPage.Items, 1)
+		assert.Equal(t, jsonPage.Items[0].ID, xmlPage.Items[0].ID)
+		assert.Equal(t, jsonPage.NextCursor, xmlPage.NextCursor)
+		assert.True(t, xmlPage.HasMore)
+		assert.Equal(t, int64(2), xmlPage.Total)
+	})
+
+	t.Run("fields with xml", func(t *testing.T) {
+		w := get(t, "/inventory?fields=id,name", "application/xml")
+		assert.Equal(t, http.StatusNotAcceptable, w.Code)
+	})
+}
+
+func TestItemHandler_GetItemStats_Window(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+	router.GET("/inventory/stats", handler.GetItemStats)
+
+	monthStart := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	created := func(name string, stock int, price float64, at time.Time) {
+		item := testDB.CreateTestItem(t, name, stock, price)
+		require.NoError(t, testDB.DB.Model(item).UpdateColumn("created_at", at).Error)
+	}
+	created("Last Month", 10, 100.00, monthStart.Add(-time.Hour))
+	created("Month Start", 2, 5.00, monthStart)
+	created("Mid Month", 4, 10.00, monthStart.AddDate(0, 0, 14))
+	created("Next Month", 1, 1000.00, monthStart.AddDate(0, 1, 0))
+
+	getStats := func(query string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		req := httptest.NewRequest(http.MethodGet, "/inventory/stats"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var stats map[string]interface{}
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+		}
+		return w, stats
+	}
+
+	_, global := getStats("")
+	assert.Equal(t, float64(4), global["total_items"])
+	assert.Equal(t, 2050.00, global["total_value"])
+
+	t.Run("scopes every aggregate to the window", func(t *testing.T) {
+		w, stats := getStats("?created_after=2026-10-01T00:00:00Z&created_before=2026-11-01T00:00:00Z")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotEqual(t, global, stats)
+		assert.Equal(t, float64(2), stats["total_items"])
+		assert.Equal(t, 50.00, stats["total_value"])
+		assert.Equal(t, 7.50, stats["average_price"])
+		assert.Equal(t, 5.00, stats["min_price"])
+		assert.Equal(t, 10.00, stats["max_price"])
+		assert.Equal(t, float64(4), stats["max_stock"])
+		assert.Equal(t, float64(2), stats["low_stock_items"])
+	})
+
+	t.Run("open ended window", func(t *testing.T) {
+		_, stats := getStats("?created_after=2026-10-15T00:00:00Z")
+		assert.Equal(t, float64(2), stats["total_items"])
+
+		_, stats = getStats("?created_before=2026-10-01T00:00:00Z")
+		assert.Equal(t, float64(1), stats["total_items"])
+		assert.Equal(t, 1000.00, stats["total_value"])
+	})
+
+	t.Run("invalid timestamp", func(t *testing.T) {
+		w, _ := getStats("?created_after=yesterday")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestItemHandler_GetItemStats_Ranges(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+
+	router.GET("/inventory/stats", handler.GetItemStats)
+
+	getStats := func(query string) map[string]interface{} {
+		req := httptest.NewRequest(http.MethodGet, "/inventory/stats"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var stats map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+		return stats
+	}
+
+	// Aggregates over no rows are NULL in SQL, but every stat is reported as zero
+	assertZero := func(t *testing.T, stats map[string]interface{}) {
+		for _, key := range []string{"total_items", "total_value", "average_price", "min_price", "max_price", "min_stock", "max_stock", "low_stock_items"} {
+			assert.Equal(t, float64(0), stats[key], key)
+		}
+		assert.Empty(t, stats["total_value_by_currency"])
+		assert.NotNil(t, stats["computed_at"])
+	}
+
+	t.Run("empty inventory", func(t *testing.T) {
+		assertZero(t, getStats(""))
+	})
+
+	t.Run("varied items", func(t *testing.T) {
+		testDB.CreateTestItem(t, "Cable", 120, 4.99)
+		testDB.CreateTestItem(t, "Monitor", 3, 249.50)
+		testDB.CreateTestItem(t, "Keyboard", 45, 59.00)
+
+		discontinued := testDB.CreateTestItem(t, "Old Laptop", 1, 1999.99)
+		require.NoError(t, testDB.DB.Model(discontinued).Update("active", false).Error)
+
+		stats := getStats("")
+		assert.Equal(t, float64(3), stats["total_items"])
+		assert.Equal(t, 4.99, stats["min_price"])
+		assert.Equal(t, 249.50, stats["max_price"])
+		assert.Equal(t, float64(3), stats["min_stock"])
+		assert.Equal(t, float64(120), stats["max_stock"])
+	})
+
+	t.Run("filters matching nothing", func(t *testing.T) {
+		assertZero(t, getStats("?name=projector"))
+	})
+}
+
+func TestItemHandler_NameLengthLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	send := func(router *gin.Engine, method, path, name string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]interface{}{"name": name, "stock": 1, "price": 1.0})
+		req := httptest.NewRequest(method, path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("default limit", func(t *testing.T) {
+		router := utils.SetupTestRouter()
+		handler := controllers.NewItemControllerWithService(utils.NewItemServiceWithDB(testDB.DB))
+		router.POST("/inventory", handler.CreateItem)
+
+		w := send(router, http.MethodPost, "/inventory", strings.Repeat("a", models.DefaultMaxNameLength))
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		w = send(router, http.MethodPost, "/inventory", strings.Repeat("a", models.DefaultMaxNameLength+1))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response models.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Invalid item name", response.Error)
+	})
+
+	t.Run("configured limit", func(t *testing.T) {
+		router := utils.SetupTestRouter()
+		cfg := &utils.Config{Validation: utils.ValidationConfig{MaxNameLength: 300}}
+		handler := controllers.NewItemControllerWithService(utils.NewItemServiceWithConfig(testDB.DB, cfg))
+		router.POST("/inventory", handler.CreateItem)
+		router.PUT("/inventory/:id", handler.UpdateItem)
+
+		w := send(router, http.MethodPost, "/inventory", strings.Repeat("b", 300))
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		var created models.ItemResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		assert.Len(t, created.Name, 300)
+
+		w = send(router, http.MethodPost, "/inventory", strings.Repeat("b", 301))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		// Characters are counted, not bytes
+		w = send(router, http.MethodPut, "/inventory/"+created.ID.String(), strings.Repeat("é", 300))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = send(router, http.MethodPut, "/inventory/"+created.ID.String(), strings.Repeat("é", 301))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestItemHandler_ActorTracking(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+	router.Use(utils.AuthMiddleware("test-api-key"))
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+
+	router.POST("/inventory", handler.CreateItem)
+	router.PUT("/inventory/:id", handler.UpdateItem)
+
+	send := func(method, path string, body interface{}, headers map[string]string) models.ItemResponse {
+		payload, _ := json.Marshal(body)
+		req := httptest.NewRequest(method, path, bytes.NewBuffer(payload))
+		req.Header.Set("Content-Type", "application/json")
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Less(t, w.Code, 300, w.Body.String())
+
+		var response models.ItemResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	created := send(http.MethodPost, "/inventory",
+		map[string]interface{}{"name": "Tracked Item", "stock": 5, "price": 9.99},
+		map[string]string{utils.ActorHeader: "jane.doe"})
+	assert.Equal(t, "jane.doe", created.CreatedBy)
+	assert.Equal(t, "jane.doe", created.UpdatedBy)
+
+	t.Run("update records the new actor", func(t *testing.T) {
+		updated := send(http.MethodPut, "/inventory/"+created.ID.String(),
+			map[string]interface{}{"stock": 7},
+			map[string]string{utils.ActorHeader: "john.roe"})
+		assert.Equal(t, "jane.doe", updated.CreatedBy)
+		assert.Equal(t, "john.roe", updated.UpdatedBy)
+
+		stored := models.Item{}
+		require.NoError(t, testDB.DB.First(&stored, "id = ?", created.ID).Error)
+		assert.Equal(t, "jane.doe", stored.CreatedBy)
+		assert.Equal(t, "john.roe", stored.UpdatedBy)
+	})
+
+	t.Run("api key identity", func(t *testing.T) {
+		updated := send(http.MethodPut, "/inventory/"+created.ID.String(),
+			map[string]interface{}{"stock": 8},
+			map[string]string{"Authorization": "Bearer test-api-key"})
+		assert.Equal(t, utils.AdminActor, updated.UpdatedBy)
+	})
+
+	t.Run("no actor", func(t *testing.T) {
+		item := send(http.MethodPost, "/inventory",
+			map[string]interface{}{"name": "Anonymous Item", "stock": 1, "price": 1.0}, nil)
+		assert.Equal(t, models.SystemActor, item.CreatedBy)
+		assert.Equal(t, models.SystemActor, item.UpdatedBy)
+	})
+}
+
+func TestItemHandler_ReorderPoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+
+	router.POST("/inventory", handler.CreateItem)
+	router.GET("/inventory/low-stock", handler.GetLowStockItems)
+	router.GET("/inventory/stats", handler.GetItemStats)
+
+	create := func(name string, stock, reorderPoint int) models.ItemResponse {
+		body, _ := json.Marshal(map[string]interface{}{
+			"name": name, "stock": stock, "price": 1.0,
+			"reorder_point": reorderPoint, "auto_reorder": reorderPoint > 0,
+		})
+		req := httptest.NewRequest(http.MethodPost, "/inventory", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		var response models.ItemResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	// Below its own reorder point, though above the global threshold
+	bolts := create("Bolts", 40, 50)
+	assert.Equal(t, 50, bolts.ReorderPoint)
+	assert.True(t, bolts.AutoReorder)
+	// At its reorder point, so not yet low
+	create("Nuts", 20, 20)
+	// Below the global threshold, but above its own reorder point
+	create("Crates", 5, 2)
+	// No reorder point, so the global threshold applies
+	create("Washers", 3, 0)
+	create("Screws", 15, 1)
+
+	t.Run("low stock items", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/inventory/low-stock", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var items []models.Item
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &items))
+
+		names := make([]string, len(items))
+		for i, item := range items {
+			names[i] = item.Name
+		}
+		assert.Equal(t, []string{"Washers", "Bolts"}, names)
+	})
+
+	t.Run("stats", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/inventory/stats", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var stats map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+		assert.Equal(t, float64(2), stats["low_stock_items"])
+	})
+}
+
+func TestItemHandler_GetInventoryValue(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+
+	router.GET("/inventory/value", handler.GetInventoryValue)
+
+	testDB.CreateTestItem(t, "Laptop", 5, 1000.0)
+	testDB.CreateTestItem(t, "Laptop Bag", 10, 50.0)
+	testDB.CreateTestItem(t, "Mouse", 20, 25.0)
+	discontinued := testDB.CreateTestItem(t, "Old Laptop", 2, 400.0)
+	require.NoError(t, testDB.DB.Model(discontinued).Update("active", false).Error)
+
+	tests := []struct {
+		name       string
+		query      string
+		wantValue  float64
+		wantCount  int64
+		wantStatus int
+	}{
+		{name: "all active items", query: "", wantValue: 6000, wantCount: 3, wantStatus: http.StatusOK},
+		{name: "name filter", query: "?name=laptop", wantValue: 5500, wantCount: 2, wantStatus: http.StatusOK},
+		{name: "price filter", query: "?min_price=40", wantValue: 5500, wantCount: 2, wantStatus: http.StatusOK},
+		{name: "combined filters", query: "?name=laptop&max_price=100", wantValue: 500, wantCount: 1, wantStatus: http.StatusOK},
+		{name: "including inactive", query: "?name=laptop&include_inactive=true", wantValue: 6300, wantCount: 3, wantStatus: http.StatusOK},
+		{name: "no matches", query: "?min_price=5000", wantValue: 0, wantCount: 0, wantStatus: http.StatusOK},
+		{name: "invalid filter", query: "?min_price=-1", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/inventory/value"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var value models.InventoryValueResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &value))
+			require.NotNil(t, value.TotalValue)
+			assert.Equal(t, tt.wantValue, *value.TotalValue)
+			assert.Equal(t, tt.wantCount, value.ItemCount)
+		})
+	}
+
+	t.Run("mixed currencies are totalled separately", func(t *testing.T) {
+		euro := testDB.CreateTestItem(t, "Euro Laptop", 2, 800.0)
+		require.NoError(t, testDB.DB.Model(euro).Update("currency", "EUR").Error)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/inventory/value?name=laptop", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var value models.InventoryValueResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &value))
+		assert.Nil(t, value.TotalValue)
+		assert.Equal(t, map[string]float64{"USD": 5500, "EUR": 1600}, value.TotalValueByCurrency)
+		assert.Equal(t, int64(3), value.ItemCount)
+	})
+}
+
+func TestItemHandler_ItemLocks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+
+	router.PUT("/inventory/:id", handler.UpdateItem)
+	router.POST("/inventory/:id/lock", handler.LockItem)
+	router.DELETE("/inventory/:id/lock", handler.UnlockItem)
+
+	item := testDB.CreateTestItem(t, "Shared Item", 10, 25.0)
+	path := "/inventory/" + item.ID.String()
+
+	lock := func(owner string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(models.LockRequest{Owner: owner, TTLSeconds: 60})
+		req := httptest.NewRequest(http.MethodPost, path+"/lock", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	unlock := func(owner string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodDelete, path+"/lock", nil)
+		req.Header.Set(utils.LockOwnerHeader, owner)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	update := func(owner string, stock int) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]int{"stock": stock})
+		req := httptest.NewRequest(http.MethodPut, path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		if owner != "" {
+			req.Header.Set(utils.LockOwnerHeader, owner)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("acquire", func(t *testing.T) {
+		w := lock("alice")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var acquired models.ItemLock
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &acquired))
+		assert.Equal(t, item.ID, acquired.ItemID)
+		assert.Equal(t, "alice", acquired.Owner)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), acquired.ExpiresAt, 5*time.Second)
+
+		// The owner can renew its own lock
+		assert.Equal(t, http.StatusOK, lock("alice").Code)
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		assert.Equal(t, http.StatusLocked, lock("bob").Code)
+		assert.Equal(t, http.StatusLocked, update("bob", 5).Code)
+		assert.Equal(t, http.StatusLocked, update("", 5).Code)
+		assert.Equal(t, http.StatusLocked, unlock("bob").Code)
+
+		assert.Equal(t, http.StatusOK, update("alice", 7).Code)
+
+		stored := models.Item{}
+		require.NoError(t, testDB.DB.First(&stored, "id = ?", item.ID).Error)
+		assert.Equal(t, 7, stored.Stock)
+	})
+
+	t.Run("release", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, unlock("alice").Code)
+		assert.Equal(t, http.StatusOK, update("", 8).Code)
+		// Releasing again is a no-op
+		assert.Equal(t, http.StatusNoContent, unlock("alice").Code)
+	})
+
+	t.Run("expiry", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, lock("alice").Code)
+		require.Equal(t, http.StatusLocked, update("bob", 9).Code)
+
+		require.NoError(t, testDB.DB.Model(&models.ItemLock{}).
+			Where("item_id = ?", item.ID).
+			Update("expires_at", time.Now().UTC().Add(-time.Second)).Error)
+
+		assert.Equal(t, http.StatusOK, update("bob", 9).Code)
+		assert.Equal(t, http.StatusOK, lock("bob").Code)
+		assert.Equal(t, http.StatusLocked, lock("alice").Code)
+	})
+
+	t.Run("unknown item", func(t *testing.T) {
+		body, _ := json.Marshal(models.LockRequest{Owner: "alice"})
+		req := httptest.NewRequest(http.MethodPost, "/inventory/"+uuid.New().String()+"/lock", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestItemHandler_GetItemMovements_Pagination(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+
+	router.GET("/inventory/:id/movements", handler.GetItemMovements)
+
+	item := testDB.CreateTestItem(t, "Busy Item", 0, 1.0)
+	movements := make([]models.StockMovement, 25)
+	for i := range movements {
+		movements[i] = models.StockMovement{ItemID: item.ID, Delta: 1, ResultingStock: i + 1}
+	}
+	require.NoError(t, testDB.DB.Create(&movements).Error)
+
+	getPage := func(query string) (*httptest.ResponseRecorder, models.PaginatedMovementsResponse) {
+		req := httptest.NewRequest(http.MethodGet, "/inventory/"+item.ID.String()+"/movements"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var page models.PaginatedMovementsResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		}
+		return w, page
+	}
+
+	t.Run("pages through every movement in order", func(t *testing.T) {
+		var seen []int
+		query := "?limit=10"
+		pages := 0
+		for {
+			w, page := getPage(query)
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "25", w.Header().Get("X-Total-Count"))
+			assert.Equal(t, int64(25), page.Total)
+			pages++
+
+			for _, movement := range page.Items {
+				seen = append(seen, movement.ResultingStock)
+			}
+			if !page.HasMore {
+				assert.Empty(t, page.NextCursor)
+				break
+			}
+			query = "?limit=10&cursor=" + url.QueryEscape(page.NextCursor)
+		}
+
+		assert.Equal(t, 3, pages)
+		require.Len(t, seen, 25)
+		for i, stock := range seen {
+			assert.Equal(t, i+1, stock)
+		}
+	})
+
+	t.Run("default page size", func(t *testing.T) {
+		_, page := getPage("")
+		assert.Len(t, page.Items, utils.DefaultPageSize)
+		assert.True(t, page.HasMore)
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		w, _ := getPage("?limit=101")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w, _ = getPage("?cursor=not-a-cursor")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestItemHandler_DatabaseBusy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+	require.NoError(t, testDB.DB.Use(utils.NewQueryLimiter(1, 0)))
+
+	// Hold the only query slot until the test lets go of it
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	require.NoError(t, testDB.DB.Callback().Query().After("query_limiter:acquire").Before("gorm:query").
+		Register("test:block", func(db *gorm.DB) {
+			if _, ok := db.Get("test:block"); ok && db.Error == nil {
+				close(started)
+				<-unblock
+			}
+		}))
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+	router.GET("/inventory", handler.GetItems)
+
+	done := make(chan error, 1)
+	go func() {
+		var items []models.Item
+		done <- testDB.DB.Set("test:block", true).Find(&items).Error
+	}()
+	<-started
+
+	req := httptest.NewRequest(http.MethodGet, "/inventory", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	var response models.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+
+	close(unblock)
+	require.NoError(t, <-done)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/inventory", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestItemHandler_PatchItem(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+	router.PATCH("/inventory/:id", handler.PatchItem)
+
+	patch := func(id, body string) (*httptest.ResponseRecorder, models.Item) {
+		req := httptest.NewRequest(http.MethodPatch, "/inventory/"+id, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response models.ItemResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		}
+		return w, response.Item
+	}
+
+	item := testDB.CreateTestItem(t, "Camera", 4, 250.0)
+	id := item.ID.String()
+
+	t.Run("set a nullable field", func(t *testing.T) {
+		w, patched := patch(id, `{"image_url": "https://cdn.example.com/camera.png"}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "https://cdn.example.com/camera.png", patched.ImageURL)
+		assert.Equal(t, "Camera", patched.Name)
+	})
+
+	t.Run("omitting a field leaves it untouched", func(t *testing.T) {
+		w, patched := patch(id, `{"stock": 9}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 9, patched.Stock)
+		assert.Equal(t, "https://cdn.example.com/camera.png", patched.ImageURL)
+	})
+
+	t.Run("null clears a nullable field", func(t *testing.T) {
+		w, patched := patch(id, `{"image_url": null}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, patched.ImageURL)
+		assert.Equal(t, 9, patched.Stock)
+
+		var stored models.Item
+		require.NoError(t, testDB.DB.First(&stored, "id = ?", id).Error)
+		assert.Empty(t, stored.ImageURL)
+	})
+
+	t.Run("invalid patches", func(t *testing.T) {
+		w, _ := patch(id, `{"name": null}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w, _ = patch(id, `{"stock": -1}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w, _ = patch(id, `[1, 2]`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w, _ = patch("not-a-uuid", `{"stock": 1}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("missing item", func(t *testing.T) {
+		w, _ := patch(uuid.New().String(), `{"stock": 1}`)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestItemHandler_ItemDimensions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+	router.POST("/inventory", handler.CreateItem)
+	router.GET("/inventory/:id", handler.GetItem)
+	router.PUT("/inventory/:id", handler.UpdateItem)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(http.MethodPost, "/inventory", `{"name": "Monitor", "stock": 3, "price": 199.99, "weight_grams": 4200, "length_mm": 600, "width_mm": 400, "height_mm": 150}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created models.ItemResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, 4200, created.WeightGrams)
+	assert.Equal(t, int64(36000000), created.VolumeMM3)
+
+	t.Run("volume is returned on read", func(t *testing.T) {
+		w := send(http.MethodGet, "/inventory/"+created.ID.String(), "")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var item models.Item
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &item))
+		assert.Equal(t, 600, item.LengthMM)
+		assert.Equal(t, int64(36000000), item.VolumeMM3)
+	})
+
+	t.Run("volume follows updated dimensions", func(t *testing.T) {
+		w := send(http.MethodPut, "/inventory/"+created.ID.String(), `{"height_mm": 100}`)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var updated models.ItemResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+		assert.Equal(t, int64(24000000), updated.VolumeMM3)
+	})
+
+	t.Run("negative dimensions are rejected", func(t *testing.T) {
+		w := send(http.MethodPost, "/inventory", `{"name": "Bad", "stock": 1, "price": 1, "weight_grams": -1}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = send(http.MethodPut, "/inventory/"+created.ID.String(), `{"width_mm": -5}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestItemHandler_SyncStock(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+	router.POST("/inventory/stock-sync", handler.SyncStock)
+
+	sync := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/inventory/stock-sync", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	withSKU := func(name, sku string, stock int, price float64) *models.Item {
+		item := testDB.CreateTestItem(t, name, stock, price)
+		require.NoError(t, testDB.DB.Model(item).Update("sku", sku).Error)
+		return item
+	}
+	laptop := withSKU("Laptop", "LAPTOP-1", 5, 999.99)
+	mouse := withSKU("Mouse", "MOUSE-1", 20, 19.99)
+
+	t.Run("matched and unmatched SKUs", func(t *testing.T) {
+		w := sync(`[{"sku": "LAPTOP-1", "stock": 12}, {"sku": "MOUSE-1", "stock": 20}, {"sku": "GONE-1", "stock": 3}]`)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response models.StockSyncResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 2, response.Updated)
+		assert.Equal(t, 1, response.Unmatched)
+		assert.Equal(t, []string{"GONE-1"}, response.UnmatchedSKUs)
+
+		var storedLaptop, storedMouse models.Item
+		require.NoError(t, testDB.DB.First(&storedLaptop, "id = ?", laptop.ID).Error)
+		assert.Equal(t, 12, storedLaptop.Stock)
+		require.NoError(t, testDB.DB.First(&storedMouse, "id = ?", mouse.ID).Error)
+		assert.Equal(t, 20, storedMouse.Stock)
+	})
+
+	t.Run("other fields are untouched", func(t *testing.T) {
+		w := sync(`[{"sku": "LAPTOP-1", "stock": 0}]`)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var stored models.Item
+		require.NoError(t, testDB.DB.First(&stored, "id = ?", laptop.ID).Error)
+		assert.Equal(t, 0, stored.Stock)
+		assert.Equal(t, "Laptop", stored.Name)
+		assert.Equal(t, 999.99, stored.Price)
+		assert.Equal(t, "LAPTOP-1", *stored.SKU)
+		assert.True(t, stored.Active)
+	})
+
+	t.Run("stock changes are recorded in the ledger", func(t *testing.T) {
+		var movements []models.StockMovement
+		require.NoError(t, testDB.DB.Where("item_id = ? AND reason = ?", laptop.ID, models.MovementReasonStockSync).Order("id").Find(&movements).Error)
+		require.Len(t, movements, 2)
+		assert.Equal(t, 7, movements[0].Delta)
+		assert.Equal(t, -12, movements[1].Delta)
+
+		var mouseMovements int64
+		require.NoError(t, testDB.DB.Model(&models.StockMovement{}).Where("item_id = ? AND reason = ?", mouse.ID, models.MovementReasonStockSync).Count(&mouseMovements).Error)
+		assert.Zero(t, mouseMovements, "an unchanged stock level is not a movement")
+	})
+
+	t.Run("a repeated SKU is counted once and its last entry wins", func(t *testing.T) {
+		w := sync(`[{"sku": "MOUSE-1", "stock": 30}, {"sku": "GONE-2", "stock": 1}, {"sku": "MOUSE-1", "stock": 25}, {"sku": "GONE-2", "stock": 2}]`)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response models.StockSyncResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 1, response.Updated)
+		assert.Equal(t, 1, response.Unmatched)
+		assert.Equal(t, []string{"GONE-2"}, response.UnmatchedSKUs)
+
+		var stored models.Item
+		require.NoError(t, testDB.DB.First(&stored, "id = ?", mouse.ID).Error)
+		assert.Equal(t, 25, stored.Stock)
+
+		var movements []models.StockMovement
+		require.NoError(t, testDB.DB.Where("item_id = ? AND reason = ?", mouse.ID, models.MovementReasonStockSync).Find(&movements).Error)
+		require.Len(t, movements, 1)
+		assert.Equal(t, 5, movements[0].Delta)
+	})
+
+	t.Run("invalid entries", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, sync(`[]`).Code)
+		assert.Equal(t, http.StatusBadRequest, sync(`[{"sku": "LAPTOP-1", "stock": -1}]`).Code)
+		assert.Equal(t, http.StatusBadRequest, sync(`[{"sku": "LAPTOP-1"}]`).Code)
+		assert.Equal(t, http.StatusBadRequest, sync(`[{"stock": 4}]`).Code)
+		assert.Equal(t, http.StatusBadRequest, sync(`{"sku": "LAPTOP-1", "stock": 4}`).Code)
+	})
+}
+
+func TestItemHandler_DeleteItem_Representation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+	router.DELETE("/inventory/:id", handler.DeleteItem)
+
+	tests := []struct {
+		name           string
+		query          string
+		prefer         string
+		expectedStatus int
+		expectBody     bool
+	}{
+		{name: "no content by default", expectedStatus: http.StatusNoContent},
+		{name: "echo query", query: "?echo=true", expectedStatus: http.StatusOK, expectBody: true},
+		{name: "prefer representation", prefer: "return=representation", expectedStatus: http.StatusOK, expectBody: true},
+		{name: "prefer among others", prefer: "respond-async, return=representation", expectedStatus: http.StatusOK, expectBody: true},
+		{name: "prefer minimal", prefer: "return=minimal", expectedStatus: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := testDB.CreateTestItem(t, "Doomed", 1, 1.00)
+
+			req := httptest.NewRequest(http.MethodDelete, "/inventory/"+item.ID.String()+tt.query, nil)
+			if tt.prefer != "" {
+				req.Header.Set("Prefer", tt.prefer)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if !tt.expectBody {
+				assert.Empty(t, w.Body.String())
+				return
+			}
+
+			var response models.DeleteResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.True(t, response.Deleted)
+			assert.Equal(t, item.ID.String(), response.ID)
+		})
+	}
+}
+
+func TestItemHandler_GetItems_CursorBySortField(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+	router.GET("/inventory", handler.GetItems)
+
+	// Many items share a price, so pages split runs of equal values
+	prices := []float64{5, 10, 10, 10, 10, 20, 20, 30, 10, 5, 20, 40}
+	for i, price := range prices {
+		testDB.CreateTestItem(t, fmt.Sprintf("Item %02d", i), i, price)
+	}
+
+	getPage := func(query string) (*httptest.ResponseRecorder, models.PaginatedResponse) {
+		req := httptest.NewRequest(http.MethodGet, "/inventory"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var page models.PaginatedResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		}
+		return w, page
+	}
+
+	collect := func(sortOrder string) []models.Item {
+		var seen []models.Item
+		base := "?limit=3&sort_by=price&sort_order=" + sortOrder
+		query := base
+		for {
+			w, page := getPage(query)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			seen = append(seen, page.Items...)
+			if !page.HasMore {
+				return seen
+			}
+			query = base + "&cursor=" + url.QueryEscape(page.NextCursor)
+		}
+	}
+
+	for _, sortOrder := range []string{"asc", "desc"} {
+		t.Run("pages through every item sorted "+sortOrder, func(t *testing.T) {
+			seen := collect(sortOrder)
+			require.Len(t, seen, len(prices))
+
+			ids := make(map[uuid.UUID]bool)
+			for i, item := range seen {
+				assert.False(t, ids[item.ID], "item %s returned twice", item.Name)
+				ids[item.ID] = true
+				if i > 0 && sortOrder == "asc" {
+					assert.LessOrEqual(t, seen[i-1].Price, item.Price)
+				}
+				if i > 0 && sortOrder == "desc" {
+					assert.GreaterOrEqual(t, seen[i-1].Price, item.Price)
+				}
+			}
+		})
+	}
+
+	t.Run("changing the sort mid-pagination is rejected", func(t *testing.T) {
+		_, page := getPage("?limit=3&sort_by=price&sort_order=asc")
+		require.NotEmpty(t, page.NextCursor)
+
+		for _, query := range []string{"?limit=3&sort_by=name&sort_order=asc", "?limit=3&sort_by=price&sort_order=desc", "?limit=3"} {
+			w, _ := getPage(query + "&cursor=" + url.QueryEscape(page.NextCursor))
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var response models.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, "Invalid cursor", response.Error)
+			assert.Contains(t, response.Message, "sort_by=price")
+		}
+	})
+}
+
+func TestItemHandler_GetItemByNumber(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	service := utils.NewItemServiceWithDB(testDB.DB)
+	handler := controllers.NewItemController()
+	handler.SetItemService(service)
+	router.GET("/inventory/number/:n", handler.GetItemByNumber)
+	router.GET("/inventory/:id", handler.GetItem)
+
+	first := testDB.CreateTestItem(t, "First", 1, 1.00)
+	second := testDB.CreateTestItem(t, "Second", 2, 2.00)
+	assert.Equal(t, int64(1), first.ItemNumber)
+	assert.Equal(t, int64(2), second.ItemNumber)
+
+	require.NoError(t, service.SeedDatabase())
+	var numbers []int64
+	require.NoError(t, testDB.DB.Model(&models.Item{}).Order("item_number").Pluck("item_number", &numbers).Error)
+	for i, number := range numbers {
+		assert.Equal(t, int64(i+1), number, "items created in a batch get consecutive numbers")
+	}
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	t.Run("lookup by number", func(t *testing.T) {
+		w := get("/inventory/number/2")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var item models.Item
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &item))
+		assert.Equal(t, second.ID, item.ID)
+		assert.Equal(t, int64(2), item.ItemNumber)
+	})
+
+	t.Run("the UUID lookup still works", func(t *testing.T) {
+		w := get("/inventory/" + first.ID.String())
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("unknown number", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, get("/inventory/number/9999").Code)
+	})
+
+	t.Run("invalid number", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, get("/inventory/number/abc").Code)
+		assert.Equal(t, http.StatusBadRequest, get("/inventory/number/0").Code)
+	})
+}
+
+func TestItemHandler_ExportItems(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+	router.GET("/inventory/export.jsonl", handler.ExportItems)
+
+	for i := 0; i < 250; i++ {
+		testDB.CreateTestItem(t, fmt.Sprintf("Widget %03d", i), i, float64(i))
+	}
+	retired := testDB.CreateTestItem(t, "Retired Widget", 1, 1.00)
+	require.NoError(t, testDB.DB.Model(retired).Update("active", false).Error)
+
+	export := func(query string) (*httptest.ResponseRecorder, []models.Item) {
+		req := httptest.NewRequest(http.MethodGet, "/inventory/export.jsonl"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var items []models.Item
+		if w.Code != http.StatusOK {
+			return w, items
+		}
+		lines := strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n")
+		for _, line := range lines {
+			if line == "" {
+				continue
+			}
+			var item models.Item
+			require.NoError(t, json.Unmarshal([]byte(line), &item), "line %q", line)
+			items = append(items, item)
+		}
+		return w, items
+	}
+
+	t.Run("streams one item per line", func(t *testing.T) {
+		w, items := export("")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+		require.Len(t, items, 250)
+		assert.Equal(t, "Widget 000", items[0].Name)
+		assert.Equal(t, "Widget 249", items[249].Name)
+	})
+
+	t.Run("honours filters", func(t *testing.T) {
+		w, items := export("?min_stock=240")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Len(t, items, 10)
+
+		_, items = export("?include_inactive=true")
+		assert.Len(t, items, 251)
+
+		_, items = export("?active=false")
+		require.Len(t, items, 1)
+		assert.Equal(t, retired.ID, items[0].ID)
+	})
+
+	t.Run("no matches is an empty stream", func(t *testing.T) {
+		w, items := export("?name=nothing-matches")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+		assert.Empty(t, w.Body.String())
+		assert.Empty(t, items)
+	})
+
+	t.Run("invalid filters", func(t *testing.T) {
+		w, _ := export("?min_stock=-1")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestItemHandler_ExportItems_MaxRows(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	for i := 0; i < 30; i++ {
+		testDB.CreateTestItem(t, fmt.Sprintf("Widget %02d", i), i, float64(i))
+	}
+
+	export := func(action, query string) *httptest.ResponseRecorder {
+		cfg := &utils.Config{Export: utils.ExportConfig{MaxRows: 20, LimitAction: action}}
+		handler := controllers.NewItemControllerWithService(utils.NewItemServiceWithConfig(testDB.DB, cfg))
+		router := utils.SetupTestRouter()
+		router.GET("/inventory/export.jsonl", handler.ExportItems)
+
+		req := httptest.NewRequest(http.MethodGet, "/inventory/export.jsonl"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	lines := func(w *httptest.ResponseRecorder) []models.Item {
+		var items []models.Item
+		for _, line := range strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n") {
+			var item models.Item
+			require.NoError(t, json.Unmarshal([]byte(line), &item), "line %q", line)
+			items = append(items, item)
+		}
+		return items
+	}
+
+	t.Run("truncates at the maximum", func(t *testing.T) {
+		w := export(utils.ExportLimitTruncate, "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "true", w.Header().Get("X-Export-Truncated"))
+		assert.Equal(t, "30", w.Header().Get("X-Total-Count"))
+
+		items := lines(w)
+		require.Len(t, items, 20)
+		assert.Equal(t, "Widget 00", items[0].Name)
+		assert.Equal(t, "Widget 19", items[19].Name)
+	})
+
+	t.Run("rejects when configured to", func(t *testing.T) {
+		w := export(utils.ExportLimitReject, "")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response models.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Export too large", response.Error)
+		assert.Contains(t, response.Message, "30 items match")
+	})
+
+	t.Run("filtered sets within the maximum are exported whole", func(t *testing.T) {
+		for _, action := range []string{utils.ExportLimitTruncate, utils.ExportLimitReject} {
+			w := export(action, "?min_stock=15")
+			require.Equal(t, http.StatusOK, w.Code, action)
+			assert.Empty(t, w.Header().Get("X-Export-Truncated"), action)
+			assert.Len(t, lines(w), 15, action)
+		}
+	})
+}
+
+func TestItemHandler_GetItems_LastModified(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+	router.GET("/inventory", handler.GetItems)
+	router.PUT("/inventory/:id", handler.UpdateItem)
+	router.DELETE("/inventory/:id", handler.DeleteItem)
+
+	widget := testDB.CreateTestItem(t, "Widget", 10, 5.00)
+	gadget := testDB.CreateTestItem(t, "Gadget", 20, 50.00)
+	spare := testDB.CreateTestItem(t, "Spare Widget", 30, 7.00)
+	// HTTP dates have one second resolution, so move the existing changes
+	// well into the past before polling
+	anHourAgo := time.Now().Add(-time.Hour)
+	require.NoError(t, testDB.DB.Model(&models.Item{}).Where("1 = 1").UpdateColumn("updated_at", anHourAgo).Error)
+
+	get := func(query, ifModifiedSince string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/inventory"+query, nil)
+		if ifModifiedSince != "" {
+			req.Header.Set("If-Modified-Since", ifModifiedSince)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	update := func(id uuid.UUID, body string) {
+		req := httptest.NewRequest(http.MethodPut, "/inventory/"+id.String(), strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	}
+
+	w := get("", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	lastModified := w.Header().Get("Last-Modified")
+	require.NotEmpty(t, lastModified)
+	assert.Equal(t, anHourAgo.UTC().Format(http.TimeFormat), lastModified)
+
+	w = get("", lastModified)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Equal(t, lastModified, w.Header().Get("Last-Modified"))
+
+	t.Run("an older If-Modified-Since gets the full list", func(t *testing.T) {
+		w := get("", anHourAgo.Add(-time.Minute).UTC().Format(http.TimeFormat))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("an invalid If-Modified-Since is ignored", func(t *testing.T) {
+		w := get("", "yesterday")
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("an update outside the filter keeps the filtered list unchanged", func(t *testing.T) {
+		update(gadget.ID, `{"stock": 21}`)
+
+		w := get("?name=Widget", lastModified)
+		assert.Equal(t, http.StatusNotModified, w.Code)
+
+		w = get("", lastModified)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("updating a matching item turns 304 into 200", func(t *testing.T) {
+		w := get("?name=Widget", lastModified)
+		require.Equal(t, http.StatusNotModified, w.Code)
+
+		update(widget.ID, `{"stock": 11}`)
+
+		w = get("?name=Widget", lastModified)
+		require.Equal(t, http.StatusOK, w.Code)
+		newer := w.Header().Get("Last-Modified")
+		assert.NotEqual(t, lastModified, newer)
+
+		var response models.PaginatedResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, int64(2), response.Total)
+
+		w = get("?name=Widget", newer)
+		assert.Equal(t, http.StatusNotModified, w.Code)
+	})
+
+	t.Run("deleting a matching item turns 304 into 200", func(t *testing.T) {
+		require.NoError(t, testDB.DB.Model(&models.Item{}).Where("1 = 1").UpdateColumn("updated_at", anHourAgo).Error)
+		w := get("?name=Spare", lastModified)
+		require.Equal(t, http.StatusNotModified, w.Code)
+
+		req := httptest.NewRequest(http.MethodDelete, "/inventory/"+spare.ID.String(), nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		w = get("?name=Spare", lastModified)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("no Last-Modified when nothing matches", func(t *testing.T) {
+		w := get("?name=Nothing", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Last-Modified"))
+	})
+}
+
+func TestItemHandler_GetItems_FilterComplexity(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	testDB.CreateTestItem(t, "Blue Widget", 10, 5.00)
+	testDB.CreateTestItem(t, "Red Widget", 20, 15.00)
+
+	newRouter := func(action string) *gin.Engine {
+		cfg := &utils.Config{Validation: utils.ValidationConfig{MaxFilterComplexity: 4, FilterComplexityAction: action}}
+		handler := controllers.NewItemControllerWithService(utils.NewItemServiceWithConfig(testDB.DB, cfg))
+		router := utils.SetupTestRouter()
+		router.GET("/inventory", handler.GetItems)
+		router.GET("/inventory/value", handler.GetInventoryValue)
+		router.GET("/inventory/export.jsonl", handler.ExportItems)
+		router.POST("/inventory/stats/batch", handler.GetBatchStats)
+		router.POST("/inventory/bulk-tag", handler.BulkTag)
+		router.POST("/inventory/bulk-soft-delete", handler.BulkSoftDelete)
+		return router
+	}
+	get := func(router *gin.Engine, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+	post := func(router *gin.Engine, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	const broad = "?name=Widget&min_stock=1&min_price=1"
+
+	t.Run("too broad a query is rejected with a helpful message", func(t *testing.T) {
+		router := newRouter(utils.FilterComplexityReject)
+
+		for _, path := range []string{"/inventory", "/inventory/value", "/inventory/export.jsonl"} {
+			w := get(router, path+broad)
+			require.Equal(t, http.StatusBadRequest, w.Code, path)
+
+			var response models.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, "Filter too broad", response.Error)
+			assert.Contains(t, response.Message, "complexity 5 exceeds the budget of 4")
+			assert.Contains(t, response.Message, "name matches anywhere in the item name")
+			assert.Contains(t, response.Message, "min_price and max_price")
+		}
+	})
+
+	t.Run("batch counts and bulk writes are held to the budget", func(t *testing.T) {
+		router := newRouter(utils.FilterComplexityReject)
+		const broadFilter = `{"name":"Widget","min_stock":1,"min_price":1}`
+
+		for path, body := range map[string]string{
+			"/inventory/stats/batch":      `{"filters":[{"name":"narrow","filter":{"min_stock":1}},{"name":"broad","filter":` + broadFilter + `}]}`,
+			"/inventory/bulk-tag":         `{"filter":` + broadFilter + `,"tags":["sale"]}`,
+			"/inventory/bulk-soft-delete": broadFilter,
+		} {
+			w := post(router, path, body)
+			require.Equal(t, http.StatusBadRequest, w.Code, path)
+
+			var response models.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, "Filter too broad", response.Error, path)
+			assert.Contains(t, response.Message, "complexity 5 exceeds the budget of 4", path)
+		}
+		assert.Contains(t, post(router, "/inventory/stats/batch", `{"filters":[{"name":"broad","filter":`+broadFilter+`}]}`).Body.String(), "filter broad:")
+
+		var tags, items int64
+		require.NoError(t, testDB.DB.Model(&models.ItemTag{}).Count(&tags).Error)
+		require.NoError(t, testDB.DB.Model(&models.Item{}).Count(&items).Error)
+		assert.Zero(t, tags, "nothing is tagged")
+		assert.Equal(t, int64(2), items, "nothing is deleted")
+
+		w := post(router, "/inventory/stats/batch", `{"filters":[{"name":"narrow","filter":{"min_stock":1,"min_price":1,"max_price":10}}]}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"narrow":1}`, w.Body.String())
+	})
+
+	t.Run("narrower queries are allowed", func(t *testing.T) {
+		router := newRouter(utils.FilterComplexityReject)
+
+		w := get(router, "/inventory?name=Widget&min_price=1&max_price=10")
+		require.Equal(t, http.StatusOK, w.Code)
+		var response models.PaginatedResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Items, 1)
+
+		assert.Equal(t, http.StatusOK, get(router, "/inventory?min_stock=1&min_price=1").Code)
+	})
+
+	t.Run("log action runs the query anyway", func(t *testing.T) {
+		router := newRouter(utils.FilterComplexityLog)
+
+		w := get(router, "/inventory"+broad)
+		require.Equal(t, http.StatusOK, w.Code)
+		var response models.PaginatedResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Items, 2)
+	})
+}
+
+func TestItemHandler_GetItemTree(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	service := utils.NewItemServiceWithDB(testDB.DB)
+	handler := controllers.NewItemControllerWithService(service)
+	categories := controllers.NewCategoryControllerWithService(service)
+	router.POST("/categories", categories.CreateCategory)
+	router.POST("/inventory", handler.CreateItem)
+	router.PATCH("/inventory/:id", handler.PatchItem)
+	router.GET("/inventory/tree", handler.GetItemTree)
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	createCategory := func(name string, parent *models.Category) models.Category {
+		body := fmt.Sprintf(`{"name": %q}`, name)
+		if parent != nil {
+			body = fmt.Sprintf(`{"name": %q, "parent_id": %q}`, name, parent.ID)
+		}
+		w := post("/categories", body)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var category models.Category
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &category))
+		return category
+	}
+	createItem := func(name string, stock int, category *models.Category) models.Item {
+		body := fmt.Sprintf(`{"name": %q, "stock": %d, "price": 10}`, name, stock)
+		if category != nil {
+			body = fmt.Sprintf(`{"name": %q, "stock": %d, "price": 10, "category_id": %q}`, name, stock, category.ID)
+		}
+		w := post("/inventory", body)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var item models.Item
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &item))
+		return item
+	}
+	getTree := func() models.ItemTreeResponse {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/inventory/tree", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		var tree models.ItemTreeResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tree))
+		return tree
+	}
+
+	electronics := createCategory("Electronics", nil)
+	accessories := createCategory("Accessories", &electronics)
+	cables := createCategory("Cables", &electronics)
+	garden := createCategory("Garden", nil)
+	assert.Equal(t, &electronics.ID, accessories.ParentID)
+
+	createItem("Laptop", 5, &electronics)
+	mouse := createItem("Mouse", 20, &accessories)
+	createItem("Keyboard", 10, &accessories)
+	createItem("USB Cable", 100, &cables)
+	createItem("Gift Card", 50, nil)
+	assert.Equal(t, &accessories.ID, mouse.CategoryID)
+
+	t.Run("items are nested under their categories", func(t *testing.T) {
+		tree := getTree()
+
+		require.Len(t, tree.Categories, 2)
+		top := tree.Categories[0]
+		assert.Equal(t, "Electronics", top.Name)
+		assert.Equal(t, 4, top.ItemCount)
+		assert.Equal(t, 135, top.TotalStock)
+		require.Len(t, top.Items, 1)
+		assert.Equal(t, "Laptop", top.Items[0].Name)
+
+		require.Len(t, top.Children, 2)
+		assert.Equal(t, "Accessories", top.Children[0].Name)
+		assert.Equal(t, 2, top.Children[0].ItemCount)
+		assert.Equal(t, 30, top.Children[0].TotalStock)
+		require.Len(t, top.Children[0].Items, 2)
+		assert.Equal(t, "Keyboard", top.Children[0].Items[0].Name)
+		assert.Equal(t, "Mouse", top.Children[0].Items[1].Name)
+		assert.Empty(t, top.Children[0].Children)
+		assert.Equal(t, "Cables", top.Children[1].Name)
+		require.Len(t, top.Children[1].Items, 1)
+		assert.Equal(t, "USB Cable", top.Children[1].Items[0].Name)
+
+		assert.Equal(t, garden.ID, tree.Categories[1].ID)
+		assert.Empty(t, tree.Categories[1].Items)
+		assert.Zero(t, tree.Categories[1].ItemCount)
+
+		require.Len(t, tree.Uncategorized, 1)
+		assert.Equal(t, "Gift Card", tree.Uncategorized[0].Name)
+	})
+
+	t.Run("a merge patch moves an item out of its category", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPatch, "/inventory/"+mouse.ID.String(), strings.NewReader(`{"category_id": null}`))
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		tree := getTree()
+		assert.Equal(t, 1, tree.Categories[0].Children[0].ItemCount)
+		assert.Len(t, tree.Uncategorized, 2)
+	})
+
+	t.Run("unknown categories are rejected", func(t *testing.T) {
+		w := post("/inventory", fmt.Sprintf(`{"name": "Orphan", "stock": 1, "price": 1, "category_id": %q}`, uuid.New()))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid category")
+
+		w = post("/categories", fmt.Sprintf(`{"name": "Orphan", "parent_id": %q}`, uuid.New()))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("nesting is capped", func(t *testing.T) {
+		parent := garden
+		for depth := 2; depth <= models.MaxCategoryDepth; depth++ {
+			parent = createCategory(fmt.Sprintf("Level %d", depth), &parent)
+		}
+
+		w := post("/categories", fmt.Sprintf(`{"name": "Too Deep", "parent_id": %q}`, parent.ID))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "nested too deeply")
+	})
+
+	t.Run("categories caught in a cycle do not hang the tree", func(t *testing.T) {
+		loopA := createCategory("Loop A", nil)
+		loopB := createCategory("Loop B", &loopA)
+		require.NoError(t, testDB.DB.Model(&loopA).Update("parent_id", loopB.ID).Error)
+		createItem("Looped Item", 1, &loopB)
+
+		tree := getTree()
+		for _, category := range tree.Categories {
+			assert.NotEqual(t, loopA.ID, category.ID)
+		}
+		var names []string
+		for _, item := range tree.Uncategorized {
+			names = append(names, item.Name)
+		}
+		assert.Contains(t, names, "Looped Item")
+	})
+}
+
+func TestItemHandler_GetDuplicates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+	router.GET("/inventory/duplicates", handler.GetDuplicates)
+
+	getDuplicates := func() models.DuplicatesResponse {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/inventory/duplicates", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		var response models.DuplicatesResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	assert.Empty(t, getDuplicates().Groups)
+
+	first := testDB.CreateTestItem(t, "USB Cable", 10, 4.99)
+	second := testDB.CreateTestItem(t, "usb cable ", 5, 3.99)
+	retired := testDB.CreateTestItem(t, "  Usb CABLE", 1, 2.99)
+	require.NoError(t, testDB.DB.Model(retired).Update("active", false).Error)
+	deleted := testDB.CreateTestItem(t, "USB cable", 1, 1.99)
+	require.NoError(t, testDB.DB.Delete(deleted).Error)
+	testDB.CreateTestItem(t, "Adapter", 3, 9.99)
+	testDB.CreateTestItem(t, "adapter", 3, 9.99)
+	testDB.CreateTestItem(t, "USB Cable 2m", 3, 6.99)
+
+	response := getDuplicates()
+	require.Len(t, response.Groups, 2)
+
+	assert.Equal(t, "adapter", response.Groups[0].NormalizedName)
+	assert.Len(t, response.Groups[0].Items, 2)
+
+	cables := response.Groups[1]
+	assert.Equal(t, "usb cable", cables.NormalizedName)
+	require.Len(t, cables.Items, 3, "inactive items are grouped, deleted ones are not")
+	assert.Equal(t, first.ID, cables.Items[0].ID)
+	assert.Equal(t, second.ID, cables.Items[1].ID)
+	assert.Equal(t, "usb cable ", cables.Items[1].Name)
+	assert.Equal(t, retired.ID, cables.Items[2].ID)
+}
+
+func TestItemHandler_MergeItems(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+	router.POST("/inventory/merge", handler.MergeItems)
+
+	merge := func(req models.MergeItemsRequest, owner string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(req)
+		r := httptest.NewRequest(http.MethodPost, "/inventory/merge", bytes.NewBuffer(body))
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set(utils.ActorHeader, "alice")
+		if owner != "" {
+			r.Header.Set(utils.LockOwnerHeader, owner)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	keep := testDB.CreateTestItem(t, "USB Cable", 10, 4.99)
+	first := testDB.CreateTestItem(t, "usb cable", 5, 3.99)
+	second := testDB.CreateTestItem(t, "USB cable ", 2, 2.99)
+
+	t.Run("validation", func(t *testing.T) {
+		w := merge(models.MergeItemsRequest{KeepID: keep.ID.String()}, "")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = merge(models.MergeItemsRequest{KeepID: keep.ID.String(), MergeIDs: []string{first.ID.String(), keep.ID.String()}}, "")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("unknown item", func(t *testing.T) {
+		w := merge(models.MergeItemsRequest{KeepID: uuid.New().String(), MergeIDs: []string{first.ID.String()}}, "")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = merge(models.MergeItemsRequest{KeepID: keep.ID.String(), MergeIDs: []string{first.ID.String(), uuid.New().String()}}, "")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		stored := models.Item{}
+		require.NoError(t, testDB.DB.First(&stored, "id = ?", first.ID).Error)
+		assert.Equal(t, 5, stored.Stock, "a failed merge changes nothing")
+	})
+
+	t.Run("locked", func(t *testing.T) {
+		require.NoError(t, testDB.DB.Create(&models.ItemLock{ItemID: second.ID, Owner: "bob", ExpiresAt: time.Now().UTC().Add(time.Minute)}).Error)
+		defer testDB.DB.Where("item_id = ?", second.ID).Delete(&models.ItemLock{})
+
+		w := merge(models.MergeItemsRequest{KeepID: keep.ID.String(), MergeIDs: []string{first.ID.String(), second.ID.String()}}, "")
+		assert.Equal(t, http.StatusLocked, w.Code)
+
+		stored := models.Item{}
+		require.NoError(t, testDB.DB.First(&stored, "id = ?", keep.ID).Error)
+		assert.Equal(t, 10, stored.Stock, "a failed merge changes nothing")
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		w := merge(models.MergeItemsRequest{KeepID: keep.ID.String(), MergeIDs: []string{first.ID.String(), second.ID.String()}}, "")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var merged models.Item
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &merged))
+		assert.Equal(t, keep.ID, merged.ID)
+		assert.Equal(t, 17, merged.Stock)
+		assert.Equal(t, "alice", merged.UpdatedBy)
+
+		var remaining int64
+		require.NoError(t, testDB.DB.Model(&models.Item{}).Where("id IN ?", []uuid.UUID{first.ID, second.ID}).Count(&remaining).Error)
+		assert.Zero(t, remaining, "merged items are soft-deleted")
+
+		var deleted models.Item
+		require.NoError(t, testDB.DB.Unscoped().First(&deleted, "id = ?", first.ID).Error)
+		assert.True(t, deleted.DeletedAt.Valid)
+		assert.Zero(t, deleted.Stock)
+
+		var movements []models.StockMovement
+		require.NoError(t, testDB.DB.Where("reason = ?", models.MovementReasonMerge).Find(&movements).Error)
+		require.Len(t, movements, 3)
+		deltas := map[uuid.UUID]int{}
+		for _, m := range movements {
+			deltas[m.ItemID] = m.Delta
+		}
+		assert.Equal(t, 7, deltas[keep.ID])
+		assert.Equal(t, -5, deltas[first.ID])
+		assert.Equal(t, -2, deltas[second.ID])
+
+		w = merge(models.MergeItemsRequest{KeepID: keep.ID.String(), MergeIDs: []string{first.ID.String()}}, "")
+		assert.Equal(t, http.StatusNotFound, w.Code, "merged items cannot be merged again")
+	})
+}
+
+func TestItemHandler_SampleItems(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+	router.GET("/inventory/sample", handler.SampleItems)
+
+	sample := func(query string) (int, []models.Item) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/inventory/sample"+query, nil))
+		var items []models.Item
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &items))
+		}
+		return w.Code, items
+	}
+
+	status, items := sample("")
+	require.Equal(t, http.StatusOK, status)
+	assert.Empty(t, items)
+
+	for i := 0; i < 20; i++ {
+		testDB.CreateTestItem(t, fmt.Sprintf("Item %02d", i), i, 1.0)
+	}
+	deleted := testDB.CreateTestItem(t, "Deleted Item", 1, 1.0)
+	require.NoError(t, testDB.DB.Delete(deleted).Error)
+
+	t.Run("count", func(t *testing.T) {
+		status, items := sample("")
+		require.Equal(t, http.StatusOK, status)
+		assert.Len(t, items, models.DefaultSampleSize)
+
+		status, items = sample("?n=3")
+		require.Equal(t, http.StatusOK, status)
+		assert.Len(t, items, 3)
+
+		status, items = sample("?n=100")
+		require.Equal(t, http.StatusOK, status)
+		assert.Len(t, items, 20, "a sample larger than the inventory returns every item")
+		for _, item := range items {
+			assert.NotEqual(t, deleted.ID, item.ID)
+		}
+	})
+
+	t.Run("varies", func(t *testing.T) {
+		// Best effort: 10 samples of 5 from 20 items all matching is vanishingly unlikely
+		seen := map[string]bool{}
+		for i := 0; i < 10; i++ {
+			_, items := sample("?n=5")
+			ids := make([]string, 0, len(items))
+			for _, item := range items {
+				ids = append(ids, item.ID.String())
+			}
+			seen[strings.Join(ids, ",")] = true
+		}
+		assert.Greater(t, len(seen), 1)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		for _, query := range []string{"?n=0", "?n=-1", "?n=101", "?n=abc"} {
+			status, _ := sample(query)
+			assert.Equal(t, http.StatusBadRequest, status, query)
+		}
+	})
+}
+
+func TestItemHandler_CircuitBreaker(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+	breaker := utils.NewCircuitBreaker(2, time.Minute)
+	require.NoError(t, testDB.DB.Use(breaker))
+
+	// Fail every query that reaches the database with a broken connection
+	reached := 0
+	require.NoError(t, testDB.DB.Callback().Query().After("circuit_breaker:allow").Before("gorm:query").
+		Register("test:fail", func(db *gorm.DB) {
+			if db.Error == nil {
+				reached++
+				db.AddError(driver.ErrBadConn)
+			}
+		}))
+
+	// A zero config disables retries, so each request fails the breaker once
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithConfig(testDB.DB, &utils.Config{}))
+	router.GET("/inventory/:id", handler.GetItem)
+
+	get := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/inventory/"+uuid.New().String(), nil))
+		return w
+	}
+
+	assert.Equal(t, http.StatusInternalServerError, get().Code)
+	assert.Equal(t, http.StatusInternalServerError, get().Code)
+	assert.Equal(t, utils.BreakerOpen, breaker.State())
+
+	w := get()
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	var response models.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+	assert.Contains(t, response.Message, "circuit breaker is open")
+	assert.Equal(t, 2, reached, "an open breaker fails fast without querying the database")
+}
+
+func TestItemHandler_BulkSoftDelete(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemController()
+	handler.SetItemService(utils.NewItemServiceWithDB(testDB.DB))
+	router.POST("/inventory/bulk-soft-delete", handler.BulkSoftDelete)
+
+	bulkDelete := func(query string, body string) (*httptest.ResponseRecorder, int64) {
+		req := httptest.NewRequest(http.MethodPost, "/inventory/bulk-soft-delete"+query, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response map[string]int64
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		}
+		return w, response["deleted"]
+	}
+	remaining := func() int64 {
+		var count int64
+		require.NoError(t, testDB.DB.Model(&models.Item{}).Count(&count).Error)
+		return count
+	}
+
+	testDB.CreateTestItem(t, "Legacy Cable", 5, 4.99)
+	testDB.CreateTestItem(t, "Legacy Adapter", 3, 9.99)
+	retired := testDB.CreateTestItem(t, "Legacy Mouse", 1, 19.99)
+	require.NoError(t, testDB.DB.Model(retired).Update("active", false).Error)
+	keep := testDB.CreateTestItem(t, "Keyboard", 10, 49.99)
+
+	t.Run("empty filter is refused", func(t *testing.T) {
+		for _, body := range []string{`{}`, `{"include_inactive": true}`} {
+			w, _ := bulkDelete("", body)
+			assert.Equal(t, http.StatusBadRequest, w.Code, body)
+
+			var response models.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, "Filter required", response.Error)
+		}
+
+		w, _ := bulkDelete("?confirm_all=false", `{}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, int64(4), remaining())
+	})
+
+	t.Run("filtered", func(t *testing.T) {
+		w, deleted := bulkDelete("", `{"name": "legacy"}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, int64(2), deleted, "inactive items are left alone unless asked for")
+		assert.Equal(t, int64(2), remaining())
+
+		w, deleted = bulkDelete("", `{"name": "legacy", "include_inactive": true}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, int64(1), deleted, "already deleted items are not counted again")
+
+		var deletedItems int64
+		require.NoError(t, testDB.DB.Unscoped().Model(&models.Item{}).Where("deleted_at IS NOT NULL").Count(&deletedItems).Error)
+		assert.Equal(t, int64(3), deletedItems, "items are soft-deleted, not removed")
+
+		var stored models.Item
+		require.NoError(t, testDB.DB.First(&stored, "id = ?", keep.ID).Error)
+	})
+
+	t.Run("confirm all", func(t *testing.T) {
+		w, deleted := bulkDelete("?confirm_all=true", `{}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, int64(1), deleted)
+		assert.Zero(t, remaining())
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		w, _ := bulkDelete("", `{"min_stock": -1}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestItemHandler_RefreshStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	cfg := &utils.Config{Cache: utils.CacheConfig{StatsRefreshInterval: time.Hour}}
+	handler := controllers.NewItemControllerWithService(utils.NewItemServiceWithConfig(testDB.DB, cfg))
+	router.GET("/inventory/stats", handler.GetItemStats)
+	router.POST("/inventory/stats/refresh", handler.RefreshStats)
+
+	stats := func(method, path string) map[string]interface{} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+	computedAt := func(stats map[string]interface{}) time.Time {
+		at, err := time.Parse(time.RFC3339Nano, stats["computed_at"].(string))
+		require.NoError(t, err)
+		return at
+	}
+
+	testDB.CreateTestItem(t, "Item 1", 10, 100.0)
+
+	first := stats(http.MethodGet, "/inventory/stats")
+	assert.Equal(t, float64(1), first["total_items"])
+	assert.WithinDuration(t, time.Now(), computedAt(first), 5*time.Second)
+
+	// Reads serve the snapshot until it is refreshed
+	testDB.CreateTestItem(t, "Item 2", 20, 50.0)
+	cached := stats(http.MethodGet, "/inventory/stats")
+	assert.Equal(t, float64(1), cached["total_items"])
+	assert.Equal(t, computedAt(first), computedAt(cached))
+
+	refreshed := stats(http.MethodPost, "/inventory/stats/refresh")
+	assert.Equal(t, float64(2), refreshed["total_items"])
+	assert.Equal(t, float64(2000), refreshed["total_value"])
+	assert.False(t, computedAt(refreshed).Before(computedAt(first)))
+
+	after := stats(http.MethodGet, "/inventory/stats")
+	assert.Equal(t, float64(2), after["total_items"])
+	assert.Equal(t, computedAt(refreshed), computedAt(after))
+}
+
+func TestItemHandler_StockAndPriceLimits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	send := func(router *gin.Engine, method, path string, body map[string]interface{}) *httptest.ResponseRecorder {
+		payload, _ := json.Marshal(body)
+		req := httptest.NewRequest(method, path, bytes.NewBuffer(payload))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assertTooLarge := func(t *testing.T, w *httptest.ResponseRecorder) {
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response models.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Value too large", response.Error)
+	}
+
+	t.Run("default limits", func(t *testing.T) {
+		router := utils.SetupTestRouter()
+		handler := controllers.NewItemControllerWithService(utils.NewItemServiceWithDB(testDB.DB))
+		router.POST("/inventory", handler.CreateItem)
+
+		w := send(router, http.MethodPost, "/inventory", map[string]interface{}{
+			"name": "At limit", "stock": models.DefaultMaxStock, "price": models.DefaultMaxPrice,
+		})
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		w = send(router, http.MethodPost, "/inventory", map[string]interface{}{
+			"name": "Huge stock", "stock": 2000000000, "price": 1.0,
+		})
+		assertTooLarge(t, w)
+	})
+
+	t.Run("configured limits", func(t *testing.T) {
+		router := utils.SetupTestRouter()
+		cfg := &utils.Config{Validation: utils.ValidationConfig{MaxStock: 100, MaxPrice: 50}}
+		handler := controllers.NewItemControllerWithService(utils.NewItemServiceWithConfig(testDB.DB, cfg))
+		router.POST("/inventory", handler.CreateItem)
+		router.PUT("/inventory/:id", handler.UpdateItem)
+
+		w := send(router, http.MethodPost, "/inventory", map[string]interface{}{"name": "Widget", "stock": 100, "price": 50.0})
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		var created models.ItemResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+
+		w = send(router, http.MethodPost, "/inventory", map[string]interface{}{"name": "Widget", "stock": 101, "price": 50.0})
+		assertTooLarge(t, w)
+		w = send(router, http.MethodPost, "/inventory", map[string]interface{}{"name": "Widget", "stock": 100, "price": 50.01})
+		assertTooLarge(t, w)
+
+		path := "/inventory/" + created.ID.String()
+		w = send(router, http.MethodPut, path, map[string]interface{}{"stock": 100, "price": 50.0})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = send(router, http.MethodPut, path, map[string]interface{}{"stock": 101})
+		assertTooLarge(t, w)
+		w = send(router, http.MethodPut, path, map[string]interface{}{"price": 50.01})
+		assertTooLarge(t, w)
+
+		// Rejected updates leave the item untouched
+		var item models.Item
+		require.NoError(t, testDB.DB.First(&item, "id = ?", created.ID).Error)
+		assert.Equal(t, 100, item.Stock)
+		assert.Equal(t, 50.0, item.Price)
+	})
+
+	// Stock changed in bulk is held to the same maximum
+	bulkRouter := func() (*gin.Engine, *models.Item) {
+		router := utils.SetupTestRouter()
+		cfg := &utils.Config{Validation: utils.ValidationConfig{MaxStock: 100}}
+		handler := controllers.NewItemControllerWithService(utils.NewItemServiceWithConfig(testDB.DB, cfg))
+		router.POST("/inventory/transact", handler.Transact)
+		router.POST("/inventory/stock-sync", handler.SyncStock)
+
+		item := testDB.CreateTestItem(t, "Bulk Widget", 90, 1.0)
+		require.NoError(t, testDB.DB.Model(item).UpdateColumn("sku", "WIDGET-"+item.ID.String()[:8]).Error)
+		require.NoError(t, testDB.DB.First(item, "id = ?", item.ID).Error)
+		return router, item
+	}
+	post := func(router *gin.Engine, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	stockOf := func(item *models.Item) int {
+		var current models.Item
+		require.NoError(t, testDB.DB.First(&current, "id = ?", item.ID).Error)
+		return current.Stock
+	}
+
+	t.Run("transactional adjustments", func(t *testing.T) {
+		router, item := bulkRouter()
+		operation := func(delta int) string {
+			return fmt.Sprintf(`{"operations":[{"id":%q,"delta":%d}]}`, item.ID.String(), delta)
+		}
+
+		w := post(router, "/inventory/transact", operation(11))
+		assertTooLarge(t, w)
+		var response models.TransactErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 0, response.FailedIndex)
+		assert.Equal(t, item.ID.String(), response.FailedID)
+		assert.Equal(t, 90, stockOf(item))
+
+		w = post(router, "/inventory/transact", operation(10))
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, 100, stockOf(item))
+	})
+
+	t.Run("stock sync", func(t *testing.T) {
+		router, item := bulkRouter()
+		sync := func(stock int) string {
+			return fmt.Sprintf(`[{"sku":%q,"stock":%d}]`, *item.SKU, stock)
+		}
+
+		assertTooLarge(t, post(router, "/inventory/stock-sync", sync(101)))
+		assert.Equal(t, 90, stockOf(item))
+
+		w := post(router, "/inventory/stock-sync", sync(100))
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, 100, stockOf(item))
+	})
+}
+
+func TestItemHandler_GetCategoryCounts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	service := utils.NewItemServiceWithDB(testDB.DB)
+	handler := controllers.NewItemControllerWithService(service)
+	router.GET("/inventory/categories", handler.GetCategoryCounts)
+
+	getCounts := func() []models.CategoryCount {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/inventory/categories", nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var counts []models.CategoryCount
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &counts))
+		return counts
+	}
+
+	assert.Empty(t, getCounts())
+
+	createCategory := func(name string) *models.Category {
+		category, err := service.CreateCategory(&models.CreateCategoryRequest{Name: name})
+		require.NoError(t, err)
+		return category
+	}
+	createItem := func(name string, category *models.Category) *models.Item {
+		item, err := service.CreateItem(&models.CreateItemRequest{Name: name, Stock: 1, Price: 1, CategoryID: category.ID.String()})
+		require.NoError(t, err)
+		return item
+	}
+
+	tools := createCategory("Tools")
+	garden := createCategory("Garden")
+	toys := createCategory("Toys")
+	createCategory("Empty")
+
+	createItem("Hammer", tools)
+	createItem("Wrench", tools)
+	createItem("Saw", tools)
+	createItem("Rake", garden)
+	createItem("Hose", garden)
+	createItem("Ball", toys)
+
+	// Soft-deleted and discontinued items are not counted
+	require.NoError(t, service.DeleteItem(createItem("Old rake", garden).ID.String()))
+	inactive := false
+	_, err := service.UpdateItem(createItem("Old ball", toys).ID.String(), &models.UpdateItemRequest{Active: &inactive})
+	require.NoError(t, err)
+
+	counts := getCounts()
+	require.Len(t, counts, 4)
+	assert.Equal(t, models.CategoryCount{ID: tools.ID, Category: "Tools", Count: 3}, counts[0])
+	assert.Equal(t, models.CategoryCount{ID: garden.ID, Category: "Garden", Count: 2}, counts[1])
+	assert.Equal(t, models.CategoryCount{ID: toys.ID, Category: "Toys", Count: 1}, counts[2])
+	assert.Equal(t, "Empty", counts[3].Category)
+	assert.Equal(t, int64(0), counts[3].Count)
+}
+
+func TestItemHandler_GroupItems(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	service := utils.NewItemServiceWithDB(testDB.DB)
+	handler := controllers.NewItemControllerWithService(service)
+	router.GET("/inventory/group-by", handler.GroupItems)
+
+	group := func(query string) (int, models.GroupedItemsResponse) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/inventory/group-by?"+query, nil))
+		var response models.GroupedItemsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w.Code, response
+	}
+	names := func(items []models.Item) []string {
+		var names []string
+		for _, item := range items {
+			names = append(names, item.Name)
+		}
+		return names
+	}
+
+	tools, err := service.CreateCategory(&models.CreateCategoryRequest{Name: "Tools"})
+	require.NoError(t, err)
+	garden, err := service.CreateCategory(&models.CreateCategoryRequest{Name: "Garden"})
+	require.NoError(t, err)
+	createItem := func(name string, price float64, categoryID string) {
+		_, err := service.CreateItem(&models.CreateItemRequest{Name: name, Stock: 1, Price: price, CategoryID: categoryID})
+		require.NoError(t, err)
+	}
+	// A second "Tools" under Garden must not be merged with the first
+	gardenTools, err := service.CreateCategory(&models.CreateCategoryRequest{Name: "Tools", ParentID: garden.ID.String()})
+	require.NoError(t, err)
+	createItem("Wrench", 15, tools.ID.String())
+	createItem("Hammer", 25, tools.ID.String())
+	createItem("Rake", 40, garden.ID.String())
+	createItem("Trowel", 30, gardenTools.ID.String())
+	createItem("Gift card", 5, "")
+
+	t.Run("by category", func(t *testing.T) {
+		code, response := group("field=category")
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "category", response.Field)
+		require.Len(t, response.Groups, 4)
+		assert.Equal(t, []string{"Hammer", "Wrench"}, names(response.Groups[tools.ID.String()]))
+		assert.Equal(t, []string{"Rake"}, names(response.Groups[garden.ID.String()]))
+		assert.Equal(t, []string{"Trowel"}, names(response.Groups[gardenTools.ID.String()]))
+		assert.Equal(t, []string{"Gift card"}, names(response.Groups[models.UncategorizedGroup]))
+		assert.Equal(t, map[string]string{
+			tools.ID.String():       "Tools",
+			garden.ID.String():      "Garden",
+			gardenTools.ID.String(): "Tools",
+		}, response.Labels)
+		assert.Nil(t, response.Counts)
+	})
+
+	t.Run("counts by category", func(t *testing.T) {
+		code, response := group("field=category&counts=true")
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, map[string]int{
+			tools.ID.String():         2,
+			garden.ID.String():        1,
+			gardenTools.ID.String():   1,
+			models.UncategorizedGroup: 1,
+		}, response.Counts)
+		assert.Len(t, response.Labels, 3)
+		assert.Nil(t, response.Groups)
+	})
+
+	t.Run("counts by price range", func(t *testing.T) {
+		code, response := group("field=price_range&counts=true")
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, map[string]int{"0-10": 1, "10-50": 4}, response.Counts)
+		assert.Nil(t, response.Groups)
+	})
+
+	t.Run("with filters", func(t *testing.T) {
+		code, response := group("field=category&min_price=20")
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{"Hammer"}, names(response.Groups[tools.ID.String()]))
+		assert.NotContains(t, response.Groups, models.UncategorizedGroup)
+	})
+
+	t.Run("rejects fields not in the allowed list", func(t *testing.T) {
+		for _, query := range []string{"", "field=name", "field=price%3BDROP%20TABLE%20items"} {
+			code, _ := group(query)
+			assert.Equal(t, http.StatusBadRequest, code, query)
+		}
+	})
+
+	t.Run("caps the items grouped but not the counts", func(t *testing.T) {
+		bulk := make([]models.Item, models.MaxGroupedItems)
+		for i := range bulk {
+			bulk[i] = models.Item{Name: fmt.Sprintf("Bulk %d", i), Stock: 1, Price: 1}
+		}
+		require.NoError(t, testDB.DB.CreateInBatches(bulk, 100).Error)
+
+		code, _ := group("field=currency")
+		assert.Equal(t, http.StatusBadRequest, code)
+
+		code, response := group("field=currency&counts=true")
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, map[string]int{models.DefaultCurrency: models.MaxGroupedItems + 5}, response.Counts)
+
+		code, response = group("field=currency&min_price=20")
+		require.Equal(t, http.StatusOK, code)
+		assert.Len(t, response.Groups[models.DefaultCurrency], 3)
+	})
+}
+
+func TestItemHandler_GetItemsIncludeTotals(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemControllerWithService(utils.NewItemServiceWithDB(testDB.DB))
+	router.GET("/inventory", handler.GetItems)
+
+	testDB.CreateTestItem(t, "Laptop", 5, 1000.0)
+	testDB.CreateTestItem(t, "Laptop Bag", 10, 50.0)
+	testDB.CreateTestItem(t, "Laptop Stand", 4, 30.0)
+	testDB.CreateTestItem(t, "Mouse", 20, 25.0)
+
+	get := func(query string) models.PaginatedResponse {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/inventory"+query, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var response models.PaginatedResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	t.Run("off by default", func(t *testing.T) {
+		response := get("?name=laptop&limit=1")
+		assert.Nil(t, response.Aggregate)
+	})
+
+	t.Run("covers every match, not just the page", func(t *testing.T) {
+		response := get("?name=laptop&limit=1&include_totals=true")
+		assert.Len(t, response.Items, 1)
+		assert.True(t, response.HasMore)
+
+		require.NotNil(t, response.Aggregate)
+		require.NotNil(t, response.Aggregate.TotalValue)
+		assert.Equal(t, 5620.0, *response.Aggregate.TotalValue)
+		assert.Equal(t, int64(19), response.Aggregate.TotalStock)
+		require.NotNil(t, response.Aggregate.AvgPrice)
+		assert.InDelta(t, 360.0, *response.Aggregate.AvgPrice, 0.001)
+
+		next := get("?name=laptop&limit=1&include_totals=true&cursor=" + url.QueryEscape(response.NextCursor))
+		assert.Equal(t, response.Aggregate, next.Aggregate)
+	})
+
+	t.Run("with field selection", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/inventory?fields=id,name&include_totals=true", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response models.PartialPaginatedResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.NotNil(t, response.Aggregate)
+		assert.Equal(t, int64(39), response.Aggregate.TotalStock)
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		response := get("?name=tablet&include_totals=true")
+		require.NotNil(t, response.Aggregate)
+		zero := 0.0
+		assert.Equal(t, models.ListAggregate{
+			TotalValue:           &zero,
+			TotalValueByCurrency: models.CurrencyAmounts{},
+			AvgPrice:             &zero,
+			AvgPriceByCurrency:   models.CurrencyAmounts{},
+		}, *response.Aggregate)
+	})
+
+	t.Run("mixed currencies are totalled separately", func(t *testing.T) {
+		euro := testDB.CreateTestItem(t, "Euro Laptop", 2, 800.0)
+		require.NoError(t, testDB.DB.Model(euro).Update("currency", "EUR").Error)
+
+		response := get("?name=laptop&include_totals=true")
+		require.NotNil(t, response.Aggregate)
+		assert.Nil(t, response.Aggregate.TotalValue)
+		assert.Nil(t, response.Aggregate.AvgPrice)
+		assert.Equal(t, models.CurrencyAmounts{"USD": 5620, "EUR": 1600}, response.Aggregate.TotalValueByCurrency)
+		assert.InDelta(t, 360.0, response.Aggregate.AvgPriceByCurrency["USD"], 0.001)
+		assert.Equal(t, 800.0, response.Aggregate.AvgPriceByCurrency["EUR"])
+		assert.Equal(t, int64(21), response.Aggregate.TotalStock)
+
+		req := httptest.NewRequest(http.MethodGet, "/inventory?name=laptop&include_totals=true", nil)
+		req.Header.Set("Accept", "application/xml")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), `<total_value_by_currency><amount currency="EUR">1600</amount><amount currency="USD">5620</amount></total_value_by_currency>`)
+	})
+}
+
+func TestItemHandler_IfUnmodifiedSince(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemControllerWithService(utils.NewItemServiceWithDB(testDB.DB))
+	router.PUT("/inventory/:id", handler.UpdateItem)
+	router.PATCH("/inventory/:id", handler.PatchItem)
+	router.DELETE("/inventory/:id", handler.DeleteItem)
+
+	send := func(method string, item *models.Item, body string, since time.Time) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/inventory/"+item.ID.String(), strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if !since.IsZero() {
+			req.Header.Set("If-Unmodified-Since", since.UTC().Format(http.TimeFormat))
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	reload := func(item *models.Item) *models.Item {
+		current := &models.Item{}
+		require.NoError(t, testDB.DB.Unscoped().First(current, "id = ?", item.ID).Error)
+		return current
+	}
+	assertPreconditionFailed := func(t *testing.T, w *httptest.ResponseRecorder) {
+		require.Equal(t, http.StatusPreconditionFailed, w.Code)
+		var response models.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Precondition failed", response.Error)
+	}
+
+	t.Run("update when unmodified", func(t *testing.T) {
+		item := testDB.CreateTestItem(t, "Widget", 10, 5.0)
+
+		// The item's own updated_at matches, despite the lost sub-second precision
+		w := send(http.MethodPut, item, `{"stock": 11}`, item.UpdatedAt)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, 11, reload(item).Stock)
+	})
+
+	t.Run("update when stale", func(t *testing.T) {
+		item := testDB.CreateTestItem(t, "Widget", 10, 5.0)
+
+		assertPreconditionFailed(t, send(http.MethodPut, item, `{"stock": 12}`, item.UpdatedAt.Add(-time.Hour)))
+		assertPreconditionFailed(t, send(http.MethodPatch, item, `{"stock": 12}`, item.UpdatedAt.Add(-time.Hour)))
+		assert.Equal(t, 10, reload(item).Stock)
+	})
+
+	t.Run("malformed header is ignored", func(t *testing.T) {
+		item := testDB.CreateTestItem(t, "Widget", 10, 5.0)
+
+		req := httptest.NewRequest(http.MethodPut, "/inventory/"+item.ID.String(), strings.NewReader(`{"stock": 13}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Unmodified-Since", "yesterday")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("delete when stale", func(t *testing.T) {
+		item := testDB.CreateTestItem(t, "Widget", 10, 5.0)
+
+		assertPreconditionFailed(t, send(http.MethodDelete, item, "", item.UpdatedAt.Add(-time.Hour)))
+		assert.False(t, reload(item).DeletedAt.Valid)
+	})
+
+	t.Run("delete when unmodified", func(t *testing.T) {
+		item := testDB.CreateTestItem(t, "Widget", 10, 5.0)
+
+		w := send(http.MethodDelete, item, "", item.UpdatedAt.Add(time.Minute))
+		require.Equal(t, http.StatusNoContent, w.Code)
+		assert.True(t, reload(item).DeletedAt.Valid)
+	})
+
+	t.Run("delete of a missing item", func(t *testing.T) {
+		missing := &models.Item{ID: uuid.New()}
+		w := send(http.MethodDelete, missing, "", time.Now())
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestItemHandler_BulkTag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemControllerWithService(utils.NewItemServiceWithDB(testDB.DB))
+	router.POST("/inventory/bulk-tag", handler.BulkTag)
+	router.GET("/inventory/:id/tags", handler.GetItemTags)
+
+	bulkTag := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/inventory/bulk-tag", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	getTags := func(item *models.Item) []string {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/inventory/"+item.ID.String()+"/tags", nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var tags []string
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tags))
+		return tags
+	}
+
+	usbCable := testDB.CreateTestItem(t, "USB Cable", 10, 5.0)
+	hdmiCable := testDB.CreateTestItem(t, "HDMI Cable", 5, 15.0)
+	expensiveCable := testDB.CreateTestItem(t, "Optical Cable", 5, 80.0)
+	mouse := testDB.CreateTestItem(t, "Mouse", 20, 25.0)
+
+	w := bulkTag(`{"filter": {"name": "cable", "max_price": 50}, "tags": ["clearance", "cables"]}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var response map[string]int64
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, int64(2), response["tagged"])
+
+	assert.Equal(t, []string{"cables", "clearance"}, getTags(usbCable))
+	assert.Equal(t, []string{"cables", "clearance"}, getTags(hdmiCable))
+	assert.Empty(t, getTags(expensiveCable))
+	assert.Empty(t, getTags(mouse))
+
+	t.Run("tags already attached are kept", func(t *testing.T) {
+		w := bulkTag(`{"filter": {"name": "usb"}, "tags": ["clearance", "usb"]}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, []string{"cables", "clearance", "usb"}, getTags(usbCable))
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		w := bulkTag(`{"filter": {"name": "keyboard"}, "tags": ["clearance"]}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, int64(0), response["tagged"])
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		for name, body := range map[string]string{
+			"no tags":        `{"filter": {"name": "cable"}, "tags": []}`,
+			"empty tag":      `{"filter": {"name": "cable"}, "tags": [""]}`,
+			"duplicate tags": `{"filter": {"name": "cable"}, "tags": ["a", "a"]}`,
+			"tag too long":   fmt.Sprintf(`{"filter": {}, "tags": [%q]}`, strings.Repeat("t", models.MaxTagLength+1)),
+			"invalid filter": `{"filter": {"min_price": -1}, "tags": ["clearance"]}`,
+		} {
+			assert.Equal(t, http.StatusBadRequest, bulkTag(body).Code, name)
+		}
+	})
+
+	t.Run("tags of a missing item", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/inventory/"+uuid.New().String()+"/tags", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestItemHandler_GetItemsSortInjection(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemControllerWithService(utils.NewItemServiceWithDB(testDB.DB))
+	router.GET("/inventory", handler.GetItems)
+
+	testDB.CreateTestItem(t, "Widget", 1, 1.0)
+
+	for _, sortBy := range []string{"stock;DROP TABLE items", "stock;DROP TABLE items;--", "price desc, (SELECT 1)"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/inventory?sort_by="+url.QueryEscape(sortBy), nil))
+		require.Equal(t, http.StatusBadRequest, w.Code, sortBy)
+
+		var response models.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Invalid sort parameters", response.Error)
+	}
+
+	var count int64
+	require.NoError(t, testDB.DB.Model(&models.Item{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestItemHandler_GetItemStock(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemControllerWithService(utils.NewItemServiceWithDB(testDB.DB))
+	router.GET("/inventory/:id/stock", handler.GetItemStock)
+
+	item := testDB.CreateTestItem(t, "Widget", 42, 9.99)
+
+	get := func(id string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/inventory/"+id+"/stock", nil))
+		return w
+	}
+
+	t.Run("minimal response", func(t *testing.T) {
+		w := get(item.ID.String())
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "public, max-age=5", w.Header().Get("Cache-Control"))
+
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, map[string]interface{}{
+			"id":        item.ID.String(),
+			"stock":     float64(42),
+			"available": float64(42),
+		}, body)
+	})
+
+	t.Run("unknown item", func(t *testing.T) {
+		w := get(uuid.New().String())
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Empty(t, w.Header().Get("Cache-Control"))
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, get("not-a-uuid").Code)
+	})
+}
+
+func TestItemHandler_CompareItems(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemControllerWithService(utils.NewItemServiceWithDB(testDB.DB))
+	router.POST("/inventory/compare", handler.CompareItems)
+
+	compare := func(ids []string) (int, models.CompareItemsResponse) {
+		reqBody, err := json.Marshal(models.CompareItemsRequest{IDs: ids})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/inventory/compare", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response models.CompareItemsResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		}
+		return w.Code, response
+	}
+
+	laptop := testDB.CreateTestItem(t, "Laptop", 10, 999.99)
+	tablet := testDB.CreateTestItem(t, "Tablet", 25, 499.50)
+	desktop := testDB.CreateTestItem(t, "Desktop", 4, 1299.99)
+
+	t.Run("deltas relative to the first item", func(t *testing.T) {
+		code, response := compare([]string{laptop.ID.String(), tablet.ID.String(), desktop.ID.String()})
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, response.Items, 3)
+
+		assert.Equal(t, laptop.ID, response.Items[0].Item.ID)
+		assert.Zero(t, response.Items[0].PriceDelta)
+		assert.Zero(t, response.Items[0].StockDelta)
+
+		assert.Equal(t, tablet.ID, response.Items[1].Item.ID)
+		assert.Equal(t, -500.49, response.Items[1].PriceDelta)
+		assert.Equal(t, 15, response.Items[1].StockDelta)
+
+		assert.Equal(t, desktop.ID, response.Items[2].Item.ID)
+		assert.Equal(t, 300.00, response.Items[2].PriceDelta)
+		assert.Equal(t, -6, response.Items[2].StockDelta)
+	})
+
+	t.Run("the first id sets the base", func(t *testing.T) {
+		code, response := compare([]string{desktop.ID.String(), laptop.ID.String()})
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, response.Items, 2)
+		assert.Equal(t, -300.00, response.Items[1].PriceDelta)
+		assert.Equal(t, 6, response.Items[1].StockDelta)
+	})
+
+	t.Run("missing item", func(t *testing.T) {
+		code, _ := compare([]string{laptop.ID.String(), uuid.New().String()})
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+
+	tooMany := make([]string, models.MaxCompareItems+1)
+	for i := range tooMany {
+		tooMany[i] = uuid.New().String()
+	}
+	for name, ids := range map[string][]string{
+		"single id":     {laptop.ID.String()},
+		"too many ids":  tooMany,
+		"invalid uuid":  {laptop.ID.String(), "not-a-uuid"},
+		"duplicate ids": {laptop.ID.String(), laptop.ID.String()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			code, _ := compare(ids)
+			assert.Equal(t, http.StatusBadRequest, code)
+		})
+	}
+}
+
+func TestItemHandler_DisplayCurrency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	laptop := testDB.CreateTestItem(t, "Laptop", 5, 1000.00)
+	require.NoError(t, testDB.DB.Model(&models.Item{}).Where("id = ?", laptop.ID).Update("currency", "GBP").Error)
+	mouse := testDB.CreateTestItem(t, "Mouse", 20, 25.50)
+
+	setup := func(currency utils.CurrencyConfig) *gin.Engine {
+		router := utils.SetupTestRouter()
+		handler := controllers.NewItemControllerWithService(utils.NewItemServiceWithConfig(testDB.DB, &utils.Config{Currency: currency}))
+		router.GET("/inventory", handler.GetItems)
+		router.GET("/inventory/:id", handler.GetItem)
+		return router
+	}
+	get := func(router *gin.Engine, path string) (int, []byte) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w.Code, w.Body.Bytes()
+	}
+	getItem := func(router *gin.Engine, path string) models.Item {
+		code, body := get(router, path)
+		require.Equal(t, http.StatusOK, code, string(body))
+		var item models.Item
+		require.NoError(t, json.Unmarshal(body, &item))
+		return item
+	}
+
+	router := setup(utils.CurrencyConfig{FXRates: "EUR=0.5,GBP=0.8"})
+
+	t.Run("converts with the configured rate", func(t *testing.T) {
+		item := getItem(router, "/inventory/"+mouse.ID.String()+"?display_currency=eur")
+		assert.Equal(t, 25.50, item.Price)
+		assert.Equal(t, "USD", item.Currency)
+		assert.Equal(t, &models.Conversion{Amount: 12.75, Currency: "EUR", Rate: 0.5}, item.Converted)
+
+		item = getItem(router, "/inventory/"+laptop.ID.String()+"?display_currency=EUR")
+		assert.Equal(t, 1000.00, item.Price)
+		assert.Equal(t, &models.Conversion{Amount: 625, Currency: "EUR", Rate: 0.625}, item.Converted)
+	})
+
+	t.Run("does not change the cached item", func(t *testing.T) {
+		item := getItem(router, "/inventory/"+mouse.ID.String())
+		assert.Nil(t, item.Converted)
+	})
+
+	t.Run("returns the original price without a rate", func(t *testing.T) {
+		item := getItem(router, "/inventory/"+mouse.ID.String()+"?display_currency=JPY")
+		assert.Equal(t, 25.50, item.Price)
+		assert.Nil(t, item.Converted)
+	})
+
+	t.Run("converts listed items", func(t *testing.T) {
+		code, body := get(router, "/inventory?display_currency=EUR&sort_by=name&sort_order=asc")
+		require.Equal(t, http.StatusOK, code)
+		var response models.PaginatedResponse
+		require.NoError(t, json.Unmarshal(body, &response))
+		require.Len(t, response.Items, 2)
+		assert.Equal(t, 625.0, response.Items[0].Converted.Amount)
+		assert.Equal(t, 12.75, response.Items[1].Converted.Amount)
+
+		code, body = get(router, "/inventory?display_currency=EUR&fields=name,price,currency&sort_by=name&sort_order=asc")
+		require.Equal(t, http.StatusOK, code)
+		var partial models.PartialPaginatedResponse
+		require.NoError(t, json.Unmarshal(body, &partial))
+		require.Len(t, partial.Items, 2)
+		assert.Equal(t, map[string]interface{}{"amount": 625.0, "currency": "EUR", "rate": 0.625}, partial.Items[0]["converted_price"])
+	})
+
+	t.Run("uses the configured display currency", func(t *testing.T) {
+		router := setup(utils.CurrencyConfig{FXRates: "EUR=0.5", DisplayCurrency: "EUR"})
+		item := getItem(router, "/inventory/"+mouse.ID.String())
+		require.NotNil(t, item.Converted)
+		assert.Equal(t, "EUR", item.Converted.Currency)
+	})
+
+	t.Run("rejects an invalid currency", func(t *testing.T) {
+		code, _ := get(router, "/inventory/"+mouse.ID.String()+"?display_currency=euro")
+		assert.Equal(t, http.StatusBadRequest, code)
+		code, _ = get(router, "/inventory?display_currency=E1")
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+}
+
+func TestItemHandler_DecodeCursor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+	router.Use(utils.AuthMiddleware("test-api-key"))
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	service := utils.NewItemServiceWithDB(testDB.DB)
+	handler := controllers.NewItemControllerWithService(service)
+	router.GET("/inventory", handler.GetItems)
+	router.GET("/inventory/cursor/decode", utils.RequireAuth(), handler.DecodeCursor)
+
+	apple := testDB.CreateTestItem(t, "Apple", 5, 1.00)
+	testDB.CreateTestItem(t, "Banana", 5, 1.00)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/inventory?limit=1&sort_by=name&sort_order=asc", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var page models.PaginatedResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	require.NotEmpty(t, page.NextCursor)
+
+	decode := func(cursor string, authenticated bool) (int, models.CursorInfo) {
+		req := httptest.NewRequest(http.MethodGet, "/inventory/cursor/decode?cursor="+url.QueryEscape(cursor), nil)
+		if authenticated {
+			req.Header.Set("Authorization", "Bearer test-api-key")
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var info models.CursorInfo
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &info))
+		}
+		return w.Code, info
+	}
+
+	t.Run("requires api key", func(t *testing.T) {
+		code, _ := decode(page.NextCursor, false)
+		assert.Equal(t, http.StatusUnauthorized, code)
+	})
+
+	t.Run("valid cursor", func(t *testing.T) {
+		code, info := decode(page.NextCursor, true)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, models.CursorInfo{
+			ID:           apple.ID.String(),
+			SortBy:       "name",
+			SortOrder:    "asc",
+			Value:        "Apple",
+			Exists:       true,
+			CurrentValue: "Apple",
+		}, info)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		for _, cursor := range []string{"", "not-a-cursor", "eyJpZCI6Im5vdC1hLXV1aWQifQ=="} {
+			code, _ := decode(cursor, true)
+			assert.Equal(t, http.StatusBadRequest, code, cursor)
+		}
+	})
+
+	t.Run("item moved since", func(t *testing.T) {
+		_, err := service.UpdateItem(apple.ID.String(), &models.UpdateItemRequest{Name: utils.StringPtr("Cherry")})
+		require.NoError(t, err)
+
+		code, info := decode(page.NextCursor, true)
+		require.Equal(t, http.StatusOK, code)
+		assert.True(t, info.Exists)
+		assert.True(t, info.Moved)
+		assert.Equal(t, "Cherry", info.CurrentValue)
+	})
+
+	t.Run("stale cursor", func(t *testing.T) {
+		require.NoError(t, service.DeleteItem(apple.ID.String()))
+
+		code, info := decode(page.NextCursor, true)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, apple.ID.String(), info.ID)
+		assert.False(t, info.Exists)
+		assert.False(t, info.Moved)
+		assert.Empty(t, info.CurrentValue)
+	})
+}
+
+func TestItemHandler_Head(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	handler := controllers.NewItemControllerWithService(utils.NewItemServiceWithDB(testDB.DB))
+	router.GET("/inventory", handler.GetItems)
+	router.HEAD("/inventory", utils.HeadMiddleware(), handler.GetItems)
+	router.GET("/inventory/:id", handler.GetItem)
+	router.HEAD("/inventory/:id", utils.HeadMiddleware(), handler.GetItem)
+
+	item := testDB.CreateTestItem(t, "Laptop", 5, 999.99)
+	testDB.CreateTestItem(t, "Mouse", 20, 25.00)
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	t.Run("existing item", func(t *testing.T) {
+		get := serve(http.MethodGet, "/inventory/"+item.ID.String())
+		head := serve(http.MethodHead, "/inventory/"+item.ID.String())
+
+		assert.Equal(t, http.StatusOK, head.Code)
+		assert.Empty(t, head.Body.String())
+		assert.NotEmpty(t, head.Header().Get("ETag"))
+		assert.Equal(t, get.Header().Get("ETag"), head.Header().Get("ETag"))
+		assert.Equal(t, item.UpdatedAt.UTC().Format(http.TimeFormat), head.Header().Get("Last-Modified"))
+	})
+
+	t.Run("etag changes when the item is updated", func(t *testing.T) {
+		before := serve(http.MethodHead, "/inventory/"+item.ID.String()).Header().Get("ETag")
+		require.NoError(t, testDB.DB.Model(&models.Item{}).Where("id = ?", item.ID).
+			Update("updated_at", item.UpdatedAt.Add(time.Minute)).Error)
+
+		// A fresh service, since the first one has the item cached
+		service := utils.NewItemServiceWithDB(testDB.DB)
+		fresh := utils.SetupTestRouter()
+		fresh.HEAD("/inventory/:id", utils.HeadMiddleware(), controllers.NewItemControllerWithService(service).GetItem)
+		w := httptest.NewRecorder()
+		fresh.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/inventory/"+item.ID.String(), nil))
+		assert.NotEqual(t, before, w.Header().Get("ETag"))
+	})
+
+	t.Run("missing item", func(t *testing.T) {
+		head := serve(http.MethodHead, "/inventory/"+uuid.New().String())
+		assert.Equal(t, http.StatusNotFound, head.Code)
+		assert.Empty(t, head.Body.String())
+		assert.Empty(t, head.Header().Get("ETag"))
+	})
+
+	t.Run("list", func(t *testing.T) {
+		head := serve(http.MethodHead, "/inventory?limit=1")
+		assert.Equal(t, http.StatusOK, head.Code)
+		assert.Empty(t, head.Body.String())
+		assert.Equal(t, "2", head.Header().Get("X-Total-Count"))
+		assert.NotEmpty(t, head.Header().Get("Link"))
+		assert.NotEmpty(t, head.Header().Get("Last-Modified"))
+	})
+}
+
+func TestAdminHandler_GetConfig(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+	router.Use(utils.AuthMiddleware("test-api-key"))
+
+	cfg := &utils.Config{}
+	cfg.Database.User = "inventory"
+	cfg.Database.Password = "hunter2"
+	cfg.Database.BreakerCooldown = 30 * time.Second
+	cfg.Auth.APIKey = "test-api-key"
+	cfg.RateLimit.Requests = 1
+
+	handler := controllers.NewAdminController(nil, cfg)
+	router.GET("/admin/config", utils.RequireAuth(), handler.GetConfig)
+
+	getConfig := func(authenticated bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/admin/config", nil)
+		if authenticated {
+			req.Header.Set("Authorization", "Bearer test-api-key")
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("requires the API key", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, getConfig(false).Code)
+	})
+
+	t.Run("redacts secrets", func(t *testing.T) {
+		w := getConfig(true)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "hunter2")
+		assert.NotContains(t, w.Body.String(), "test-api-key")
+
+		var config map[string]map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &config))
+		assert.Equal(t, utils.RedactedValue, config["database"]["password"])
+		assert.Equal(t, utils.RedactedValue, config["auth"]["api_key"])
+		assert.Equal(t, "inventory", config["database"]["user"])
+		assert.Equal(t, "30s", config["database"]["breaker_cooldown"])
+		assert.Equal(t, float64(1), config["rate_limit"]["requests"])
+	})
+}
+
+func TestItemHandler_GetRelatedItems(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	service := utils.NewItemServiceWithDB(testDB.DB)
+	handler := controllers.NewItemControllerWithService(service)
+	router.GET("/inventory/:id/related", handler.GetRelatedItems)
+
+	laptops, err := service.CreateCategory(&models.CreateCategoryRequest{Name: "Laptops"})
+	require.NoError(t, err)
+	phones, err := service.CreateCategory(&models.CreateCategoryRequest{Name: "Phones"})
+	require.NoError(t, err)
+
+	create := func(name string, price float64, category *models.Category) *models.Item {
+		item := testDB.CreateTestItem(t, name, 5, price)
+		if category != nil {
+			require.NoError(t, testDB.DB.Model(item).UpdateColumn("category_id", category.ID).Error)
+		}
+		return item
+	}
+	source := create("Laptop", 1000.00, laptops)
+	nearest := create("Laptop Pro", 1050.00, laptops)
+	cheaper := create("Laptop Air", 850.00, laptops)
+	create("Laptop Max", 1300.00, laptops)
+	create("Phone", 1000.00, phones)
+	create("Uncategorized", 1000.00, nil)
+	retired := create("Laptop Old", 1000.00, laptops)
+	require.NoError(t, testDB.DB.Model(retired).Update("active", false).Error)
+
+	related := func(id, query string) (*httptest.ResponseRecorder, []models.Item) {
+		req := httptest.NewRequest(http.MethodGet, "/inventory/"+id+"/related"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response models.RelatedItemsResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		}
+		return w, response.Items
+	}
+	names := func(items []models.Item) []string {
+		var names []string
+		for _, item := range items {
+			names = append(names, item.Name)
+		}
+		return names
+	}
+
+	t.Run("same category within the price spread, closest first", func(t *testing.T) {
+		w, items := related(source.ID.String(), "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{nearest.Name, cheaper.Name}, names(items))
+		for _, item := range items {
+			assert.NotEqual(t, source.ID, item.ID, "the source item is excluded")
+			require.NotNil(t, item.CategoryID)
+			assert.Equal(t, laptops.ID, *item.CategoryID)
+		}
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		_, items := related(source.ID.String(), "?limit=1")
+		assert.Equal(t, []string{nearest.Name}, names(items))
+
+		w, _ := related(source.ID.String(), "?limit=21")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("nothing related", func(t *testing.T) {
+		w, items := related(nearest.ID.String(), "?limit=20")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, names(items), nearest.Name)
+
+		phone := create("Phone Case", 5.00, phones)
+		w, items = related(phone.ID.String(), "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotNil(t, items)
+		assert.Empty(t, items)
+	})
+
+	t.Run("unknown item", func(t *testing.T) {
+		w, _ := related(uuid.New().String(), "")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w, _ = related("not-a-uuid", "")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestItemHandler_NormalizePrices(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+	router.Use(utils.AuthMiddleware("test-api-key"))
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	service := utils.NewItemServiceWithDB(testDB.DB)
+	handler := controllers.NewItemControllerWithService(service)
+	router.POST("/admin/normalize-prices", utils.RequireAuth(), handler.NormalizePrices)
+
+	messy := map[string]float64{"Long Tail": 9.901, "Almost Twenty": 19.999, "Pi": 3.14159, "Clean": 5.5}
+	items := make(map[string]*models.Item, len(messy))
+	for name, price := range messy {
+		items[name] = testDB.CreateTestItem(t, name, 1, 1.00)
+		require.NoError(t, testDB.DB.Model(items[name]).UpdateColumn("price", price).Error)
+	}
+	deleted := testDB.CreateTestItem(t, "Deleted", 1, 1.00)
+	require.NoError(t, testDB.DB.Model(deleted).UpdateColumn("price", 7.777).Error)
+	require.NoError(t, testDB.DB.Delete(deleted).Error)
+
+	// Cached copies must not keep serving the old price
+	cached, err := service.GetItem(items["Pi"].ID.String())
+	require.NoError(t, err)
+	require.Equal(t, 3.14159, cached.Price)
+
+	normalize := func(authenticated bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/admin/normalize-prices", nil)
+		if authenticated {
+			req.Header.Set("Authorization", "Bearer test-api-key")
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, normalize(false).Code)
+
+	w := normalize(true)
+	require.Equal(t, http.StatusOK, w.Code)
+	var response map[string]int64
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, int64(4), response["normalized"])
+
+	expected := map[string]float64{"Long Tail": 9.90, "Almost Twenty": 20.00, "Pi": 3.14, "Clean": 5.50}
+	for name, price := range expected {
+		item, err := service.GetItem(items[name].ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, price, item.Price, name)
+	}
+	var softDeleted models.Item
+	require.NoError(t, testDB.DB.Unscoped().First(&softDeleted, "id = ?", deleted.ID).Error)
+	assert.Equal(t, 7.78, softDeleted.Price)
+
+	t.Run("running again changes nothing", func(t *testing.T) {
+		w := normalize(true)
+		require.Equal(t, http.StatusOK, w.Code)
+		var response map[string]int64
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Zero(t, response["normalized"])
+	})
+}
+
+func TestItemHandler_GetItem_IncludeDeleted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+	router.Use(utils.AuthMiddleware("test-api-key"))
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	service := utils.NewItemServiceWithDB(testDB.DB)
+	handler := controllers.NewItemControllerWithService(service)
+	router.GET("/inventory/:id", handler.GetItem)
+
+	live := testDB.CreateTestItem(t, "Live", 5, 1.00)
+	deleted := testDB.CreateTestItem(t, "Deleted", 5, 1.00)
+	require.NoError(t, service.DeleteItem(deleted.ID.String()))
+
+	get := func(id, query string, authenticated bool) (*httptest.ResponseRecorder, map[string]interface{}) {
+		req := httptest.NewRequest(http.MethodGet, "/inventory/"+id+query, nil)
+		if authenticated {
+			req.Header.Set("Authorization", "Bearer test-api-key")
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return w, body
+	}
+
+	t.Run("deleted item without the flag", func(t *testing.T) {
+		w, _ := get(deleted.ID.String(), "", true)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("deleted item with the flag", func(t *testing.T) {
+		w, body := get(deleted.ID.String(), "?include_deleted=true", true)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "Deleted", body["name"])
+		assert.NotEmpty(t, body["deleted_at"])
+	})
+
+	t.Run("live item without the flag", func(t *testing.T) {
+		w, body := get(live.ID.String(), "", false)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "Live", body["name"])
+		assert.Nil(t, body["deleted_at"])
+	})
+
+	t.Run("live item with the flag", func(t *testing.T) {
+		w, body := get(live.ID.String(), "?include_deleted=true", true)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "Live", body["name"])
+		assert.Nil(t, body["deleted_at"])
+	})
+
+	t.Run("flag requires the API key", func(t *testing.T) {
+		w, _ := get(deleted.ID.String(), "?include_deleted=true", false)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("unknown item with the flag", func(t *testing.T) {
+		w, _ := get(uuid.New().String(), "?include_deleted=true", true)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestItemHandler_GetItems_NormalizedName(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	service := utils.NewItemServiceWithDB(testDB.DB)
+	handler := controllers.NewItemControllerWithService(service)
+	router.GET("/inventory", handler.GetItems)
+
+	testDB.CreateTestItem(t, "Apple iPhone 15", 10, 799.00)
+	testDB.CreateTestItem(t, "I-Phone  Case!", 50, 19.99)
+	renamed := testDB.CreateTestItem(t, "Charger", 20, 29.99)
+	testDB.CreateTestItem(t, "Phone Stand", 5, 14.99)
+
+	// Renaming an item recomputes its search name
+	name := "iPhone Charger"
+	_, err := service.UpdateItem(renamed.ID.String(), &models.UpdateItemRequest{Name: &name})
+	require.NoError(t, err)
+
+	names := func(keyword string) []string {
+		req := httptest.NewRequest(http.MethodGet, "/inventory?sort_by=name&sort_order=asc&name="+url.QueryEscape(keyword), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response models.PaginatedResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		found := []string{}
+		for _, item := range response.Items {
+			found = append(found, item.Name)
+		}
+		return found
+	}
+
+	expected := []string{"Apple iPhone 15", "I-Phone  Case!", "iPhone Charger"}
+	assert.Equal(t, expected, names("iphone"))
+	assert.Equal(t, expected, names("IPHONE"))
+	assert.Equal(t, expected, names("I-Phone"))
+	assert.Equal(t, []string{"I-Phone  Case!"}, names("phone case"))
+	assert.Empty(t, names("i phone"), "spaces are kept by default")
+	assert.Equal(t, []string{"I-Phone  Case!"}, names("!"), "keywords without searchable characters match the raw name")
+
+	t.Run("remove_spaces", func(t *testing.T) {
+		normalization, err := models.ParseSearchNormalization("lowercase,strip_punctuation,remove_spaces")
+		require.NoError(t, err)
+		models.SetSearchNormalization(normalization)
+		defer func() {
+			defaults, err := models.ParseSearchNormalization(models.DefaultSearchNormalization)
+			require.NoError(t, err)
+			models.SetSearchNormalization(defaults)
+			_, err = service.RefreshSearchNames()
+			require.NoError(t, err)
+		}()
+
+		updated, err := service.RefreshSearchNames()
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), updated)
+
+		assert.Equal(t, expected, names("i phone"))
+		assert.Equal(t, []string{"Phone Stand"}, names("phonestand"))
+	})
+}
+
+func TestItemHandler_GetItemSchema(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	schema := func(cfg *utils.Config) models.ItemSchemaResponse {
+		router := utils.SetupTestRouter()
+		handler := controllers.NewItemControllerWithService(utils.NewItemServiceWithConfig(testDB.DB, cfg))
+		router.GET("/inventory/schema", handler.GetItemSchema)
+
+		req := httptest.NewRequest(http.MethodGet, "/inventory/schema", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response models.ItemSchemaResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	t.Run("default limits", func(t *testing.T) {
+		response := schema(&utils.Config{})
+
+		name := response.Create["name"]
+		assert.Equal(t, "string", name.Type)
+		assert.True(t, name.Required)
+		require.NotNil(t, name.MinLength)
+		assert.Equal(t, 1, *name.MinLength)
+		require.NotNil(t, name.MaxLength)
+		assert.Equal(t, models.DefaultMaxNameLength, *name.MaxLength)
+
+		stock := response.Create["stock"]
+		assert.Equal(t, "integer", stock.Type)
+		require.NotNil(t, stock.Min)
+		assert.Equal(t, 0.0, *stock.Min)
+		require.NotNil(t, stock.Max)
+		assert.Equal(t, float64(models.DefaultMaxStock), *stock.Max)
+
+		price := response.Create["price"]
+		assert.Equal(t, "number", price.Type)
+		require.NotNil(t, price.Max)
+		assert.Equal(t, models.DefaultMaxPrice, *price.Max)
+
+		assert.Contains(t, response.Create["currency"].Enum, "EUR")
+		assert.Equal(t, "uri", response.Create["image_url"].Format)
+		assert.Equal(t, "uuid", response.Create["category_id"].Format)
+		assert.Equal(t, "boolean", response.Create["auto_reorder"].Type)
+		assert.NotContains(t, response.Create, "Actor", "fields hidden from JSON are not listed")
+
+		assert.False(t, response.Update["name"].Required, "updates only change the fields given")
+		assert.Contains(t, response.Update, "active")
+		assert.NotContains(t, response.Update, "LockOwner")
+	})
+
+	t.Run("configured limits", func(t *testing.T) {
+		response := schema(&utils.Config{Validation: utils.ValidationConfig{MaxNameLength: 80, MaxStock: 5000, MaxPrice: 2500.50}})
+
+		for action, rules := range map[string]map[string]models.FieldRule{"create": response.Create, "update": response.Update} {
+			require.NotNil(t, rules["name"].MaxLength, action)
+			assert.Equal(t, 80, *rules["name"].MaxLength, action)
+			require.NotNil(t, rules["stock"].Max, action)
+			assert.Equal(t, 5000.0, *rules["stock"].Max, action)
+			require.NotNil(t, rules["price"].Max, action)
+			assert.Equal(t, 2500.50, *rules["price"].Max, action)
+		}
+	})
+}
+
+func TestItemHandler_GetItems_Uncategorized(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := utils.SetupTestRouter()
+
+	testDB := utils.NewTestDB(t)
+	defer testDB.Close()
+
+	service := utils.NewItemServiceWithDB(testDB.DB)
+	handler := controllers.NewItemControllerWithService(service)
+	router.GET("/inventory", handler.GetItems)
+
+	laptops, err := service.CreateCategory(&models.CreateCategoryRequest{Name: "Laptops"})
+	require.NoError(t, err)
+	for _, name := range []string{"Laptop Pro", "Laptop Air"} {
+		item := testDB.CreateTestItem(t, name, 5, 999.99)
+		require.NoError(t, testDB.DB.Model(item).UpdateColumn("category_id", laptops.ID).Error)
+	}
+	testDB.CreateTestItem(t, "Mystery Cable", 10, 4.99)
+	testDB.CreateTestItem(t, "Laptop Sleeve", 20, 19.99)
+	retired := testDB.CreateTestItem(t, "Old Adapter", 0, 2.99)
+	require.NoError(t, testDB.DB.Model(retired).Update("active", false).Error)
+
+	names := func(query string) []string {
+		req := httptest.NewRequest(http.MethodGet, "/inventory?sort_by=name&sort_order=asc&"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response models.PaginatedResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		found := []string{}
+		for _, item := range response.Items {
+			found = append(found, item.Name)
+		}
+		return found
+	}
+
+	assert.Equal(t, []string{"Laptop Sleeve", "Mystery Cable"}, names("uncategorized=true"))
+	assert.Equal(t, []string{"Laptop Sleeve"}, names("uncategorized=true&name=laptop"))
+	assert.Equal(t, []string{"Laptop Sleeve", "Mystery Cable", "Old Adapter"}, names("uncategorized=true&include_inactive=true"))
+	assert.Len(t, names("uncategorized=false"), 4, "uncategorized=false does not filter")
+}
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"strings"
+
+	"inventory-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActorHeader is the header naming who is making a change
+const ActorHeader = "X-Actor"
+
+// AdminActor is recorded for changes authenticated with the admin API key
+// that do not name an actor
+const AdminActor = "admin"
+
+// RequestActor returns who is making the request: the X-Actor header if set,
+// otherwise AdminActor for requests carrying the API key, otherwise
+// models.SystemActor. Names longer than models.MaxActorLength are truncated.
+func RequestActor(c *gin.Context) string {
+	if actor := strings.TrimSpace(c.GetHeader(ActorHeader)); actor != "" {
+		if runes := []rune(actor); len(runes) > models.MaxActorLength {
+			actor = string(runes[:models.MaxActorLength])
+		}
+		return actor
+	}
+
+	if IsAuthenticated(c) {
+		return AdminActor
+	}
+
+	return models.SystemActor
+}
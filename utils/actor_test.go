@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"inventory-api/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestActor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name          string
+		header        string
+		authenticated bool
+		want          string
+	}{
+		{name: "actor header", header: "jane.doe", want: "jane.doe"},
+		{name: "actor header wins over api key", header: "jane.doe", authenticated: true, want: "jane.doe"},
+		{name: "api key", authenticated: true, want: AdminActor},
+		{name: "anonymous", want: models.SystemActor},
+		{name: "blank header", header: "   ", want: models.SystemActor},
+		{name: "long header", header: strings.Repeat("a", models.MaxActorLength+10), want: strings.Repeat("a", models.MaxActorLength)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("POST", "/inventory", nil)
+			if tt.header != "" {
+				c.Request.Header.Set(ActorHeader, tt.header)
+			}
+			if tt.authenticated {
+				c.Set(AuthenticatedKey, true)
+			}
+
+			assert.Equal(t, tt.want, RequestActor(c))
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuthenticatedKey is the context key set when a request carries a valid API key
+const AuthenticatedKey = "authenticated"
+
+// AuthMiddleware marks requests whose Authorization header matches the
+// configured API key as authenticated. It never rejects a request on its
+// own; use RequireAuth to gate routes. An empty key disables authentication.
+func AuthMiddleware(apiKey string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if apiKey != "" {
+			token := strings.TrimSpace(strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer "))
+			if subtle.ConstantTimeCompare([]byte(token), []byte(apiKey)) == 1 {
+				c.Set(AuthenticatedKey, true)
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// RequireAuth rejects requests that were not authenticated by AuthMiddleware
+func RequireAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !IsAuthenticated(c) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":   "Unauthorized",
+				"message": "A valid API key is required",
+				"code":    http.StatusUnauthorized,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// IsAuthenticated reports whether the request carries a valid API key
+func IsAuthenticated(c *gin.Context) bool {
+	return c.GetBool(AuthenticatedKey)
+}
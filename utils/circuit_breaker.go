@@ -0,0 +1,189 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrCircuitOpen is returned while the circuit breaker is open, so
+// statements fail fast instead of piling onto a struggling database
+var ErrCircuitOpen = errors.New("database circuit breaker is open, retry later")
+
+// Circuit breaker states, as reported by CircuitBreaker.State
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half-open"
+)
+
+// circuitBreakerAllowed marks statements let through to the database, so
+// only their outcome is recorded
+const circuitBreakerAllowed = "circuit_breaker:allowed"
+
+// circuitBreakerProbe marks the statement sent to test a half-open breaker
+const circuitBreakerProbe = "circuit_breaker:probe"
+
+// CircuitBreaker is a GORM plugin that stops sending statements to the
+// database after threshold consecutive statements fail with a connection
+// or server error. While open, statements fail with ErrCircuitOpen. Once
+// cooldown has passed the breaker half-opens and lets a single statement
+// through: success closes it again, failure reopens it for another cooldown.
+type CircuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	state    string
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewCircuitBreaker creates a closed breaker opening after threshold
+// consecutive failures and half-opening after cooldown
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+		state:     BreakerClosed,
+	}
+}
+
+// Name implements gorm.Plugin
+func (b *CircuitBreaker) Name() string {
+	return "circuit_breaker"
+}
+
+// Initialize implements gorm.Plugin, checking the breaker before each
+// statement and recording the outcome after it
+func (b *CircuitBreaker) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().Before("gorm:create").Register("circuit_breaker:allow", b.allow),
+		callbacks.Create().After("gorm:create").Register("circuit_breaker:record", b.record),
+		callbacks.Query().Before("gorm:query").Register("circuit_breaker:allow", b.allow),
+		callbacks.Query().After("gorm:query").Register("circuit_breaker:record", b.record),
+		callbacks.Update().Before("gorm:update").Register("circuit_breaker:allow", b.allow),
+		callbacks.Update().After("gorm:update").Register("circuit_breaker:record", b.record),
+		callbacks.Delete().Before("gorm:delete").Register("circuit_breaker:allow", b.allow),
+		callbacks.Delete().After("gorm:delete").Register("circuit_breaker:record", b.record),
+		callbacks.Row().Before("gorm:row").Register("circuit_breaker:allow", b.allow),
+		callbacks.Row().After("gorm:row").Register("circuit_breaker:record", b.record),
+		callbacks.Raw().Before("gorm:raw").Register("circuit_breaker:allow", b.allow),
+		callbacks.Raw().After("gorm:raw").Register("circuit_breaker:record", b.record),
+	)
+}
+
+// State reports whether the breaker is closed, open or half-open
+func (b *CircuitBreaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.cooldown {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+// allow fails the statement with ErrCircuitOpen unless the breaker is
+// closed, or half-open with no other statement probing the database
+func (b *CircuitBreaker) allow(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.cooldown {
+		b.state = BreakerHalfOpen
+	}
+
+	switch b.state {
+	case BreakerClosed:
+	case BreakerHalfOpen:
+		if b.probing {
+			db.AddError(ErrCircuitOpen)
+			return
+		}
+		b.probing = true
+		db.InstanceSet(circuitBreakerProbe, true)
+	default:
+		db.AddError(ErrCircuitOpen)
+		return
+	}
+	db.InstanceSet(circuitBreakerAllowed, true)
+}
+
+// record counts the statement's failure towards opening the breaker, or
+// closes the breaker again after a success
+func (b *CircuitBreaker) record(db *gorm.DB) {
+	if allowed, _ := db.InstanceGet(circuitBreakerAllowed); allowed != true {
+		return
+	}
+	probe, _ := db.InstanceGet(circuitBreakerProbe)
+	db.InstanceSet(circuitBreakerAllowed, false)
+	db.InstanceSet(circuitBreakerProbe, false)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if probe == true {
+		b.probing = false
+	}
+
+	if !isDatabaseFailure(db.Error) {
+		if probe == true || b.state == BreakerClosed {
+			b.state = BreakerClosed
+			b.failures = 0
+		}
+		return
+	}
+
+	b.failures++
+	if probe == true || (b.state == BreakerClosed && b.failures >= b.threshold) {
+		if b.state != BreakerOpen {
+			Error.Printf("Database circuit breaker opened after %d consecutive failures: %v", b.failures, db.Error)
+		}
+		b.state = BreakerOpen
+		b.openedAt = b.now()
+	}
+}
+
+// isDatabaseFailure reports whether err means the database itself is
+// unreachable or struggling. Errors caused by the statement, such as a
+// missing row or a constraint violation, leave the breaker alone.
+func isDatabaseFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) {
+		return true
+	}
+
+	// PostgreSQL reports connection problems, exhausted resources and
+	// shutdowns in SQLSTATE classes 08, 53, 57 and 58
+	var pgErr interface{ SQLState() string }
+	if errors.As(err, &pgErr) {
+		for _, class := range []string{"08", "53", "57", "58"} {
+			if strings.HasPrefix(pgErr.SQLState(), class) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"inventory-api/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// failQueries registers a callback failing every query with a broken
+// connection while *failing is true, and counting the queries that reach it
+func failQueries(t *testing.T, db *gorm.DB, failing *bool) *int {
+	reached := 0
+	err := db.Callback().Query().After("circuit_breaker:allow").Before("gorm:query").
+		Register("test:fail", func(db *gorm.DB) {
+			if db.Error != nil {
+				return
+			}
+			reached++
+			if *failing {
+				db.AddError(driver.ErrBadConn)
+			}
+		})
+	require.NoError(t, err)
+	return &reached
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Run("opens after consecutive failures and fails fast", func(t *testing.T) {
+		testDB := NewTestDB(t)
+		defer testDB.Close()
+		breaker := NewCircuitBreaker(3, time.Minute)
+		require.NoError(t, testDB.DB.Use(breaker))
+		failing := true
+		reached := failQueries(t, testDB.DB, &failing)
+
+		var items []models.Item
+		for i := 0; i < 3; i++ {
+			assert.ErrorIs(t, testDB.DB.Find(&items).Error, driver.ErrBadConn)
+		}
+		assert.Equal(t, BreakerOpen, breaker.State())
+
+		failing = false
+		for i := 0; i < 5; i++ {
+			assert.ErrorIs(t, testDB.DB.Find(&items).Error, ErrCircuitOpen)
+		}
+		assert.Equal(t, 3, *reached, "an open breaker does not reach the database")
+	})
+
+	t.Run("a success resets the failure count", func(t *testing.T) {
+		testDB := NewTestDB(t)
+		defer testDB.Close()
+		breaker := NewCircuitBreaker(2, time.Minute)
+		require.NoError(t, testDB.DB.Use(breaker))
+		failing := true
+		failQueries(t, testDB.DB, &failing)
+
+		var items []models.Item
+		assert.Error(t, testDB.DB.Find(&items).Error)
+		failing = false
+		assert.NoError(t, testDB.DB.Find(&items).Error)
+		failing = true
+		assert.Error(t, testDB.DB.Find(&items).Error)
+		assert.Equal(t, BreakerClosed, breaker.State())
+	})
+
+	t.Run("statement errors do not count", func(t *testing.T) {
+		testDB := NewTestDB(t)
+		defer testDB.Close()
+		breaker := NewCircuitBreaker(1, time.Minute)
+		require.NoError(t, testDB.DB.Use(breaker))
+
+		var item models.Item
+		for i := 0; i < 3; i++ {
+			assert.ErrorIs(t, testDB.DB.First(&item, "name = ?", "missing").Error, gorm.ErrRecordNotFound)
+		}
+		assert.Equal(t, BreakerClosed, breaker.State())
+	})
+
+	t.Run("half-opens after the cooldown", func(t *testing.T) {
+		testDB := NewTestDB(t)
+		defer testDB.Close()
+		clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+		breaker := NewCircuitBreaker(1, 30*time.Second)
+		breaker.now = clock.Now
+		require.NoError(t, testDB.DB.Use(breaker))
+		failing := true
+		reached := failQueries(t, testDB.DB, &failing)
+
+		var items []models.Item
+		assert.ErrorIs(t, testDB.DB.Find(&items).Error, driver.ErrBadConn)
+		assert.Equal(t, BreakerOpen, breaker.State())
+
+		clock.now = clock.now.Add(29 * time.Second)
+		assert.ErrorIs(t, testDB.DB.Find(&items).Error, ErrCircuitOpen)
+
+		// A failed probe reopens the breaker for another cooldown
+		clock.now = clock.now.Add(time.Second)
+		assert.Equal(t, BreakerHalfOpen, breaker.State())
+		assert.ErrorIs(t, testDB.DB.Find(&items).Error, driver.ErrBadConn)
+		assert.Equal(t, BreakerOpen, breaker.State())
+		assert.ErrorIs(t, testDB.DB.Find(&items).Error, ErrCircuitOpen)
+		assert.Equal(t, 2, *reached)
+
+		// A successful probe closes it
+		failing = false
+		clock.now = clock.now.Add(30 * time.Second)
+		assert.NoError(t, testDB.DB.Find(&items).Error)
+		assert.Equal(t, BreakerClosed, breaker.State())
+		assert.NoError(t, testDB.DB.Find(&items).Error)
+	})
+
+	t.Run("only one probe at a time", func(t *testing.T) {
+		clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+		breaker := NewCircuitBreaker(1, time.Second)
+		breaker.now = clock.Now
+		breaker.state = BreakerOpen
+		breaker.openedAt = clock.now.Add(-time.Second)
+
+		probe := &gorm.DB{Config: &gorm.Config{}, Statement: &gorm.Statement{}}
+		probe.Statement.DB = probe
+		breaker.allow(probe)
+		require.NoError(t, probe.Error)
+
+		other := &gorm.DB{Config: &gorm.Config{}, Statement: &gorm.Statement{}}
+		other.Statement.DB = other
+		breaker.allow(other)
+		assert.ErrorIs(t, other.Error, ErrCircuitOpen)
+
+		breaker.record(probe)
+		assert.Equal(t, BreakerClosed, breaker.State())
+	})
+}
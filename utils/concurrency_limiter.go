@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConcurrencyLimiter caps how many requests each client may have in flight
+// at once. It is a counting semaphore per key; keys without requests in
+// flight are forgotten, so memory only grows with concurrent clients.
+type ConcurrencyLimiter struct {
+	mu       sync.Mutex
+	limit    int
+	inFlight map[string]int
+}
+
+// NewConcurrencyLimiter creates a limiter allowing up to limit requests in
+// flight per key
+func NewConcurrencyLimiter(limit int) *ConcurrencyLimiter {
+	return &ConcurrencyLimiter{
+		limit:    limit,
+		inFlight: make(map[string]int),
+	}
+}
+
+// Acquire takes a slot for key, reporting false when all of its slots are taken
+func (l *ConcurrencyLimiter) Acquire(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.inFlight[key] >= l.limit {
+		return false
+	}
+	l.inFlight[key]++
+	return true
+}
+
+// Release frees a slot taken by Acquire
+func (l *ConcurrencyLimiter) Release(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.inFlight[key] <= 1 {
+		delete(l.inFlight, key)
+		return
+	}
+	l.inFlight[key]--
+}
+
+// ConcurrencyLimitMiddleware rejects a request with 429 when its client IP
+// already has the maximum number of requests in flight. The slot is held
+// until the request completes, including when a handler panics. Clients
+// matched by the allowlist are never limited; a nil allowlist matches no one.
+func ConcurrencyLimitMiddleware(limiter *ConcurrencyLimiter, allowlist *Allowlist) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if allowlist.Allows(c) {
+			c.Next()
+			return
+		}
+
+		key := c.ClientIP()
+		if !limiter.Acquire(key) {
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":   "Too many concurrent requests",
+				"message": "Too many requests in progress. Please wait for earlier requests to finish.",
+				"code":    http.StatusTooManyRequests,
+			})
+			c.Abort()
+			return
+		}
+		defer limiter.Release(key)
+
+		c.Next()
+	}
+}
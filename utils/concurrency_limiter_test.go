@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSlowRouter serves /slow, holding each request until release is closed,
+// behind a concurrency limit of limit. Every request that reaches the
+// handler sends on entered first.
+func newSlowRouter(t *testing.T, limit int, allowlist *Allowlist) (router *gin.Engine, entered chan struct{}, release chan struct{}) {
+	entered = make(chan struct{}, 100)
+	release = make(chan struct{})
+
+	router = SetupTestRouter()
+	router.Use(ConcurrencyLimitMiddleware(NewConcurrencyLimiter(limit), allowlist))
+	router.GET("/slow", func(c *gin.Context) {
+		entered <- struct{}{}
+		<-release
+		c.Status(http.StatusOK)
+	})
+	return router, entered, release
+}
+
+func serveFrom(router *gin.Engine, remoteAddr string) int {
+	req := httptest.NewRequest(http.MethodGet, "/slow", nil)
+	req.RemoteAddr = remoteAddr
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestConcurrencyLimitMiddleware(t *testing.T) {
+	t.Run("rejects requests beyond the limit from one IP", func(t *testing.T) {
+		router, entered, release := newSlowRouter(t, 3, nil)
+
+		const clients = 8
+		codes := make(chan int, clients)
+		var wg sync.WaitGroup
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				codes <- serveFrom(router, "192.0.2.1:1234")
+			}()
+		}
+		for i := 0; i < 3; i++ {
+			<-entered
+		}
+
+		// Every slot is held, so the rest are turned away at once
+		for i := 3; i < clients; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				codes <- serveFrom(router, "192.0.2.1:1234")
+			}()
+		}
+		for i := 3; i < clients; i++ {
+			assert.Equal(t, http.StatusTooManyRequests, <-codes)
+		}
+
+		// Another IP has its own slots
+		done := make(chan int, 1)
+		go func() { done <- serveFrom(router, "192.0.2.2:1234") }()
+		<-entered
+
+		close(release)
+		wg.Wait()
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, http.StatusOK, <-codes)
+		}
+		assert.Equal(t, http.StatusOK, <-done)
+
+		// Completed requests free their slots
+		assert.Equal(t, http.StatusOK, serveFrom(router, "192.0.2.1:1234"))
+	})
+
+	t.Run("allowlisted clients are not limited", func(t *testing.T) {
+		allowlist, err := NewAllowlist("192.0.2.1")
+		require.NoError(t, err)
+		router, entered, release := newSlowRouter(t, 1, allowlist)
+
+		codes := make(chan int, 3)
+		for i := 0; i < 3; i++ {
+			go func() { codes <- serveFrom(router, "192.0.2.1:1234") }()
+		}
+		for i := 0; i < 3; i++ {
+			<-entered
+		}
+		close(release)
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, http.StatusOK, <-codes)
+		}
+	})
+}
+
+func TestConcurrencyLimiter_ReleaseForgetsIdleKeys(t *testing.T) {
+	limiter := NewConcurrencyLimiter(2)
+
+	require.True(t, limiter.Acquire("a"))
+	require.True(t, limiter.Acquire("a"))
+	assert.False(t, limiter.Acquire("a"))
+
+	limiter.Release("a")
+	assert.True(t, limiter.Acquire("a"))
+
+	limiter.Release("a")
+	limiter.Release("a")
+	assert.Empty(t, limiter.inFlight)
+}
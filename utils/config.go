@@ -2,36 +2,196 @@ package utils
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"strconv"
+	"time"
+
+	"inventory-api/models"
 
 	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	Database  DatabaseConfig
-	Server    ServerConfig
-	RateLimit RateLimitConfig
+	Database   DatabaseConfig   `yaml:"database"`
+	Server     ServerConfig     `yaml:"server"`
+	RateLimit  RateLimitConfig  `yaml:"rate_limit"`
+	Auth       AuthConfig       `yaml:"auth"`
+	Redis      RedisConfig      `yaml:"redis"`
+	Pagination PaginationConfig `yaml:"pagination"`
+	Retention  RetentionConfig  `yaml:"retention"`
+	Validation ValidationConfig `yaml:"validation"`
+	Cache      CacheConfig      `yaml:"cache"`
+	Currency   CurrencyConfig   `yaml:"currency"`
+	Export     ExportConfig     `yaml:"export"`
+	Alerts     AlertConfig      `yaml:"alerts"`
+	Search     SearchConfig     `yaml:"search"`
 }
 
+// Database drivers selectable via DB_DRIVER
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
 type DatabaseConfig struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	DBName   string
-	SSLMode  string
+	Driver             string        `yaml:"driver"`
+	Path               string        `yaml:"path"`
+	Host               string        `yaml:"host"`
+	Port               string        `yaml:"port"`
+	User               string        `yaml:"user"`
+	Password           string        `yaml:"password"`
+	DBName             string        `yaml:"name"`
+	SSLMode            string        `yaml:"sslmode"`
+	HealthTimeout      time.Duration `yaml:"health_timeout"`
+	ReplicaDSN         string        `yaml:"replica_dsn"`
+	MaxReplicaLag      time.Duration `yaml:"max_replica_lag"`
+	SlowQueryThreshold time.Duration `yaml:"slow_query_threshold"`
+	// MaxConcurrentQueries caps the statements run at once; 0 disables the cap
+	MaxConcurrentQueries int `yaml:"max_concurrent_queries"`
+	QueryQueueSize       int `yaml:"query_queue_size"`
+	// BreakerThreshold is how many consecutive database failures open the
+	// circuit breaker; 0 disables the breaker
+	BreakerThreshold int           `yaml:"breaker_threshold"`
+	BreakerCooldown  time.Duration `yaml:"breaker_cooldown"`
+	// RetryAttempts is how many times an operation failing with a transient
+	// error is retried; 0 disables retries
+	RetryAttempts int           `yaml:"retry_attempts"`
+	RetryBackoff  time.Duration `yaml:"retry_backoff"`
 }
 
 type ServerConfig struct {
-	Port string
+	Port           string `yaml:"port"`
+	GRPCPort       string `yaml:"grpc_port"`
+	TrustedProxies string `yaml:"trusted_proxies"`
+	// EnableSeedEndpoint mounts POST /inventory/seed; it is off by default in production
+	EnableSeedEndpoint bool `yaml:"enable_seed_endpoint"`
+	// ReadOnly rejects every write with 503 while reads keep working
+	ReadOnly bool `yaml:"read_only"`
+	// DisplayTimezone is the IANA timezone timestamps are rendered in; they
+	// are stored in UTC regardless
+	DisplayTimezone string `yaml:"display_timezone"`
+	// LogSampleRate logs only one in this many successful GET requests;
+	// errors and other methods are always logged
+	LogSampleRate int `yaml:"log_sample_rate"`
+	// MaxQueryLength and MaxQueryParams bound the query string of API
+	// requests in bytes and parameters; 0 disables either check
+	MaxQueryLength int `yaml:"max_query_length"`
+	MaxQueryParams int `yaml:"max_query_params"`
 }
 
+// RateLimitConfig selects the rate limit algorithm. The token bucket
+// refills Requests per second and allows bursts of up to Burst; the fixed
+// window allows Requests per Window and ignores Burst.
 type RateLimitConfig struct {
-	Requests int
-	Burst    int
+	Requests    int           `yaml:"requests"`
+	Burst       int           `yaml:"burst"`
+	KeyStrategy string        `yaml:"key_strategy"`
+	Allowlist   string        `yaml:"allowlist"`
+	Algorithm   string        `yaml:"algorithm"`
+	Window      time.Duration `yaml:"window"`
+	// MaxConcurrent caps the requests each client IP may have in flight at
+	// once; 0 disables the cap
+	MaxConcurrent int `yaml:"max_concurrent"`
+	// APIKeys are the comma-separated X-API-Key values the apikey strategy
+	// limits separately; other values are limited by client IP
+	APIKeys string `yaml:"api_keys"`
+}
+
+type AuthConfig struct {
+	APIKey string `yaml:"api_key"`
+}
+
+type RedisConfig struct {
+	URL string `yaml:"url"`
+}
+
+type PaginationConfig struct {
+	CursorSecret    string `yaml:"cursor_secret"`
+	DefaultPageSize int    `yaml:"default_page_size"`
+}
+
+// ValidationConfig holds item validation limits. MaxStock and MaxPrice are
+// hard limits that reject the write. MaxReasonablePrice is a
+// soft limit that produces warnings rather than errors; zero disables it.
+// MaxFilterComplexity is the budget for list filters, also off at zero;
+// FilterComplexityAction says whether broader filters are rejected or logged.
+type ValidationConfig struct {
+	MaxReasonablePrice     float64 `yaml:"max_reasonable_price"`
+	MaxNameLength          int     `yaml:"max_name_length"`
+	MaxStock               int     `yaml:"max_stock"`
+	MaxPrice               float64 `yaml:"max_price"`
+	MaxFilterComplexity    int     `yaml:"max_filter_complexity"`
+	FilterComplexityAction string  `yaml:"filter_complexity_action"`
+}
+
+// Actions for filters over the complexity budget, selectable via FILTER_COMPLEXITY_ACTION
+const (
+	FilterComplexityReject = "reject"
+	FilterComplexityLog    = "log"
+)
+
+// CacheConfig sizes the item cache and controls warming it on startup.
+// MaxCost is the cache budget in bytes. Disabled, set with
+// CACHE_ENABLED=false, leaves the item cache out so every read hits the
+// database.
+type CacheConfig struct {
+	Disabled    bool  `yaml:"disabled"`
+	Warm        bool  `yaml:"warm"`
+	WarmSize    int   `yaml:"warm_size"`
+	MaxCost     int64 `yaml:"max_cost"`
+	NumCounters int64 `yaml:"num_counters"`
+	// StatsRefreshInterval is how often the stats snapshot served by
+	// /inventory/stats is recomputed; 0 recomputes the stats on every read
+	StatsRefreshInterval time.Duration `yaml:"stats_refresh_interval"`
+}
+
+// CurrencyConfig holds the exchange rates used to convert prices for
+// display. FXRates is a comma-separated list of CODE=RATE pairs quoting
+// each currency against models.DefaultCurrency. DisplayCurrency converts
+// every response that does not ask for a currency; empty leaves prices as
+// they are.
+type CurrencyConfig struct {
+	DisplayCurrency string `yaml:"display_currency"`
+	FXRates         string `yaml:"fx_rates"`
 }
 
+// ExportConfig caps the items a single export may stream. When more items
+// match than MaxRows, LimitAction says whether the export is cut short at
+// MaxRows or rejected. MaxRows of 0 leaves exports uncapped.
+type ExportConfig struct {
+	MaxRows     int    `yaml:"max_rows"`
+	LimitAction string `yaml:"limit_action"`
+}
+
+// Actions for exports over MAX_EXPORT_ROWS, selectable via EXPORT_LIMIT_ACTION
+const (
+	ExportLimitTruncate = "truncate"
+	ExportLimitReject   = "reject"
+)
+
+// AlertConfig raises an alert when the total inventory value, in
+// models.DefaultCurrency, crosses MaxTotalValue; 0 disables the check.
+// Alerts are logged and, when WebhookURL is set, posted to it as JSON.
+type AlertConfig struct {
+	MaxTotalValue float64 `yaml:"max_total_value"`
+	WebhookURL    string  `yaml:"webhook_url"`
+}
+
+// SearchConfig lists the steps item names are normalized with for name
+// searches, as accepted by models.ParseSearchNormalization
+type SearchConfig struct {
+	Normalization string `yaml:"normalization"`
+}
+
+type RetentionConfig struct {
+	SoftDeleteDays int           `yaml:"soft_delete_days"`
+	PurgeInterval  time.Duration `yaml:"purge_interval"`
+}
+
+// Load builds the configuration from defaults, then the YAML file named by
+// CONFIG_FILE if set, then environment variables, each overriding the last
 func Load() (*Config, error) {
 	// Load .env file if it exists
 	if err := godotenv.Load(); err != nil {
@@ -39,25 +199,287 @@ func Load() (*Config, error) {
 		fmt.Println("No .env file found, using default configuration")
 	}
 
-	config := &Config{
+	config := defaultConfig()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := loadConfigFile(path, config); err != nil {
+			return nil, err
+		}
+	}
+
+	applyEnv(config)
+
+	switch config.Database.Driver {
+	case DriverPostgres:
+	case DriverSQLite:
+		if config.Database.ReplicaDSN != "" {
+			return nil, fmt.Errorf("DB_REPLICA_DSN is only supported with the %s driver", DriverPostgres)
+		}
+	default:
+		return nil, fmt.Errorf("DB_DRIVER must be %s or %s, got %q", DriverPostgres, DriverSQLite, config.Database.Driver)
+	}
+	if config.Database.MaxReplicaLag < 0 {
+		return nil, fmt.Errorf("DB_MAX_REPLICA_LAG must not be negative, got %s", config.Database.MaxReplicaLag)
+	}
+
+	switch config.RateLimit.Algorithm {
+	case RateLimitAlgoTokenBucket, RateLimitAlgoFixedWindow:
+	default:
+		return nil, fmt.Errorf("RATE_LIMIT_ALGO must be %s or %s, got %q",
+			RateLimitAlgoTokenBucket, RateLimitAlgoFixedWindow, config.RateLimit.Algorithm)
+	}
+	if _, err := time.LoadLocation(config.Server.DisplayTimezone); err != nil {
+		return nil, fmt.Errorf("DISPLAY_TIMEZONE must be an IANA timezone such as Europe/Berlin, got %q", config.Server.DisplayTimezone)
+	}
+	if config.Server.LogSampleRate < 1 {
+		return nil, fmt.Errorf("LOG_SAMPLE_RATE must be at least 1, got %d", config.Server.LogSampleRate)
+	}
+	if config.Server.MaxQueryLength < 0 {
+		return nil, fmt.Errorf("MAX_QUERY_LENGTH must not be negative, got %d", config.Server.MaxQueryLength)
+	}
+	if config.Server.MaxQueryParams < 0 {
+		return nil, fmt.Errorf("MAX_QUERY_PARAMS must not be negative, got %d", config.Server.MaxQueryParams)
+	}
+
+	if config.RateLimit.Window <= 0 {
+		return nil, fmt.Errorf("RATE_LIMIT_WINDOW must be positive, got %s", config.RateLimit.Window)
+	}
+	if config.RateLimit.MaxConcurrent < 0 {
+		return nil, fmt.Errorf("RATE_LIMIT_MAX_CONCURRENT must not be negative, got %d", config.RateLimit.MaxConcurrent)
+	}
+
+	if size := config.Pagination.DefaultPageSize; size < 1 || size > models.MaxPageLimit {
+		return nil, fmt.Errorf("DEFAULT_PAGE_SIZE must be between 1 and %d, got %d", models.MaxPageLimit, size)
+	}
+
+	if config.Database.MaxConcurrentQueries < 0 {
+		return nil, fmt.Errorf("DB_MAX_CONCURRENT_QUERIES must not be negative, got %d", config.Database.MaxConcurrentQueries)
+	}
+	if config.Database.QueryQueueSize < 0 {
+		return nil, fmt.Errorf("DB_QUERY_QUEUE_SIZE must not be negative, got %d", config.Database.QueryQueueSize)
+	}
+	if config.Database.BreakerThreshold < 0 {
+		return nil, fmt.Errorf("DB_BREAKER_THRESHOLD must not be negative, got %d", config.Database.BreakerThreshold)
+	}
+	if config.Database.BreakerThreshold > 0 && config.Database.BreakerCooldown <= 0 {
+		return nil, fmt.Errorf("DB_BREAKER_COOLDOWN must be positive, got %s", config.Database.BreakerCooldown)
+	}
+	if config.Database.RetryAttempts < 0 {
+		return nil, fmt.Errorf("DB_RETRY_ATTEMPTS must not be negative, got %d", config.Database.RetryAttempts)
+	}
+	if config.Database.RetryBackoff < 0 {
+		return nil, fmt.Errorf("DB_RETRY_BACKOFF must not be negative, got %s", config.Database.RetryBackoff)
+	}
+
+	if config.Cache.MaxCost < 1 {
+		return nil, fmt.Errorf("CACHE_MAX_COST must be at least 1, got %d", config.Cache.MaxCost)
+	}
+	if config.Cache.NumCounters < 1 {
+		return nil, fmt.Errorf("CACHE_NUM_COUNTERS must be at least 1, got %d", config.Cache.NumCounters)
+	}
+	if config.Cache.StatsRefreshInterval < 0 {
+		return nil, fmt.Errorf("STATS_REFRESH_INTERVAL must not be negative, got %s", config.Cache.StatsRefreshInterval)
+	}
+
+	if config.Validation.MaxNameLength < 1 {
+		return nil, fmt.Errorf("MAX_NAME_LENGTH must be at least 1, got %d", config.Validation.MaxNameLength)
+	}
+	if config.Validation.MaxStock < 1 {
+		return nil, fmt.Errorf("MAX_STOCK must be at least 1, got %d", config.Validation.MaxStock)
+	}
+	if config.Validation.MaxPrice <= 0 {
+		return nil, fmt.Errorf("MAX_PRICE must be greater than 0, got %v", config.Validation.MaxPrice)
+	}
+	if config.Validation.MaxFilterComplexity < 0 {
+		return nil, fmt.Errorf("MAX_FILTER_COMPLEXITY must not be negative, got %d", config.Validation.MaxFilterComplexity)
+	}
+	switch config.Validation.FilterComplexityAction {
+	case FilterComplexityReject, FilterComplexityLog:
+	default:
+		return nil, fmt.Errorf("FILTER_COMPLEXITY_ACTION must be %s or %s, got %q",
+			FilterComplexityReject, FilterComplexityLog, config.Validation.FilterComplexityAction)
+	}
+
+	if _, err := ParseFXRates(config.Currency.FXRates); err != nil {
+		return nil, fmt.Errorf("FX_RATES is invalid: %w", err)
+	}
+	if code := config.Currency.DisplayCurrency; code != "" && !models.IsCurrencyCode(code) {
+		return nil, fmt.Errorf("DISPLAY_CURRENCY must be a three letter currency code such as EUR, got %q", code)
+	}
+
+	if config.Export.MaxRows < 0 {
+		return nil, fmt.Errorf("MAX_EXPORT_ROWS must not be negative, got %d", config.Export.MaxRows)
+	}
+	if config.Alerts.MaxTotalValue < 0 {
+		return nil, fmt.Errorf("MAX_TOTAL_VALUE must not be negative, got %g", config.Alerts.MaxTotalValue)
+	}
+	if config.Alerts.WebhookURL != "" {
+		if u, err := url.ParseRequestURI(config.Alerts.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, fmt.Errorf("ALERT_WEBHOOK_URL must be an http or https URL")
+		}
+	}
+	if _, err := models.ParseSearchNormalization(config.Search.Normalization); err != nil {
+		return nil, fmt.Errorf("SEARCH_NORMALIZATION: %w", err)
+	}
+	switch config.Export.LimitAction {
+	case ExportLimitTruncate, ExportLimitReject:
+	default:
+		return nil, fmt.Errorf("EXPORT_LIMIT_ACTION must be %s or %s, got %q",
+			ExportLimitTruncate, ExportLimitReject, config.Export.LimitAction)
+	}
+
+	return config, nil
+}
+
+func defaultConfig() *Config {
+	return &Config{
 		Database: DatabaseConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnv("DB_PORT", "5432"),
-			User:     getEnv("DB_USER", "postgres"),
-			Password: getEnv("DB_PASSWORD", "postgres"),
-			DBName:   getEnv("DB_NAME", "inventory_db"),
-			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+			Driver:             DriverPostgres,
+			Path:               "inventory.db",
+			Host:               "localhost",
+			Port:               "5432",
+			User:               "postgres",
+			Password:           "postgres",
+			DBName:             "inventory_db",
+			SSLMode:            "disable",
+			HealthTimeout:      2 * time.Second,
+			MaxReplicaLag:      30 * time.Second,
+			SlowQueryThreshold: 200 * time.Millisecond,
+			QueryQueueSize:     50,
+			BreakerCooldown:    30 * time.Second,
+			RetryAttempts:      DefaultRetryAttempts,
+			RetryBackoff:       DefaultRetryBackoff,
 		},
 		Server: ServerConfig{
-			Port: getEnv("SERVER_PORT", "8080"),
+			Port:               "8080",
+			GRPCPort:           "9090",
+			EnableSeedEndpoint: os.Getenv("ENV") != "production",
+			DisplayTimezone:    "UTC",
+			LogSampleRate:      1,
+			MaxQueryLength:     4096,
+			MaxQueryParams:     50,
 		},
 		RateLimit: RateLimitConfig{
-			Requests: getEnvAsInt("RATE_LIMIT_REQUESTS", 1),
-			Burst:    getEnvAsInt("RATE_LIMIT_BURST", 5),
+			Requests:    1,
+			Burst:       5,
+			KeyStrategy: RateLimitKeyIP,
+			Algorithm:   RateLimitAlgoTokenBucket,
+			Window:      time.Second,
+		},
+		Pagination: PaginationConfig{
+			DefaultPageSize: DefaultPageSize,
+		},
+		Retention: RetentionConfig{
+			SoftDeleteDays: DefaultSoftDeleteRetentionDays,
+			PurgeInterval:  time.Hour,
+		},
+		Validation: ValidationConfig{
+			MaxNameLength:          models.DefaultMaxNameLength,
+			MaxStock:               models.DefaultMaxStock,
+			MaxPrice:               models.DefaultMaxPrice,
+			FilterComplexityAction: FilterComplexityReject,
+		},
+		Cache: CacheConfig{
+			WarmSize:             100,
+			MaxCost:              DefaultCacheMaxCost,
+			NumCounters:          DefaultCacheNumCounters,
+			StatsRefreshInterval: time.Minute,
+		},
+		Export: ExportConfig{
+			LimitAction: ExportLimitTruncate,
+		},
+		Search: SearchConfig{
+			Normalization: models.DefaultSearchNormalization,
 		},
 	}
+}
 
-	return config, nil
+// loadConfigFile overlays the values present in a YAML file onto config
+func loadConfigFile(path string, config *Config) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	if err := yaml.Unmarshal(content, config); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// applyEnv overrides config with any environment variables that are set
+func applyEnv(config *Config) {
+	config.Database.Driver = getEnv("DB_DRIVER", config.Database.Driver)
+	config.Database.Path = getEnv("DB_PATH", config.Database.Path)
+	config.Database.Host = getEnv("DB_HOST", config.Database.Host)
+	config.Database.Port = getEnv("DB_PORT", config.Database.Port)
+	config.Database.User = getEnv("DB_USER", config.Database.User)
+	config.Database.Password = getEnv("DB_PASSWORD", config.Database.Password)
+	config.Database.DBName = getEnv("DB_NAME", config.Database.DBName)
+	config.Database.SSLMode = getEnv("DB_SSLMODE", config.Database.SSLMode)
+	config.Database.HealthTimeout = getEnvAsDuration("DB_HEALTH_TIMEOUT", config.Database.HealthTimeout)
+	config.Database.ReplicaDSN = getEnv("DB_REPLICA_DSN", config.Database.ReplicaDSN)
+	config.Database.MaxReplicaLag = getEnvAsDuration("DB_MAX_REPLICA_LAG", config.Database.MaxReplicaLag)
+	config.Database.SlowQueryThreshold = getEnvAsDuration("SLOW_QUERY_THRESHOLD", config.Database.SlowQueryThreshold)
+	config.Database.MaxConcurrentQueries = getEnvAsInt("DB_MAX_CONCURRENT_QUERIES", config.Database.MaxConcurrentQueries)
+	config.Database.QueryQueueSize = getEnvAsInt("DB_QUERY_QUEUE_SIZE", config.Database.QueryQueueSize)
+	config.Database.BreakerThreshold = getEnvAsInt("DB_BREAKER_THRESHOLD", config.Database.BreakerThreshold)
+	config.Database.BreakerCooldown = getEnvAsDuration("DB_BREAKER_COOLDOWN", config.Database.BreakerCooldown)
+	config.Database.RetryAttempts = getEnvAsInt("DB_RETRY_ATTEMPTS", config.Database.RetryAttempts)
+	config.Database.RetryBackoff = getEnvAsDuration("DB_RETRY_BACKOFF", config.Database.RetryBackoff)
+
+	config.Server.Port = getEnv("SERVER_PORT", config.Server.Port)
+	config.Server.GRPCPort = getEnv("GRPC_PORT", config.Server.GRPCPort)
+	config.Server.TrustedProxies = getEnv("TRUSTED_PROXIES", config.Server.TrustedProxies)
+	config.Server.EnableSeedEndpoint = getEnvAsBool("ENABLE_SEED_ENDPOINT", config.Server.EnableSeedEndpoint)
+	config.Server.ReadOnly = getEnvAsBool("READ_ONLY", config.Server.ReadOnly)
+	config.Server.DisplayTimezone = getEnv("DISPLAY_TIMEZONE", config.Server.DisplayTimezone)
+	config.Server.LogSampleRate = getEnvAsInt("LOG_SAMPLE_RATE", config.Server.LogSampleRate)
+	config.Server.MaxQueryLength = getEnvAsInt("MAX_QUERY_LENGTH", config.Server.MaxQueryLength)
+	config.Server.MaxQueryParams = getEnvAsInt("MAX_QUERY_PARAMS", config.Server.MaxQueryParams)
+
+	config.RateLimit.Requests = getEnvAsInt("RATE_LIMIT_REQUESTS", config.RateLimit.Requests)
+	config.RateLimit.Burst = getEnvAsInt("RATE_LIMIT_BURST", config.RateLimit.Burst)
+	config.RateLimit.KeyStrategy = getEnv("RATE_LIMIT_KEY", config.RateLimit.KeyStrategy)
+	config.RateLimit.APIKeys = getEnv("RATE_LIMIT_API_KEYS", config.RateLimit.APIKeys)
+	config.RateLimit.Allowlist = getEnv("RATE_LIMIT_ALLOWLIST", config.RateLimit.Allowlist)
+	config.RateLimit.Algorithm = getEnv("RATE_LIMIT_ALGO", config.RateLimit.Algorithm)
+	config.RateLimit.Window = getEnvAsDuration("RATE_LIMIT_WINDOW", config.RateLimit.Window)
+	config.RateLimit.MaxConcurrent = getEnvAsInt("RATE_LIMIT_MAX_CONCURRENT", config.RateLimit.MaxConcurrent)
+
+	config.Auth.APIKey = getEnv("ADMIN_API_KEY", config.Auth.APIKey)
+	config.Redis.URL = getEnv("REDIS_URL", config.Redis.URL)
+	config.Pagination.CursorSecret = getEnv("CURSOR_SECRET", config.Pagination.CursorSecret)
+	config.Pagination.DefaultPageSize = getEnvAsInt("DEFAULT_PAGE_SIZE", config.Pagination.DefaultPageSize)
+
+	config.Retention.SoftDeleteDays = getEnvAsInt("SOFT_DELETE_RETENTION_DAYS", config.Retention.SoftDeleteDays)
+	config.Retention.PurgeInterval = getEnvAsDuration("SOFT_DELETE_PURGE_INTERVAL", config.Retention.PurgeInterval)
+
+	config.Validation.MaxReasonablePrice = getEnvAsFloat("MAX_REASONABLE_PRICE", config.Validation.MaxReasonablePrice)
+	config.Validation.MaxNameLength = getEnvAsInt("MAX_NAME_LENGTH", config.Validation.MaxNameLength)
+	config.Validation.MaxStock = getEnvAsInt("MAX_STOCK", config.Validation.MaxStock)
+	config.Validation.MaxPrice = getEnvAsFloat("MAX_PRICE", config.Validation.MaxPrice)
+	config.Validation.MaxFilterComplexity = getEnvAsInt("MAX_FILTER_COMPLEXITY", config.Validation.MaxFilterComplexity)
+	config.Validation.FilterComplexityAction = getEnv("FILTER_COMPLEXITY_ACTION", config.Validation.FilterComplexityAction)
+
+	config.Cache.Disabled = !getEnvAsBool("CACHE_ENABLED", !config.Cache.Disabled)
+	config.Cache.Warm = getEnvAsBool("CACHE_WARM", config.Cache.Warm)
+	config.Cache.WarmSize = getEnvAsInt("CACHE_WARM_SIZE", config.Cache.WarmSize)
+	config.Cache.MaxCost = getEnvAsInt64("CACHE_MAX_COST", config.Cache.MaxCost)
+	config.Cache.NumCounters = getEnvAsInt64("CACHE_NUM_COUNTERS", config.Cache.NumCounters)
+	config.Cache.StatsRefreshInterval = getEnvAsDuration("STATS_REFRESH_INTERVAL", config.Cache.StatsRefreshInterval)
+
+	config.Currency.DisplayCurrency = getEnv("DISPLAY_CURRENCY", config.Currency.DisplayCurrency)
+	config.Currency.FXRates = getEnv("FX_RATES", config.Currency.FXRates)
+
+	config.Export.MaxRows = getEnvAsInt("MAX_EXPORT_ROWS", config.Export.MaxRows)
+	config.Export.LimitAction = getEnv("EXPORT_LIMIT_ACTION", config.Export.LimitAction)
+
+	config.Alerts.MaxTotalValue = getEnvAsFloat("MAX_TOTAL_VALUE", config.Alerts.MaxTotalValue)
+	config.Alerts.WebhookURL = getEnv("ALERT_WEBHOOK_URL", config.Alerts.WebhookURL)
+
+	config.Search.Normalization = getEnv("SEARCH_NORMALIZATION", config.Search.Normalization)
 }
 
 func getEnv(key, defaultValue string) string {
@@ -76,6 +498,42 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsInt64(key string, defaultValue int64) int64 {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
+
 func (c *Config) GetDSN() string {
 	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		c.Database.Host,
@@ -86,3 +544,27 @@ func (c *Config) GetDSN() string {
 		c.Database.SSLMode,
 	)
 }
+
+// RedactedValue replaces secrets in Redacted configurations
+const RedactedValue = "[REDACTED]"
+
+// Redacted returns a copy of the configuration that is safe to show, with
+// every secret that is set replaced by RedactedValue. Connection strings
+// are redacted whole, since they may embed a password.
+func (c *Config) Redacted() *Config {
+	redacted := *c
+	for _, secret := range []*string{
+		&redacted.Database.Password,
+		&redacted.Database.ReplicaDSN,
+		&redacted.Auth.APIKey,
+		&redacted.RateLimit.APIKeys,
+		&redacted.Redis.URL,
+		&redacted.Pagination.CursorSecret,
+		&redacted.Alerts.WebhookURL,
+	} {
+		if *secret != "" {
+			*secret = RedactedValue
+		}
+	}
+	return &redacted
+}
@@ -0,0 +1,635 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"inventory-api/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_ConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := `
+database:
+  host: file-host
+  port: "6543"
+  health_timeout: 5s
+server:
+  port: "9090"
+rate_limit:
+  requests: 20
+retention:
+  purge_interval: 15m
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	t.Setenv("CONFIG_FILE", path)
+	t.Setenv("DB_HOST", "env-host")
+	t.Setenv("DB_PORT", "")
+	t.Setenv("SERVER_PORT", "")
+	t.Setenv("RATE_LIMIT_REQUESTS", "")
+	t.Setenv("RATE_LIMIT_BURST", "")
+	t.Setenv("DB_HEALTH_TIMEOUT", "")
+	t.Setenv("SOFT_DELETE_PURGE_INTERVAL", "")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+
+	// Environment variables take precedence over the file
+	assert.Equal(t, "env-host", cfg.Database.Host)
+
+	// File values override defaults
+	assert.Equal(t, "6543", cfg.Database.Port)
+	assert.Equal(t, 5*time.Second, cfg.Database.HealthTimeout)
+	assert.Equal(t, "9090", cfg.Server.Port)
+	assert.Equal(t, 20, cfg.RateLimit.Requests)
+	assert.Equal(t, 15*time.Minute, cfg.Retention.PurgeInterval)
+
+	// Values missing from the file keep their defaults
+	assert.Equal(t, 5, cfg.RateLimit.Burst)
+	assert.Equal(t, "inventory_db", cfg.Database.DBName)
+}
+
+func TestLoad_ConfigFileErrors(t *testing.T) {
+	t.Run("missing file", func(t *testing.T) {
+		t.Setenv("CONFIG_FILE", filepath.Join(t.TempDir(), "missing.yaml"))
+
+		_, err := Load()
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid yaml", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("database: [unclosed"), 0o600))
+		t.Setenv("CONFIG_FILE", path)
+
+		_, err := Load()
+		assert.Error(t, err)
+	})
+}
+
+func TestLoad_WithoutConfigFile(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("DB_HOST", "")
+	t.Setenv("SERVER_PORT", "7070")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+
+	assert.Equal(t, "localhost", cfg.Database.Host)
+	assert.Equal(t, "7070", cfg.Server.Port)
+}
+
+func TestLoad_DefaultPageSize(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	t.Run("default", func(t *testing.T) {
+		t.Setenv("DEFAULT_PAGE_SIZE", "")
+
+		cfg, err := Load()
+		require.NoError(t, err)
+		assert.Equal(t, DefaultPageSize, cfg.Pagination.DefaultPageSize)
+	})
+
+	t.Run("from environment", func(t *testing.T) {
+		t.Setenv("DEFAULT_PAGE_SIZE", "25")
+
+		cfg, err := Load()
+		require.NoError(t, err)
+		assert.Equal(t, 25, cfg.Pagination.DefaultPageSize)
+	})
+
+	for _, size := range []string{"0", "101"} {
+		t.Run("out of range "+size, func(t *testing.T) {
+			t.Setenv("DEFAULT_PAGE_SIZE", size)
+
+			_, err := Load()
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoad_DatabaseDriver(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("DB_REPLICA_DSN", "")
+
+	t.Run("sqlite", func(t *testing.T) {
+		t.Setenv("DB_DRIVER", DriverSQLite)
+		t.Setenv("DB_PATH", "/var/lib/inventory/inventory.db")
+
+		cfg, err := Load()
+		require.NoError(t, err)
+		assert.Equal(t, DriverSQLite, cfg.Database.Driver)
+		assert.Equal(t, "/var/lib/inventory/inventory.db", cfg.Database.Path)
+	})
+
+	t.Run("unknown driver", func(t *testing.T) {
+		t.Setenv("DB_DRIVER", "mysql")
+
+		_, err := Load()
+		assert.Error(t, err)
+	})
+
+	t.Run("sqlite with replica", func(t *testing.T) {
+		t.Setenv("DB_DRIVER", DriverSQLite)
+		t.Setenv("DB_REPLICA_DSN", "host=replica")
+
+		_, err := Load()
+		assert.Error(t, err)
+	})
+}
+
+func TestLoad_MaxNameLength(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	t.Run("default", func(t *testing.T) {
+		t.Setenv("MAX_NAME_LENGTH", "")
+
+		cfg, err := Load()
+		require.NoError(t, err)
+		assert.Equal(t, models.DefaultMaxNameLength, cfg.Validation.MaxNameLength)
+	})
+
+	t.Run("from environment", func(t *testing.T) {
+		t.Setenv("MAX_NAME_LENGTH", "500")
+
+		cfg, err := Load()
+		require.NoError(t, err)
+		assert.Equal(t, 500, cfg.Validation.MaxNameLength)
+	})
+
+	t.Run("not positive", func(t *testing.T) {
+		t.Setenv("MAX_NAME_LENGTH", "0")
+
+		_, err := Load()
+		assert.Error(t, err)
+	})
+}
+
+func TestLoad_MaxStockAndPrice(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	t.Run("default", func(t *testing.T) {
+		t.Setenv("MAX_STOCK", "")
+		t.Setenv("MAX_PRICE", "")
+
+		cfg, err := Load()
+		require.NoError(t, err)
+		assert.Equal(t, models.DefaultMaxStock, cfg.Validation.MaxStock)
+		assert.Equal(t, models.DefaultMaxPrice, cfg.Validation.MaxPrice)
+	})
+
+	t.Run("from environment", func(t *testing.T) {
+		t.Setenv("MAX_STOCK", "5000")
+		t.Setenv("MAX_PRICE", "250.50")
+
+		cfg, err := Load()
+		require.NoError(t, err)
+		assert.Equal(t, 5000, cfg.Validation.MaxStock)
+		assert.Equal(t, 250.50, cfg.Validation.MaxPrice)
+	})
+
+	t.Run("stock not positive", func(t *testing.T) {
+		t.Setenv("MAX_STOCK", "0")
+		t.Setenv("MAX_PRICE", "")
+
+		_, err := Load()
+		assert.Error(t, err)
+	})
+
+	t.Run("price not positive", func(t *testing.T) {
+		t.Setenv("MAX_STOCK", "")
+		t.Setenv("MAX_PRICE", "0")
+
+		_, err := Load()
+		assert.Error(t, err)
+	})
+}
+
+func TestLoad_CacheSize(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	t.Run("default", func(t *testing.T) {
+		t.Setenv("CACHE_MAX_COST", "")
+		t.Setenv("CACHE_NUM_COUNTERS", "")
+
+		cfg, err := Load()
+		require.NoError(t, err)
+		assert.Equal(t, DefaultCacheMaxCost, cfg.Cache.MaxCost)
+		assert.Equal(t, DefaultCacheNumCounters, cfg.Cache.NumCounters)
+	})
+
+	t.Run("from environment", func(t *testing.T) {
+		t.Setenv("CACHE_MAX_COST", "67108864")
+		t.Setenv("CACHE_NUM_COUNTERS", "100000")
+
+		cfg, err := Load()
+		require.NoError(t, err)
+		assert.Equal(t, int64(64<<20), cfg.Cache.MaxCost)
+		assert.Equal(t, int64(100000), cfg.Cache.NumCounters)
+	})
+
+	t.Run("not positive", func(t *testing.T) {
+		t.Setenv("CACHE_MAX_COST", "0")
+
+		_, err := Load()
+		assert.Error(t, err)
+	})
+}
+
+func TestLoad_FilterComplexity(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	t.Run("default", func(t *testing.T) {
+		t.Setenv("MAX_FILTER_COMPLEXITY", "")
+		t.Setenv("FILTER_COMPLEXITY_ACTION", "")
+
+		cfg, err := Load()
+		require.NoError(t, err)
+		assert.Equal(t, 0, cfg.Validation.MaxFilterComplexity)
+		assert.Equal(t, FilterComplexityReject, cfg.Validation.FilterComplexityAction)
+	})
+
+	t.Run("from environment", func(t *testing.T) {
+		t.Setenv("MAX_FILTER_COMPLEXITY", "4")
+		t.Setenv("FILTER_COMPLEXITY_ACTION", "log")
+
+		cfg, err := Load()
+		require.NoError(t, err)
+		assert.Equal(t, 4, cfg.Validation.MaxFilterComplexity)
+		assert.Equal(t, FilterComplexityLog, cfg.Validation.FilterComplexityAction)
+	})
+
+	t.Run("negative budget", func(t *testing.T) {
+		t.Setenv("MAX_FILTER_COMPLEXITY", "-1")
+		t.Setenv("FILTER_COMPLEXITY_ACTION", "")
+
+		_, err := Load()
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown action", func(t *testing.T) {
+		t.Setenv("MAX_FILTER_COMPLEXITY", "")
+		t.Setenv("FILTER_COMPLEXITY_ACTION", "warn")
+
+		_, err := Load()
+		assert.Error(t, err)
+	})
+}
+
+func TestLoad_RateLimitAlgorithm(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	t.Run("default", func(t *testing.T) {
+		t.Setenv("RATE_LIMIT_ALGO", "")
+		t.Setenv("RATE_LIMIT_WINDOW", "")
+
+		cfg, err := Load()
+		require.NoError(t, err)
+		assert.Equal(t, RateLimitAlgoTokenBucket, cfg.RateLimit.Algorithm)
+		assert.Equal(t, time.Second, cfg.RateLimit.Window)
+	})
+
+	t.Run("from environment", func(t *testing.T) {
+		t.Setenv("RATE_LIMIT_ALGO", "fixed_window")
+		t.Setenv("RATE_LIMIT_WINDOW", "1m")
+
+		cfg, err := Load()
+		require.NoError(t, err)
+		assert.Equal(t, RateLimitAlgoFixedWindow, cfg.RateLimit.Algorithm)
+		assert.Equal(t, time.Minute, cfg.RateLimit.Window)
+	})
+
+	t.Run("unknown algorithm", func(t *testing.T) {
+		t.Setenv("RATE_LIMIT_ALGO", "leaky_bucket")
+		t.Setenv("RATE_LIMIT_WINDOW", "")
+
+		_, err := Load()
+		assert.Error(t, err)
+	})
+
+	t.Run("window not positive", func(t *testing.T) {
+		t.Setenv("RATE_LIMIT_ALGO", "")
+		t.Setenv("RATE_LIMIT_WINDOW", "0s")
+
+		_, err := Load()
+		assert.Error(t, err)
+	})
+}
+
+func TestLoad_DisplayTimezone(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	t.Run("default", func(t *testing.T) {
+		t.Setenv("DISPLAY_TIMEZONE", "")
+
+		cfg, err := Load()
+		require.NoError(t, err)
+		assert.Equal(t, "UTC", cfg.Server.DisplayTimezone)
+	})
+
+	t.Run("from environment", func(t *testing.T) {
+		t.Setenv("DISPLAY_TIMEZONE", "Europe/Berlin")
+
+		cfg, err := Load()
+		require.NoError(t, err)
+		assert.Equal(t, "Europe/Berlin", cfg.Server.DisplayTimezone)
+	})
+
+	t.Run("unknown timezone", func(t *testing.T) {
+		t.Setenv("DISPLAY_TIMEZONE", "Mars/Olympus_Mons")
+
+		_, err := Load()
+		assert.Error(t, err)
+	})
+}
+
+func TestLoad_CircuitBreaker(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	t.Run("default", func(t *testing.T) {
+		t.Setenv("DB_BREAKER_THRESHOLD", "")
+		t.Setenv("DB_BREAKER_COOLDOWN", "")
+
+		cfg, err := Load()
+		require.NoError(t, err)
+		assert.Zero(t, cfg.Database.BreakerThreshold)
+		assert.Equal(t, 30*time.Second, cfg.Database.BreakerCooldown)
+	})
+
+	t.Run("from environment", func(t *testing.T) {
+		t.Setenv("DB_BREAKER_THRESHOLD", "5")
+		t.Setenv("DB_BREAKER_COOLDOWN", "1m")
+
+		cfg, err := Load()
+		require.NoError(t, err)
+		assert.Equal(t, 5, cfg.Database.BreakerThreshold)
+		assert.Equal(t, time.Minute, cfg.Database.BreakerCooldown)
+	})
+
+	t.Run("negative threshold", func(t *testing.T) {
+		t.Setenv("DB_BREAKER_THRESHOLD", "-1")
+		t.Setenv("DB_BREAKER_COOLDOWN", "")
+
+		_, err := Load()
+		assert.Error(t, err)
+	})
+
+	t.Run("zero cooldown", func(t *testing.T) {
+		t.Setenv("DB_BREAKER_THRESHOLD", "5")
+		t.Setenv("DB_BREAKER_COOLDOWN", "0s")
+
+		_, err := Load()
+		assert.Error(t, err)
+	})
+}
+
+func TestLoad_RetryConfig(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	t.Setenv("DB_RETRY_ATTEMPTS", "")
+	t.Setenv("DB_RETRY_BACKOFF", "")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultRetryAttempts, cfg.Database.RetryAttempts)
+	assert.Equal(t, DefaultRetryBackoff, cfg.Database.RetryBackoff)
+
+	t.Setenv("DB_RETRY_ATTEMPTS", "0")
+	t.Setenv("DB_RETRY_BACKOFF", "200ms")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Zero(t, cfg.Database.RetryAttempts)
+	assert.Equal(t, 200*time.Millisecond, cfg.Database.RetryBackoff)
+
+	t.Setenv("DB_RETRY_ATTEMPTS", "-1")
+	_, err = Load()
+	assert.Error(t, err)
+}
+
+func TestLoad_LogSampleRate(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	t.Setenv("LOG_SAMPLE_RATE", "")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, 1, cfg.Server.LogSampleRate)
+
+	t.Setenv("LOG_SAMPLE_RATE", "100")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, 100, cfg.Server.LogSampleRate)
+
+	t.Setenv("LOG_SAMPLE_RATE", "0")
+	_, err = Load()
+	assert.Error(t, err)
+}
+
+func TestLoad_QuerySize(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	t.Setenv("MAX_QUERY_LENGTH", "")
+	t.Setenv("MAX_QUERY_PARAMS", "")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, 4096, cfg.Server.MaxQueryLength)
+	assert.Equal(t, 50, cfg.Server.MaxQueryParams)
+
+	t.Setenv("MAX_QUERY_LENGTH", "0")
+	t.Setenv("MAX_QUERY_PARAMS", "20")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Zero(t, cfg.Server.MaxQueryLength)
+	assert.Equal(t, 20, cfg.Server.MaxQueryParams)
+
+	t.Setenv("MAX_QUERY_PARAMS", "-1")
+	_, err = Load()
+	assert.Error(t, err)
+}
+
+func TestLoad_Currency(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	t.Setenv("DISPLAY_CURRENCY", "")
+	t.Setenv("FX_RATES", "")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Currency.DisplayCurrency)
+	assert.Empty(t, cfg.Currency.FXRates)
+
+	t.Setenv("DISPLAY_CURRENCY", "EUR")
+	t.Setenv("FX_RATES", "EUR=0.92")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", cfg.Currency.DisplayCurrency)
+	assert.Equal(t, "EUR=0.92", cfg.Currency.FXRates)
+
+	t.Setenv("FX_RATES", "EUR=zero")
+	_, err = Load()
+	assert.Error(t, err)
+
+	t.Setenv("FX_RATES", "")
+	t.Setenv("DISPLAY_CURRENCY", "euro")
+	_, err = Load()
+	assert.Error(t, err)
+}
+
+func TestLoad_CacheEnabled(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	t.Setenv("CACHE_ENABLED", "")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.Cache.Disabled)
+
+	t.Setenv("CACHE_ENABLED", "false")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.Cache.Disabled)
+}
+
+func TestLoad_SearchNormalization(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	t.Setenv("SEARCH_NORMALIZATION", "")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, models.DefaultSearchNormalization, cfg.Search.Normalization)
+
+	t.Setenv("SEARCH_NORMALIZATION", "lowercase,remove_spaces")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, "lowercase,remove_spaces", cfg.Search.Normalization)
+
+	t.Setenv("SEARCH_NORMALIZATION", "lowercase,soundex")
+	_, err = Load()
+	assert.ErrorContains(t, err, "SEARCH_NORMALIZATION")
+}
+
+func TestLoad_ExportLimit(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	t.Run("default", func(t *testing.T) {
+		t.Setenv("MAX_EXPORT_ROWS", "")
+		t.Setenv("EXPORT_LIMIT_ACTION", "")
+
+		cfg, err := Load()
+		require.NoError(t, err)
+		assert.Zero(t, cfg.Export.MaxRows)
+		assert.Equal(t, ExportLimitTruncate, cfg.Export.LimitAction)
+	})
+
+	t.Run("from environment", func(t *testing.T) {
+		t.Setenv("MAX_EXPORT_ROWS", "1000")
+		t.Setenv("EXPORT_LIMIT_ACTION", "reject")
+
+		cfg, err := Load()
+		require.NoError(t, err)
+		assert.Equal(t, 1000, cfg.Export.MaxRows)
+		assert.Equal(t, ExportLimitReject, cfg.Export.LimitAction)
+	})
+
+	t.Run("negative maximum", func(t *testing.T) {
+		t.Setenv("MAX_EXPORT_ROWS", "-1")
+		t.Setenv("EXPORT_LIMIT_ACTION", "")
+
+		_, err := Load()
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown action", func(t *testing.T) {
+		t.Setenv("MAX_EXPORT_ROWS", "")
+		t.Setenv("EXPORT_LIMIT_ACTION", "drop")
+
+		_, err := Load()
+		assert.Error(t, err)
+	})
+}
+
+func TestConfig_Redacted(t *testing.T) {
+	cfg := &Config{}
+	cfg.Database.User = "inventory"
+	cfg.Database.Password = "hunter2"
+	cfg.Database.ReplicaDSN = "host=replica password=hunter2"
+	cfg.Auth.APIKey = "admin-key"
+	cfg.RateLimit.APIKeys = "tenant-a,tenant-b"
+	cfg.Pagination.CursorSecret = "cursor-secret"
+
+	redacted := cfg.Redacted()
+	assert.Equal(t, RedactedValue, redacted.Database.Password)
+	assert.Equal(t, RedactedValue, redacted.Database.ReplicaDSN)
+	assert.Equal(t, RedactedValue, redacted.Auth.APIKey)
+	assert.Equal(t, RedactedValue, redacted.RateLimit.APIKeys)
+	assert.Equal(t, RedactedValue, redacted.Pagination.CursorSecret)
+	assert.Empty(t, redacted.Redis.URL, "unset secrets stay empty")
+	assert.Equal(t, "inventory", redacted.Database.User)
+
+	assert.Equal(t, "hunter2", cfg.Database.Password, "the original is left untouched")
+}
+
+func TestLoad_Alerts(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	t.Setenv("MAX_TOTAL_VALUE", "")
+	t.Setenv("ALERT_WEBHOOK_URL", "")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Zero(t, cfg.Alerts.MaxTotalValue)
+	assert.Empty(t, cfg.Alerts.WebhookURL)
+
+	t.Setenv("MAX_TOTAL_VALUE", "1000000")
+	t.Setenv("ALERT_WEBHOOK_URL", "https://hooks.example.com/inventory")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, 1000000.0, cfg.Alerts.MaxTotalValue)
+	assert.Equal(t, "https://hooks.example.com/inventory", cfg.Alerts.WebhookURL)
+
+	t.Setenv("ALERT_WEBHOOK_URL", "hooks.example.com")
+	_, err = Load()
+	assert.Error(t, err)
+
+	t.Setenv("ALERT_WEBHOOK_URL", "")
+	t.Setenv("MAX_TOTAL_VALUE", "-1")
+	_, err = Load()
+	assert.Error(t, err)
+}
+
+func TestLoad_MaxReplicaLag(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	t.Setenv("DB_MAX_REPLICA_LAG", "")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, cfg.Database.MaxReplicaLag)
+
+	t.Setenv("DB_MAX_REPLICA_LAG", "5s")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, cfg.Database.MaxReplicaLag)
+
+	t.Setenv("DB_MAX_REPLICA_LAG", "-1s")
+	_, err = Load()
+	assert.Error(t, err)
+}
+
+func TestLoad_RateLimitMaxConcurrent(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	t.Setenv("RATE_LIMIT_MAX_CONCURRENT", "")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Zero(t, cfg.RateLimit.MaxConcurrent)
+
+	t.Setenv("RATE_LIMIT_MAX_CONCURRENT", "4")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, 4, cfg.RateLimit.MaxConcurrent)
+
+	t.Setenv("RATE_LIMIT_MAX_CONCURRENT", "-1")
+	_, err = Load()
+	assert.Error(t, err)
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+
+	"inventory-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MergePatchContentType is the media type of an RFC 7386 JSON merge patch
+const MergePatchContentType = "application/merge-patch+json"
+
+// JSONContentTypeMiddleware rejects POST, PUT, PATCH and DELETE requests
+// that carry a body with 415 Unsupported Media Type unless the body is
+// declared as application/json. PATCH also accepts a JSON merge patch.
+// Requests without a body, such as a plain DELETE, pass through.
+func JSONContentTypeMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasBody(c.Request) {
+			c.Next()
+			return
+		}
+
+		allowed := []string{gin.MIMEJSON}
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodDelete:
+		case http.MethodPatch:
+			allowed = append(allowed, MergePatchContentType)
+		default:
+			c.Next()
+			return
+		}
+
+		mediaType, _, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+		if err == nil {
+			for _, t := range allowed {
+				if mediaType == t {
+					c.Next()
+					return
+				}
+			}
+		}
+
+		message := fmt.Sprintf("Content-Type must be %s", allowed[0])
+		if len(allowed) > 1 {
+			message = fmt.Sprintf("Content-Type must be %s or %s", allowed[0], allowed[1])
+		}
+		c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, models.ErrorResponse{
+			Error:   "Unsupported media type",
+			Message: message,
+			Code:    http.StatusUnsupportedMediaType,
+		})
+	}
+}
+
+// hasBody reports whether the request carries a body, including chunked
+// bodies whose length is unknown
+func hasBody(r *http.Request) bool {
+	return r.Body != nil && r.Body != http.NoBody && r.ContentLength != 0
+}
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"inventory-api/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONContentTypeMiddleware(t *testing.T) {
+	router := SetupTestRouter()
+	router.Use(JSONContentTypeMiddleware())
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/items", ok)
+	router.POST("/items", ok)
+	router.PUT("/items/:id", ok)
+	router.PATCH("/items/:id", ok)
+	router.DELETE("/items/:id", ok)
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		body        string
+		contentType string
+		expected    int
+	}{
+		{name: "json", method: http.MethodPost, path: "/items", body: `{}`, contentType: "application/json", expected: http.StatusOK},
+		{name: "json with charset", method: http.MethodPut, path: "/items/1", body: `{}`, contentType: "application/json; charset=utf-8", expected: http.StatusOK},
+		{name: "missing content type", method: http.MethodPost, path: "/items", body: `{}`, expected: http.StatusUnsupportedMediaType},
+		{name: "form content type", method: http.MethodPost, path: "/items", body: `name=x`, contentType: "application/x-www-form-urlencoded", expected: http.StatusUnsupportedMediaType},
+		{name: "text content type", method: http.MethodPut, path: "/items/1", body: `{}`, contentType: "text/plain", expected: http.StatusUnsupportedMediaType},
+		{name: "malformed content type", method: http.MethodPost, path: "/items", body: `{}`, contentType: "application/json;;", expected: http.StatusUnsupportedMediaType},
+		{name: "merge patch", method: http.MethodPatch, path: "/items/1", body: `{}`, contentType: MergePatchContentType, expected: http.StatusOK},
+		{name: "merge patch outside patch", method: http.MethodPost, path: "/items", body: `{}`, contentType: MergePatchContentType, expected: http.StatusUnsupportedMediaType},
+		{name: "delete without body", method: http.MethodDelete, path: "/items/1", expected: http.StatusOK},
+		{name: "post without body", method: http.MethodPost, path: "/items", expected: http.StatusOK},
+		{name: "delete with body", method: http.MethodDelete, path: "/items/1", body: `{}`, contentType: "text/plain", expected: http.StatusUnsupportedMediaType},
+		{name: "get ignores content type", method: http.MethodGet, path: "/items", body: `x`, contentType: "text/plain", expected: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+			if tt.expected == http.StatusUnsupportedMediaType {
+				var response models.ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, "Unsupported media type", response.Error)
+				assert.Equal(t, http.StatusUnsupportedMediaType, response.Code)
+			}
+		})
+	}
+}
@@ -1,22 +1,42 @@
 package utils
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"time"
 
+	"inventory-api/models"
+
 	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
 )
 
 var DB *gorm.DB
 
+// healthTimeout bounds the database ping performed by Health
+var healthTimeout = 2 * time.Second
+
+// breaker is the circuit breaker guarding DB, if one is configured
+var breaker *CircuitBreaker
+
 func Connect(cfg *Config) error {
-	dsn := cfg.GetDSN()
+	dialector := postgres.Open(cfg.GetDSN())
+	if cfg.Database.Driver == DriverSQLite {
+		dialector = sqlite.Open(cfg.Database.Path + "?_foreign_keys=on&_busy_timeout=5000")
+	}
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+	gormLogger := logger.Default.LogMode(logger.Info)
+	if cfg.Database.SlowQueryThreshold > 0 {
+		gormLogger = NewSlowQueryLogger(gormLogger, cfg.Database.SlowQueryThreshold)
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger: gormLogger,
 		NowFunc: func() time.Time {
 			return time.Now().UTC()
 		},
@@ -33,31 +53,129 @@ func Connect(cfg *Config) error {
 	sqlDB.SetMaxIdleConns(10)
 	sqlDB.SetMaxOpenConns(100)
 	sqlDB.SetConnMaxLifetime(time.Hour)
+	if cfg.Database.Driver == DriverSQLite {
+		// SQLite allows a single writer, so share one connection rather
+		// than fail with "database is locked"
+		sqlDB.SetMaxOpenConns(1)
+	}
+
+	if cfg.Database.MaxConcurrentQueries > 0 {
+		if err := db.Use(NewQueryLimiter(cfg.Database.MaxConcurrentQueries, cfg.Database.QueryQueueSize)); err != nil {
+			return fmt.Errorf("failed to configure query limiter: %w", err)
+		}
+	}
+
+	var dbBreaker *CircuitBreaker
+	if cfg.Database.BreakerThreshold > 0 {
+		dbBreaker = NewCircuitBreaker(cfg.Database.BreakerThreshold, cfg.Database.BreakerCooldown)
+		if err := db.Use(dbBreaker); err != nil {
+			return fmt.Errorf("failed to configure circuit breaker: %w", err)
+		}
+	}
+
+	if cfg.Database.ReplicaDSN != "" {
+		if err := UseReplica(db, postgres.Open(cfg.Database.ReplicaDSN)); err != nil {
+			return fmt.Errorf("failed to configure read replica: %w", err)
+		}
+		Info.Printf("Routing read queries to the configured replica")
+	}
 
 	DB = db
+	breaker = dbBreaker
+	if cfg.Database.HealthTimeout > 0 {
+		healthTimeout = cfg.Database.HealthTimeout
+	}
+	maxReplicaLag = cfg.Database.MaxReplicaLag
 	return nil
 }
 
+// UseReplica routes read queries on db to replica, while writes and
+// transactions keep using the primary connection
+func UseReplica(db *gorm.DB, replica gorm.Dialector) error {
+	return db.Use(dbresolver.Register(dbresolver.Config{
+		Replicas: []gorm.Dialector{replica},
+	}).
+		SetMaxIdleConns(10).
+		SetMaxOpenConns(100).
+		SetConnMaxLifetime(time.Hour))
+}
+
+// migrationFiles lists the migrations run by Migrate, in order
+var migrationFiles = []string{
+	"migrations/001_drop_tables.sql",
+	"migrations/002_create_items_table.sql",
+	"migrations/003_add_item_image_url.sql",
+	"migrations/004_add_item_currency.sql",
+	"migrations/005_create_stock_movements_table.sql",
+	"migrations/006_add_item_sku.sql",
+	"migrations/007_add_item_active.sql",
+	"migrations/008_widen_item_name.sql",
+	"migrations/009_add_item_actors.sql",
+	"migrations/010_add_item_reorder_point.sql",
+	"migrations/011_create_item_locks_table.sql",
+	"migrations/012_add_item_dimensions.sql",
+	"migrations/013_add_item_number.sql",
+	"migrations/014_create_categories_table.sql",
+	"migrations/015_create_item_tags_table.sql",
+	"migrations/016_add_item_search_name.sql",
+}
+
+// createSchemaMigrationsTable creates the table recording applied migrations
+const createSchemaMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	name VARCHAR(255) PRIMARY KEY,
+	applied_at TIMESTAMP NOT NULL
+)`
+
 // Migrate runs database migrations (development mode only)
 func Migrate() error {
 	if DB == nil {
 		return fmt.Errorf("database connection not initialized")
 	}
 
-	// Run migration files in order
-	migrationFiles := []string{
-		"migrations/001_drop_tables.sql",
-		"migrations/002_create_items_table.sql",
+	return runMigrations(DB, migrationFiles)
+}
+
+// runMigrations executes, in order, the files not yet recorded in
+// schema_migrations, recording each one in the same transaction as the
+// migration itself. Running it again once every file is applied is a no-op.
+func runMigrations(db *gorm.DB, files []string) error {
+	if err := db.Exec(createSchemaMigrationsTable).Error; err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	applied, err := AppliedMigrations(db)
+	if err != nil {
+		return err
+	}
+	done := make(map[string]bool, len(applied))
+	for _, migration := range applied {
+		done[migration.Name] = true
 	}
 
-	for _, file := range migrationFiles {
+	for _, file := range files {
+		name := filepath.Base(file)
+		if done[name] {
+			continue
+		}
+
+		file = migrationSource(db, file)
 		content, err := os.ReadFile(file)
 		if err != nil {
 			return fmt.Errorf("failed to read migration file %s: %w", file, err)
 		}
 
-		if err := DB.Exec(string(content)).Error; err != nil {
-			return fmt.Errorf("failed to execute migration %s: %w", file, err)
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(string(content)).Error; err != nil {
+				return fmt.Errorf("failed to execute migration %s: %w", file, err)
+			}
+
+			return tx.Create(&models.SchemaMigration{
+				Name:      name,
+				AppliedAt: time.Now().UTC(),
+			}).Error
+		})
+		if err != nil {
+			return err
 		}
 
 		Info.Printf("Successfully executed migration: %s", file)
@@ -66,6 +184,32 @@ func Migrate() error {
 	return nil
 }
 
+// migrationSource returns the variant of file written for the database's
+// dialect, such as migrations/sqlite/002_create_items_table.sql, when one
+// exists. Migrations are recorded under the shared file name either way.
+func migrationSource(db *gorm.DB, file string) string {
+	variant := filepath.Join(filepath.Dir(file), db.Dialector.Name(), filepath.Base(file))
+	if _, err := os.Stat(variant); err == nil {
+		return variant
+	}
+	return file
+}
+
+// AppliedMigrations returns the migrations recorded in db, in the order
+// they were applied. It is empty when Migrate has never run.
+func AppliedMigrations(db *gorm.DB) ([]models.SchemaMigration, error) {
+	migrations := []models.SchemaMigration{}
+	if !db.Migrator().HasTable(&models.SchemaMigration{}) {
+		return migrations, nil
+	}
+
+	if err := db.Order("applied_at ASC, name ASC").Find(&migrations).Error; err != nil {
+		return nil, fmt.Errorf("failed to list applied migrations: %w", err)
+	}
+
+	return migrations, nil
+}
+
 // Close closes the database connection
 func Close() error {
 	if DB == nil {
@@ -80,16 +224,42 @@ func Close() error {
 	return sqlDB.Close()
 }
 
-// Health checks the database connection health
+// Health checks the database connection health, giving up once the
+// configured health timeout is exceeded
 func Health() error {
+	ctx, cancel := context.WithTimeout(context.Background(), healthTimeout)
+	defer cancel()
+
+	return HealthContext(ctx)
+}
+
+// HealthContext checks the database connection health within ctx. The
+// database is unhealthy while its circuit breaker is open.
+func HealthContext(ctx context.Context) error {
 	if DB == nil {
 		return fmt.Errorf("database connection not initialized")
 	}
+	if BreakerState() == BreakerOpen {
+		return ErrCircuitOpen
+	}
 
 	sqlDB, err := DB.DB()
 	if err != nil {
 		return err
 	}
 
-	return sqlDB.Ping()
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return fmt.Errorf("database ping failed: %w", err)
+	}
+
+	return nil
+}
+
+// BreakerState reports the state of the database circuit breaker, or
+// "disabled" when none is configured
+func BreakerState() string {
+	if breaker == nil {
+		return "disabled"
+	}
+	return breaker.State()
 }
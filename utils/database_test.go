@@ -0,0 +1,321 @@
+package utils
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"inventory-api/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+func useTestDB(t *testing.T) *TestDB {
+	testDB := NewTestDB(t)
+	previous := DB
+	DB = testDB.DB
+	t.Cleanup(func() {
+		DB = previous
+		testDB.Close()
+	})
+	return testDB
+}
+
+func TestHealth(t *testing.T) {
+	useTestDB(t)
+
+	assert.NoError(t, Health())
+	assert.NoError(t, HealthContext(context.Background()))
+}
+
+func TestHealthContext_Timeout(t *testing.T) {
+	useTestDB(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := HealthContext(ctx)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestHealth_NotInitialized(t *testing.T) {
+	previous := DB
+	DB = nil
+	defer func() { DB = previous }()
+
+	assert.Error(t, Health())
+}
+
+func TestHealth_CircuitBreaker(t *testing.T) {
+	useTestDB(t)
+	previous := breaker
+	t.Cleanup(func() { breaker = previous })
+
+	breaker = nil
+	assert.Equal(t, "disabled", BreakerState())
+
+	breaker = NewCircuitBreaker(1, time.Minute)
+	assert.Equal(t, BreakerClosed, BreakerState())
+	assert.NoError(t, Health())
+
+	breaker.state = BreakerOpen
+	breaker.openedAt = time.Now()
+	assert.Equal(t, BreakerOpen, BreakerState())
+	assert.ErrorIs(t, Health(), ErrCircuitOpen)
+
+	breaker.openedAt = time.Now().Add(-time.Minute)
+	assert.Equal(t, BreakerHalfOpen, BreakerState())
+	assert.NoError(t, Health())
+}
+
+func TestUseReplica_RoutesReadsToReplica(t *testing.T) {
+	primary := NewTestDB(t)
+	defer primary.Close()
+
+	// In-memory SQLite databases are private to a connection
+	sqlDB, err := primary.DB.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	replicaPath := filepath.Join(t.TempDir(), "replica.db")
+	replica := &TestDB{}
+	replica.DB, err = gorm.Open(sqlite.Open(replicaPath), &gorm.Config{})
+	require.NoError(t, err)
+	defer replica.Close()
+	require.NoError(t, replica.DB.AutoMigrate(&models.Item{}, &models.StockMovement{}))
+
+	primaryItem := primary.CreateTestItem(t, "Primary Item", 10, 10.0)
+	replicaItem := replica.CreateTestItem(t, "Replica Item", 20, 20.0)
+
+	require.NoError(t, UseReplica(primary.DB, sqlite.Open(replicaPath)))
+	service := NewItemServiceWithDB(primary.DB)
+
+	item, err := service.GetItem(replicaItem.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, "Replica Item", item.Name)
+
+	_, err = service.GetItem(primaryItem.ID.String())
+	assert.EqualError(t, err, "item not found")
+
+	result, err := service.GetItems(&models.PaginationRequest{Limit: 10}, &models.FilterRequest{}, &models.SortRequest{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.Total)
+
+	stats, err := service.GetItemStats(nil)
+	require.NoError(t, err)
+	assert.Equal(t, 20*20.0, stats["total_value"])
+
+	created, err := service.CreateItem(&models.CreateItemRequest{Name: "New Item", Stock: 1, Price: 1.0})
+	require.NoError(t, err)
+
+	var written models.Item
+	require.NoError(t, primary.DB.Clauses(dbresolver.Write).First(&written, "id = ?", created.ID).Error)
+	assert.Equal(t, "New Item", written.Name)
+	assert.ErrorIs(t, replica.DB.First(&models.Item{}, "id = ?", created.ID).Error, gorm.ErrRecordNotFound)
+}
+
+// writeMigrations writes each name/statement pair to a migration file,
+// returning the file paths in order
+func writeMigrations(t *testing.T, migrations ...[2]string) []string {
+	dir := t.TempDir()
+	files := make([]string, 0, len(migrations))
+	for _, migration := range migrations {
+		path := filepath.Join(dir, migration[0])
+		require.NoError(t, os.WriteFile(path, []byte(migration[1]), 0o600))
+		files = append(files, path)
+	}
+	return files
+}
+
+func TestRunMigrations_RecordsAppliedFiles(t *testing.T) {
+	testDB := NewTestDB(t)
+	defer testDB.Close()
+
+	files := writeMigrations(t,
+		[2]string{"001_create_widgets.sql", "CREATE TABLE widgets (id INTEGER PRIMARY KEY);"},
+		[2]string{"002_add_widget_color.sql", "ALTER TABLE widgets ADD COLUMN color VARCHAR(20);"},
+	)
+
+	applied, err := AppliedMigrations(testDB.DB)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	before := time.Now().UTC().Add(-time.Second)
+	require.NoError(t, runMigrations(testDB.DB, files))
+
+	applied, err = AppliedMigrations(testDB.DB)
+	require.NoError(t, err)
+	require.Len(t, applied, 2)
+	assert.Equal(t, "001_create_widgets.sql", applied[0].Name)
+	assert.Equal(t, "002_add_widget_color.sql", applied[1].Name)
+	for _, migration := range applied {
+		assert.True(t, migration.AppliedAt.After(before), "applied_at %s", migration.AppliedAt)
+	}
+
+	assert.True(t, testDB.DB.Migrator().HasColumn("widgets", "color"))
+}
+
+func TestRunMigrations_StopsAtFailure(t *testing.T) {
+	testDB := NewTestDB(t)
+	defer testDB.Close()
+
+	files := writeMigrations(t,
+		[2]string{"001_create_widgets.sql", "CREATE TABLE widgets (id INTEGER PRIMARY KEY);"},
+		[2]string{"002_add_widget_color.sql", "ALTER TABLE missing_table ADD COLUMN color VARCHAR(20);"},
+	)
+
+	require.Error(t, runMigrations(testDB.DB, files))
+
+	applied, err := AppliedMigrations(testDB.DB)
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+	assert.Equal(t, "001_create_widgets.sql", applied[0].Name)
+}
+
+func TestRunMigrations_SkipsAppliedFiles(t *testing.T) {
+	testDB := NewTestDB(t)
+	defer testDB.Close()
+
+	// Re-running the CREATE TABLE or the INSERT would fail or add a row
+	files := writeMigrations(t,
+		[2]string{"001_create_widgets.sql", "CREATE TABLE widgets (id INTEGER PRIMARY KEY, name VARCHAR(20));"},
+		[2]string{"002_seed_widgets.sql", "INSERT INTO widgets (name) VALUES ('first');"},
+	)
+
+	require.NoError(t, runMigrations(testDB.DB, files))
+	first, err := AppliedMigrations(testDB.DB)
+	require.NoError(t, err)
+
+	require.NoError(t, runMigrations(testDB.DB, files))
+	second, err := AppliedMigrations(testDB.DB)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	var count int64
+	require.NoError(t, testDB.DB.Table("widgets").Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	// Only files added since the last run are executed
+	files = append(files, writeMigrations(t,
+		[2]string{"003_seed_more_widgets.sql", "INSERT INTO widgets (name) VALUES ('second');"},
+	)...)
+	require.NoError(t, runMigrations(testDB.DB, files))
+
+	require.NoError(t, testDB.DB.Table("widgets").Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+
+	applied, err := AppliedMigrations(testDB.DB)
+	require.NoError(t, err)
+	assert.Len(t, applied, 3)
+}
+
+func TestMigrate_BackfillsSearchName(t *testing.T) {
+	previous := DB
+	t.Cleanup(func() { DB = previous })
+
+	cfg := defaultConfig()
+	cfg.Database.Driver = DriverSQLite
+	cfg.Database.Path = filepath.Join(t.TempDir(), "inventory.db")
+
+	files := make([]string, len(migrationFiles))
+	for i, file := range migrationFiles {
+		files[i] = filepath.Join("..", file)
+	}
+	require.Equal(t, "016_add_item_search_name.sql", filepath.Base(files[len(files)-1]))
+
+	require.NoError(t, Connect(cfg))
+	defer Close()
+
+	// Rows inserted directly, as by a release from before the column existed
+	require.NoError(t, runMigrations(DB, files[:len(files)-1]))
+	require.NoError(t, DB.Exec("INSERT INTO items (id, name, price, item_number) VALUES (?, ?, 1, 1), (?, ?, 1, 2)",
+		uuid.NewString(), "Apple iPhone 15", uuid.NewString(), "I-Phone  Case!").Error)
+
+	require.NoError(t, runMigrations(DB, files))
+	var searchNames []string
+	require.NoError(t, DB.Model(&models.Item{}).Order("item_number").Pluck("search_name", &searchNames).Error)
+	assert.Equal(t, []string{"apple iphone 15", "i-phone  case!"}, searchNames, "the migration lowercases existing names")
+
+	service := NewItemServiceWithDB(DB)
+	defer service.Close()
+
+	updated, err := service.RefreshSearchNames()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), updated, "only names the migration could not fully normalize are updated")
+	require.NoError(t, DB.Model(&models.Item{}).Order("item_number").Pluck("search_name", &searchNames).Error)
+	assert.Equal(t, []string{"apple iphone 15", "iphone case"}, searchNames)
+
+	updated, err = service.RefreshSearchNames()
+	require.NoError(t, err)
+	assert.Zero(t, updated)
+}
+
+func TestConnect_SQLiteFile(t *testing.T) {
+	previous := DB
+	t.Cleanup(func() { DB = previous })
+
+	cfg := defaultConfig()
+	cfg.Database.Driver = DriverSQLite
+	cfg.Database.Path = filepath.Join(t.TempDir(), "inventory.db")
+
+	// Tests run from utils/, one level below the migrations directory
+	files := make([]string, len(migrationFiles))
+	for i, file := range migrationFiles {
+		files[i] = filepath.Join("..", file)
+	}
+
+	require.NoError(t, Connect(cfg))
+	require.NoError(t, runMigrations(DB, files))
+	require.NoError(t, Health())
+
+	service := NewItemServiceWithDB(DB)
+
+	created, err := service.CreateItem(&models.CreateItemRequest{Name: "Gaming Laptop", Stock: 5, Price: 1499.99})
+	require.NoError(t, err)
+
+	item, err := service.GetItem(created.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, "Gaming Laptop", item.Name)
+	assert.Equal(t, models.DefaultCurrency, item.Currency)
+
+	stock := 8
+	updated, err := service.UpdateItem(created.ID.String(), &models.UpdateItemRequest{Stock: &stock})
+	require.NoError(t, err)
+	assert.Equal(t, 8, updated.Stock)
+
+	_, upserted, err := service.UpsertItemBySKU("MOUSE-1", &models.CreateItemRequest{Name: "Mouse", Stock: 3, Price: 25.99})
+	require.NoError(t, err)
+	assert.True(t, upserted)
+
+	// Name filters use LIKE, as SQLite has no ILIKE
+	result, err := service.GetItems(&models.PaginationRequest{}, &models.FilterRequest{Name: "laptop"}, &models.SortRequest{}, nil)
+	require.NoError(t, err)
+	require.Len(t, result.Items, 1)
+	assert.Equal(t, created.ID, result.Items[0].ID)
+
+	movements, err := service.GetItemMovements(created.ID.String(), nil)
+	require.NoError(t, err)
+	assert.Len(t, movements.Items, 2)
+
+	require.NoError(t, service.DeleteItem(created.ID.String()))
+	_, err = service.GetItem(created.ID.String())
+	assert.EqualError(t, err, "item not found")
+
+	// Data persists in the file across connections, and migrating again is a no-op
+	require.NoError(t, Close())
+	require.NoError(t, Connect(cfg))
+	defer Close()
+	require.NoError(t, runMigrations(DB, files))
+
+	var count int64
+	require.NoError(t, DB.Model(&models.Item{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
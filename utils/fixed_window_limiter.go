@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// window counts the requests of one key within the window starting at start
+type window struct {
+	start time.Time
+	count int
+}
+
+// FixedWindowLimiter allows up to limit requests per key in each window.
+// Windows are aligned to multiples of the window length, and the count
+// resets on every boundary. Unlike a token bucket it never lets a client
+// borrow from the next window, so a burst is capped at limit.
+type FixedWindowLimiter struct {
+	mu        sync.Mutex
+	windows   map[string]*window
+	limit     int
+	length    time.Duration
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewFixedWindowLimiter(limit int, length time.Duration) *FixedWindowLimiter {
+	return &FixedWindowLimiter{
+		windows: make(map[string]*window),
+		limit:   limit,
+		length:  length,
+		now:     time.Now,
+	}
+}
+
+func (l *FixedWindowLimiter) Allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	start := l.now().Truncate(l.length)
+
+	// Forget keys that were idle for a whole window, once per window
+	if start.After(l.lastSweep) {
+		for k, w := range l.windows {
+			if w.start.Before(start) {
+				delete(l.windows, k)
+			}
+		}
+		l.lastSweep = start
+	}
+
+	w, exists := l.windows[key]
+	if !exists || !w.start.Equal(start) {
+		w = &window{start: start}
+		l.windows[key] = w
+	}
+	if w.count >= l.limit {
+		return false
+	}
+	w.count++
+	return true
+}
+
+// RedisFixedWindowLimiter is a fixed window limiter whose counters live in
+// Redis, so every replica shares the same budget per key
+type RedisFixedWindowLimiter struct {
+	client  *redis.Client
+	limit   int
+	length  time.Duration
+	timeout time.Duration
+}
+
+func NewRedisFixedWindowLimiter(client *redis.Client, limit int, length time.Duration) *RedisFixedWindowLimiter {
+	return &RedisFixedWindowLimiter{
+		client:  client,
+		limit:   limit,
+		length:  length,
+		timeout: 500 * time.Millisecond,
+	}
+}
+
+// Allow counts the request against the current window of key. Redis
+// failures fail open so that an unavailable Redis does not take the API
+// down with it.
+func (l *RedisFixedWindowLimiter) Allow(key string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), l.timeout)
+	defer cancel()
+
+	start := time.Now().Truncate(l.length)
+	counterKey := "ratelimit:window:" + key + ":" + strconv.FormatInt(start.UnixMilli(), 10)
+
+	var count *redis.IntCmd
+	_, err := l.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		count = pipe.Incr(ctx, counterKey)
+		pipe.PExpire(ctx, counterKey, l.length)
+		return nil
+	})
+	if err != nil {
+		Error.Printf("Redis rate limiter failed, allowing request: %v", err)
+		return true
+	}
+
+	return count.Val() <= int64(l.limit)
+}
@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a settable time source for the fixed window limiter
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newFixedWindowLimiterAt(clock *fakeClock, limit int, length time.Duration) *FixedWindowLimiter {
+	limiter := NewFixedWindowLimiter(limit, length)
+	limiter.now = clock.Now
+	return limiter
+}
+
+func TestFixedWindowLimiter_ResetsOnBoundary(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	limiter := newFixedWindowLimiterAt(clock, 2, time.Minute)
+
+	assert.True(t, limiter.Allow("client-a"))
+	assert.True(t, limiter.Allow("client-a"))
+	assert.False(t, limiter.Allow("client-a"))
+
+	// Other keys have their own count
+	assert.True(t, limiter.Allow("client-b"))
+
+	// Still the same window just before the boundary
+	clock.now = clock.now.Add(59 * time.Second)
+	assert.False(t, limiter.Allow("client-a"))
+
+	clock.now = clock.now.Add(time.Second)
+	assert.True(t, limiter.Allow("client-a"))
+	assert.True(t, limiter.Allow("client-a"))
+	assert.False(t, limiter.Allow("client-a"))
+}
+
+func TestFixedWindowLimiter_ForgetsIdleKeys(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	limiter := newFixedWindowLimiterAt(clock, 1, time.Second)
+
+	limiter.Allow("client-a")
+	limiter.Allow("client-b")
+	require.Len(t, limiter.windows, 2)
+
+	clock.now = clock.now.Add(time.Second)
+	limiter.Allow("client-a")
+	assert.Len(t, limiter.windows, 1)
+}
+
+func TestRateLimitAlgorithms_BurstBehavior(t *testing.T) {
+	// Both allow one request per second on average, but only the token
+	// bucket lets a client spend its burst up front
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	tests := []struct {
+		name    string
+		limiter Limiter
+		allowed int
+	}{
+		{name: "token bucket allows the burst", limiter: NewRateLimiter(1, 5), allowed: 5},
+		{name: "fixed window caps the burst at the limit", limiter: newFixedWindowLimiterAt(clock, 1, time.Second), allowed: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := SetupTestRouter()
+			router.Use(RateLimitMiddleware(tt.limiter, NewKeyFunc(RateLimitKeyIP, ""), nil))
+			router.GET("/ping", func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			allowed := 0
+			for i := 0; i < 10; i++ {
+				if doRateLimitedRequest(router, "192.0.2.1:1234", nil) == http.StatusOK {
+					allowed++
+				}
+			}
+			assert.Equal(t, tt.allowed, allowed)
+		})
+	}
+}
+
+func TestRedisFixedWindowLimiter_Allow(t *testing.T) {
+	server, client := newTestRedisClient(t)
+	replicaA := NewRedisFixedWindowLimiter(client, 2, time.Hour)
+	replicaB := NewRedisFixedWindowLimiter(client, 2, time.Hour)
+
+	assert.True(t, replicaA.Allow("client-a"))
+	assert.True(t, replicaB.Allow("client-a"))
+	assert.False(t, replicaA.Allow("client-a"), "replicas share the window count")
+	assert.True(t, replicaA.Allow("client-b"))
+
+	server.Close()
+	assert.True(t, replicaA.Allow("client-a"), "fails open when Redis is down")
+}
+
+func TestNewLimiter_Algorithm(t *testing.T) {
+	cfg := &Config{RateLimit: RateLimitConfig{Requests: 1, Burst: 5, Algorithm: RateLimitAlgoFixedWindow, Window: time.Second}}
+	limiter, err := NewLimiter(cfg)
+	require.NoError(t, err)
+	assert.IsType(t, &FixedWindowLimiter{}, limiter)
+
+	cfg.RateLimit.Algorithm = RateLimitAlgoTokenBucket
+	limiter, err = NewLimiter(cfg)
+	require.NoError(t, err)
+	assert.IsType(t, &RateLimiter{}, limiter)
+
+	server, _ := newTestRedisClient(t)
+	cfg.Redis.URL = "redis://" + server.Addr()
+	cfg.RateLimit.Algorithm = RateLimitAlgoFixedWindow
+	limiter, err = NewLimiter(cfg)
+	require.NoError(t, err)
+	assert.IsType(t, &RedisFixedWindowLimiter{}, limiter)
+}
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"inventory-api/models"
+)
+
+// ParseFXRates parses a comma-separated list of CODE=RATE pairs, each rate
+// being how many units of the currency one models.DefaultCurrency buys,
+// e.g. "EUR=0.92,GBP=0.79". The default currency always has a rate of 1.
+func ParseFXRates(raw string) (map[string]float64, error) {
+	rates := map[string]float64{models.DefaultCurrency: 1}
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		code, value, ok := strings.Cut(pair, "=")
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if !ok || !models.IsCurrencyCode(code) {
+			return nil, fmt.Errorf("invalid exchange rate %q, expected CODE=RATE such as EUR=0.92", pair)
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("exchange rate for %s must be a positive number, got %q", code, value)
+		}
+		rates[code] = rate
+	}
+	return rates, nil
+}
+
+// DisplayCurrency returns the configured currency prices are converted into
+// when a request does not ask for one; "" leaves prices unconverted
+func (s *ItemService) DisplayCurrency() string {
+	return s.displayCurrency
+}
+
+// ConvertPrice sets item.Converted to the item's price in currency. Without
+// a rate for either currency the item is left as it is, so clients fall back
+// to the original price.
+func (s *ItemService) ConvertPrice(item *models.Item, currency string) {
+	from, ok := s.fxRates[item.Currency]
+	if !ok {
+		return
+	}
+	to, ok := s.fxRates[currency]
+	if !ok {
+		return
+	}
+	item.Converted = models.NewConversion(item.Price, to/from, currency)
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"testing"
+
+	"inventory-api/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFXRates(t *testing.T) {
+	rates, err := ParseFXRates("")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"USD": 1}, rates)
+
+	rates, err = ParseFXRates(" eur=0.92, GBP = 0.79 ,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"USD": 1, "EUR": 0.92, "GBP": 0.79}, rates)
+
+	for _, raw := range []string{"EUR", "EUR=", "EUR=abc", "EUR=0", "EUR=-1", "EURO=0.9", "E1R=0.9"} {
+		_, err := ParseFXRates(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestItemService_ConvertPrice(t *testing.T) {
+	service := NewItemServiceWithConfig(nil, &Config{Currency: CurrencyConfig{FXRates: "EUR=0.5,GBP=0.25"}})
+
+	t.Run("from the base currency", func(t *testing.T) {
+		item := models.Item{Price: 100, Currency: "USD"}
+		service.ConvertPrice(&item, "EUR")
+		assert.Equal(t, &models.Conversion{Amount: 50, Currency: "EUR", Rate: 0.5}, item.Converted)
+	})
+
+	t.Run("between two other currencies", func(t *testing.T) {
+		item := models.Item{Price: 10, Currency: "EUR"}
+		service.ConvertPrice(&item, "GBP")
+		assert.Equal(t, &models.Conversion{Amount: 5, Currency: "GBP", Rate: 0.5}, item.Converted)
+	})
+
+	t.Run("without a rate", func(t *testing.T) {
+		item := models.Item{Price: 10, Currency: "USD"}
+		service.ConvertPrice(&item, "JPY")
+		assert.Nil(t, item.Converted)
+
+		item = models.Item{Price: 10, Currency: "CHF"}
+		service.ConvertPrice(&item, "EUR")
+		assert.Nil(t, item.Converted)
+	})
+}
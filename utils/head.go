@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// headWriter sends the status and headers of a response but drops its body
+type headWriter struct {
+	gin.ResponseWriter
+}
+
+func (w headWriter) Write(data []byte) (int, error) {
+	w.ResponseWriter.WriteHeaderNow()
+	return len(data), nil
+}
+
+func (w headWriter) WriteString(s string) (int, error) {
+	w.ResponseWriter.WriteHeaderNow()
+	return len(s), nil
+}
+
+// HeadMiddleware lets a GET handler answer HEAD requests. The handler runs
+// as it would for GET, so the status and headers are the same, but the body
+// is discarded. Gin does not route HEAD to GET handlers on its own.
+func HeadMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer = headWriter{c.Writer}
+		c.Next()
+	}
+}
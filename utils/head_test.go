@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeadMiddleware(t *testing.T) {
+	router := SetupTestRouter()
+	handler := func(c *gin.Context) {
+		c.Header("X-Total-Count", "3")
+		c.JSON(http.StatusOK, gin.H{"items": []string{"a", "b", "c"}})
+	}
+	router.GET("/items", handler)
+	router.HEAD("/items", HeadMiddleware(), handler)
+	router.HEAD("/missing", HeadMiddleware(), func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+	})
+
+	get := httptest.NewRecorder()
+	router.ServeHTTP(get, httptest.NewRequest(http.MethodGet, "/items", nil))
+	head := httptest.NewRecorder()
+	router.ServeHTTP(head, httptest.NewRequest(http.MethodHead, "/items", nil))
+
+	assert.Equal(t, http.StatusOK, head.Code)
+	assert.Equal(t, "3", head.Header().Get("X-Total-Count"))
+	assert.Equal(t, get.Header().Get("Content-Type"), head.Header().Get("Content-Type"))
+	assert.NotEmpty(t, get.Body.String())
+	assert.Empty(t, head.Body.String())
+
+	missing := httptest.NewRecorder()
+	router.ServeHTTP(missing, httptest.NewRequest(http.MethodHead, "/missing", nil))
+	assert.Equal(t, http.StatusNotFound, missing.Code)
+	assert.Empty(t, missing.Body.String())
+}
@@ -1,71 +1,326 @@
 package utils
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"inventory-api/models"
 
 	"github.com/dgraph-io/ristretto/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ItemService struct {
-	db    *gorm.DB
-	cache *ristretto.Cache[string, *models.Item]
+	db                  *gorm.DB
+	cache               *ristretto.Cache[string, *models.Item]
+	cursorSecret        []byte
+	softDeleteRetention time.Duration
+	maxReasonablePrice  float64
+	defaultPageSize     int
+	maxNameLength       int
+	maxStock            int
+	maxPrice            float64
+	maxFilterComplexity int
+	logBroadFilters     bool
+	retryAttempts       int
+	retryBackoff        time.Duration
+	// fxRates quote each currency against models.DefaultCurrency
+	fxRates         map[string]float64
+	displayCurrency string
+	// maxExportRows caps exports; 0 leaves them uncapped
+	maxExportRows      int
+	rejectLargeExports bool
+	// maxTotalValue alerts, through the log and alertWebhookURL, when the
+	// total inventory value crosses it; 0 disables the check
+	maxTotalValue   float64
+	alertWebhookURL string
+	alertMu         sync.Mutex
+	valueExceeded   bool
+	// statsRefreshInterval is how often the stats snapshot is recomputed;
+	// while it is 0, every read recomputes the stats
+	statsRefreshInterval time.Duration
+	statsMu              sync.RWMutex
+	stats                *statsSnapshot
 }
 
+// CursorData marks the last item of a page by its value in the sorted
+// column and its ID, which breaks ties between equal values. The sort is
+// recorded so a cursor cannot be reused with a different one.
 type CursorData struct {
 	ID        string `json:"id"`
-	CreatedAt string `json:"created_at"`
+	SortBy    string `json:"sort_by"`
+	SortOrder string `json:"sort_order"`
+	Value     string `json:"value"`
 }
 
+// sortKey writes the value of a sortable column into a cursor and parses it back
+type sortKey struct {
+	format func(item *models.Item) string
+	parse  func(value string) (interface{}, error)
+}
+
+// sortKeys writes and parses the cursor value of every field in
+// models.SortColumns, keyed by field name
+var sortKeys = map[string]sortKey{
+	"name": {
+		format: func(item *models.Item) string { return item.Name },
+		parse:  func(value string) (interface{}, error) { return value, nil },
+	},
+	"stock": {
+		format: func(item *models.Item) string { return strconv.Itoa(item.Stock) },
+		parse:  func(value string) (interface{}, error) { return strconv.Atoi(value) },
+	},
+	"price": {
+		format: func(item *models.Item) string { return strconv.FormatFloat(item.Price, 'g', -1, 64) },
+		parse:  func(value string) (interface{}, error) { return strconv.ParseFloat(value, 64) },
+	},
+	"created_at": {
+		format: func(item *models.Item) string { return item.CreatedAt.Format(time.RFC3339Nano) },
+		parse:  func(value string) (interface{}, error) { return time.Parse(time.RFC3339Nano, value) },
+	},
+}
+
+// movementCursor marks the last stock movement of a page; movements are
+// listed in ID order
+type movementCursor struct {
+	ID uint `json:"id"`
+}
+
+// DefaultPageSize is the number of items listed when no limit is requested
+const DefaultPageSize = 10
+
+// Item cache sizing used unless CACHE_MAX_COST and CACHE_NUM_COUNTERS say otherwise
+const (
+	DefaultCacheMaxCost     int64 = 1 << 30
+	DefaultCacheNumCounters int64 = 1e7
+)
+
+// DefaultLowStockThreshold is the stock level below which an item without its
+// own reorder point counts as low on stock
+const DefaultLowStockThreshold = 10
+
+// DefaultSoftDeleteRetentionDays is how long soft-deleted items are kept before being purged
+const DefaultSoftDeleteRetentionDays = 30
+
+// ErrInvalidCursor is returned when a pagination cursor is malformed or tampered with
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ErrItemLocked is returned when another owner holds an unexpired lock on the item
+var ErrItemLocked = errors.New("item is locked by another owner")
+
+// LockOwnerHeader carries the owner token of the lock a client holds when it updates an item
+const LockOwnerHeader = "X-Lock-Owner"
+
+// ErrNameTooLong is returned when an item name exceeds the configured maximum length
+var ErrNameTooLong = errors.New("name too long")
+
+// ErrPreconditionFailed is returned when a conditional write finds the item changed since the caller last saw it
+var ErrPreconditionFailed = errors.New("item has been modified since the given time")
+
+// ErrInvalidSort is returned when items are sorted by a field not listed in models.SortColumns
+var ErrInvalidSort = errors.New("invalid sort")
+
+// ErrExportTooLarge is returned when more items match an export than
+// MAX_EXPORT_ROWS allows and EXPORT_LIMIT_ACTION is reject
+var ErrExportTooLarge = errors.New("export too large")
+
+// ErrTooManyToGroup is returned when more than models.MaxGroupedItems items would be grouped
+var ErrTooManyToGroup = errors.New("too many items to group")
+
+// ErrInvalidGroupField is returned when items are grouped by a field not listed in models.GroupFields
+var ErrInvalidGroupField = errors.New("invalid group field")
+
+// ErrValueTooLarge is returned when an item's stock or price exceeds the configured maximum
+var ErrValueTooLarge = errors.New("value too large")
+
+// ErrCategoryNotFound is returned when an item or category refers to a category that does not exist
+var ErrCategoryNotFound = errors.New("category not found")
+
+// ErrCategoryTooDeep is returned when a new category would nest deeper than models.MaxCategoryDepth
+var ErrCategoryTooDeep = errors.New("category nested too deeply")
+
+// ErrFilterTooBroad is returned when list filters exceed the configured complexity budget
+var ErrFilterTooBroad = errors.New("filters are too broad")
+
+// ErrFilterRequired is returned when a bulk change is given an empty filter without confirming it applies to every item
+var ErrFilterRequired = errors.New("filter is empty")
+
 func NewItemService() *ItemService {
+	return NewItemServiceWithDB(DB)
+}
+
+func NewItemServiceWithDB(db *gorm.DB) *ItemService {
+	return NewItemServiceWithConfig(db, nil)
+}
+
+// NewItemServiceWithConfig creates an item service tuned by cfg.
+// A nil cfg uses the default settings.
+func NewItemServiceWithConfig(db *gorm.DB, cfg *Config) *ItemService {
+	maxCost, numCounters := DefaultCacheMaxCost, DefaultCacheNumCounters
+	service := &ItemService{
+		db:                  db,
+		softDeleteRetention: DefaultSoftDeleteRetentionDays * 24 * time.Hour,
+		defaultPageSize:     DefaultPageSize,
+		maxNameLength:       models.DefaultMaxNameLength,
+		maxStock:            models.DefaultMaxStock,
+		maxPrice:            models.DefaultMaxPrice,
+		retryAttempts:       DefaultRetryAttempts,
+		retryBackoff:        DefaultRetryBackoff,
+		fxRates:             map[string]float64{models.DefaultCurrency: 1},
+	}
+	if cfg != nil {
+		if cfg.Pagination.CursorSecret != "" {
+			service.cursorSecret = []byte(cfg.Pagination.CursorSecret)
+		}
+		service.softDeleteRetention = time.Duration(cfg.Retention.SoftDeleteDays) * 24 * time.Hour
+		service.maxReasonablePrice = cfg.Validation.MaxReasonablePrice
+		if cfg.Pagination.DefaultPageSize > 0 {
+			service.defaultPageSize = cfg.Pagination.DefaultPageSize
+		}
+		if cfg.Validation.MaxNameLength > 0 {
+			service.maxNameLength = cfg.Validation.MaxNameLength
+		}
+		if cfg.Validation.MaxStock > 0 {
+			service.maxStock = cfg.Validation.MaxStock
+		}
+		if cfg.Validation.MaxPrice > 0 {
+			service.maxPrice = cfg.Validation.MaxPrice
+		}
+		service.maxFilterComplexity = cfg.Validation.MaxFilterComplexity
+		service.logBroadFilters = cfg.Validation.FilterComplexityAction == FilterComplexityLog
+		service.statsRefreshInterval = cfg.Cache.StatsRefreshInterval
+		service.retryAttempts = cfg.Database.RetryAttempts
+		service.retryBackoff = cfg.Database.RetryBackoff
+		if rates, err := ParseFXRates(cfg.Currency.FXRates); err != nil {
+			Error.Printf("Invalid exchange rates, converting no prices: %v", err)
+		} else {
+			service.fxRates = rates
+		}
+		service.displayCurrency = cfg.Currency.DisplayCurrency
+		service.maxExportRows = cfg.Export.MaxRows
+		service.rejectLargeExports = cfg.Export.LimitAction == ExportLimitReject
+		service.maxTotalValue = cfg.Alerts.MaxTotalValue
+		service.alertWebhookURL = cfg.Alerts.WebhookURL
+		if cfg.Cache.MaxCost > 0 {
+			maxCost = cfg.Cache.MaxCost
+		}
+		if cfg.Cache.NumCounters > 0 {
+			numCounters = cfg.Cache.NumCounters
+		}
+		// Every cache access is guarded, so without one reads go to the database
+		if cfg.Cache.Disabled {
+			Info.Printf("Item cache disabled, every read hits the database")
+			return service
+		}
+	}
+
 	cache, err := ristretto.NewCache(&ristretto.Config[string, *models.Item]{
-		NumCounters: 1e7,
-		MaxCost:     1 << 30,
+		NumCounters: numCounters,
+		MaxCost:     maxCost,
 		BufferItems: 64,
 	})
 	if err != nil {
 		Error.Printf("Failed to create cache: %v", err)
-		return &ItemService{db: DB}
+		return service
 	}
 
-	return &ItemService{
-		db:    DB,
-		cache: cache,
-	}
+	service.cache = cache
+	return service
 }
 
-func NewItemServiceWithDB(db *gorm.DB) *ItemService {
-	cache, err := ristretto.NewCache(&ristretto.Config[string, *models.Item]{
-		NumCounters: 1e7,
-		MaxCost:     1 << 30,
-		BufferItems: 64,
-	})
-	if err != nil {
-		Error.Printf("Failed to create cache: %v", err)
-		return &ItemService{db: db}
+// DB returns the database connection the service queries
+func (s *ItemService) DB() *gorm.DB {
+	return s.db
+}
+
+// WithRequestTx returns a service running its queries in the transaction
+// TransactionMiddleware opened for c, or s itself outside one. A failed
+// statement aborts the whole transaction, so the returned service does not
+// retry. It bypasses the item cache so uncommitted items are never cached,
+// and clears the cache once the transaction commits.
+func (s *ItemService) WithRequestTx(c *gin.Context) *ItemService {
+	tx, ok := RequestTx(c)
+	if !ok {
+		return s
 	}
+	AfterCommit(c, s.invalidateCache)
 
+	// Stats are computed on every read and value alerts are left to s,
+	// which keeps the snapshot and the alert state
 	return &ItemService{
-		db:    db,
-		cache: cache,
+		db:                  tx,
+		cursorSecret:        s.cursorSecret,
+		softDeleteRetention: s.softDeleteRetention,
+		maxReasonablePrice:  s.maxReasonablePrice,
+		defaultPageSize:     s.defaultPageSize,
+		maxNameLength:       s.maxNameLength,
+		maxStock:            s.maxStock,
+		maxPrice:            s.maxPrice,
+		maxFilterComplexity: s.maxFilterComplexity,
+		logBroadFilters:     s.logBroadFilters,
+		fxRates:             s.fxRates,
+		displayCurrency:     s.displayCurrency,
+		maxExportRows:       s.maxExportRows,
+		rejectLargeExports:  s.rejectLargeExports,
 	}
 }
 
 func (s *ItemService) CreateItem(req *models.CreateItemRequest) (*models.Item, error) {
+	if err := s.ValidateName(req.Name); err != nil {
+		return nil, err
+	}
+	if err := s.validateAmounts(&req.Stock, &req.Price); err != nil {
+		return nil, err
+	}
+
 	item := &models.Item{
-		Name:  req.Name,
-		Stock: req.Stock,
-		Price: req.Price,
+		Name:         req.Name,
+		Stock:        req.Stock,
+		Price:        req.Price,
+		Currency:     req.Currency,
+		ImageURL:     req.ImageURL,
+		ReorderPoint: req.ReorderPoint,
+		AutoReorder:  req.AutoReorder,
+		WeightGrams:  req.WeightGrams,
+		LengthMM:     req.LengthMM,
+		WidthMM:      req.WidthMM,
+		HeightMM:     req.HeightMM,
+		CreatedBy:    req.Actor,
+		UpdatedBy:    req.Actor,
 	}
 
-	if err := s.db.Create(item).Error; err != nil {
-		return nil, fmt.Errorf("failed to create item: %w", err)
+	err := s.withRetry(false, func() error {
+		// A retried attempt draws a fresh item number
+		item.ItemNumber = 0
+		return s.db.Transaction(func(tx *gorm.DB) error {
+			categoryID, err := findCategoryID(tx, req.CategoryID)
+			if err != nil {
+				return err
+			}
+			item.CategoryID = categoryID
+
+			if err := tx.Create(item).Error; err != nil {
+				return fmt.Errorf("failed to create item: %w", err)
+			}
+
+			return recordMovement(tx, item, item.Stock, req.Reason, models.MovementReasonInitial)
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	s.invalidateCache()
@@ -73,13 +328,184 @@ func (s *ItemService) CreateItem(req *models.CreateItemRequest) (*models.Item, e
 	return item, nil
 }
 
+// UpsertItemBySKU creates the item with the given SKU, or replaces the
+// fields of the existing one, in a single ON CONFLICT statement. A
+// soft-deleted item with the SKU is restored. It reports whether the item
+// was created.
+func (s *ItemService) UpsertItemBySKU(sku string, req *models.CreateItemRequest) (*models.Item, bool, error) {
+	if err := s.ValidateName(req.Name); err != nil {
+		return nil, false, err
+	}
+	if err := s.validateAmounts(&req.Stock, &req.Price); err != nil {
+		return nil, false, err
+	}
+
+	newID := uuid.New()
+	item := &models.Item{
+		ID:           newID,
+		Name:         req.Name,
+		Stock:        req.Stock,
+		Price:        req.Price,
+		Currency:     req.Currency,
+		ImageURL:     req.ImageURL,
+		SKU:          &sku,
+		ReorderPoint: req.ReorderPoint,
+		AutoReorder:  req.AutoReorder,
+		WeightGrams:  req.WeightGrams,
+		LengthMM:     req.LengthMM,
+		WidthMM:      req.WidthMM,
+		HeightMM:     req.HeightMM,
+		CreatedBy:    req.Actor,
+		UpdatedBy:    req.Actor,
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		categoryID, err := findCategoryID(tx, req.CategoryID)
+		if err != nil {
+			return err
+		}
+		item.CategoryID = categoryID
+
+		previous := models.Item{}
+		if err := tx.Unscoped().Select("stock").Where("sku = ?", sku).Take(&previous).Error; err != nil && err != gorm.ErrRecordNotFound {
+			return fmt.Errorf("failed to get item: %w", err)
+		}
+
+		err = tx.Clauses(
+			clause.OnConflict{
+				Columns:   []clause.Column{{Name: "sku"}},
+				DoUpdates: clause.AssignmentColumns([]string{"name", "search_name", "stock", "price", "currency", "image_url", "reorder_point", "auto_reorder", "weight_grams", "length_mm", "width_mm", "height_mm", "category_id", "updated_by", "updated_at", "deleted_at"}),
+			},
+			clause.Returning{},
+		).Create(item).Error
+		if err != nil {
+			return fmt.Errorf("failed to upsert item: %w", err)
+		}
+
+		// On conflict the existing row is returned, replacing the new ID
+		if item.ID == newID {
+			return recordMovement(tx, item, item.Stock, req.Reason, models.MovementReasonInitial)
+		}
+		if delta := item.Stock - previous.Stock; delta != 0 {
+			return recordMovement(tx, item, delta, req.Reason, models.MovementReasonAdjustment)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	s.invalidateCache()
+
+	return item, item.ID == newID, nil
+}
+
+// ValidateName checks name against the configured maximum length, counted
+// in characters rather than bytes
+func (s *ItemService) ValidateName(name string) error {
+	if utf8.RuneCountInString(name) > s.maxNameLength {
+		return fmt.Errorf("%w: must be at most %d characters", ErrNameTooLong, s.maxNameLength)
+	}
+	return nil
+}
+
+// ValidateStock checks stock against the configured maximum
+func (s *ItemService) ValidateStock(stock int) error {
+	if stock > s.maxStock {
+		return fmt.Errorf("%w: stock must be at most %d", ErrValueTooLarge, s.maxStock)
+	}
+	return nil
+}
+
+// ValidatePrice checks price against the configured maximum
+func (s *ItemService) ValidatePrice(price float64) error {
+	if price > s.maxPrice {
+		return fmt.Errorf("%w: price must be at most %.2f", ErrValueTooLarge, s.maxPrice)
+	}
+	return nil
+}
+
+// ItemSchema returns the validation rules of the item create and update
+// requests: those of their binding tags, with the name length, stock and
+// price capped at the configured maximums
+func (s *ItemService) ItemSchema() *models.ItemSchemaResponse {
+	schema := &models.ItemSchemaResponse{
+		Create: models.RequestRules(&models.CreateItemRequest{}),
+		Update: models.RequestRules(&models.UpdateItemRequest{}),
+	}
+
+	maxStock := float64(s.maxStock)
+	for _, rules := range []map[string]models.FieldRule{schema.Create, schema.Update} {
+		name := rules["name"]
+		name.MaxLength = &s.maxNameLength
+		rules["name"] = name
+
+		stock := rules["stock"]
+		stock.Max = &maxStock
+		rules["stock"] = stock
+
+		price := rules["price"]
+		price.Max = &s.maxPrice
+		rules["price"] = price
+	}
+
+	return schema
+}
+
+// validateAmounts checks whichever of stock and price are given against
+// their configured maximums
+func (s *ItemService) validateAmounts(stock *int, price *float64) error {
+	if stock != nil {
+		if err := s.ValidateStock(*stock); err != nil {
+			return err
+		}
+	}
+	if price != nil {
+		return s.ValidatePrice(*price)
+	}
+	return nil
+}
+
+// CheckFilters rejects filters whose complexity exceeds the configured
+// budget, naming what makes them expensive. When broad filters are only
+// logged it always returns nil.
+func (s *ItemService) CheckFilters(filters *models.FilterRequest) error {
+	if s.maxFilterComplexity <= 0 {
+		return nil
+	}
+	complexity, reasons := filters.Complexity()
+	if complexity <= s.maxFilterComplexity {
+		return nil
+	}
+
+	err := fmt.Errorf("%w: complexity %d exceeds the budget of %d because %s; search for a more specific name or bound the price with both min_price and max_price or price_eq",
+		ErrFilterTooBroad, complexity, s.maxFilterComplexity, strings.Join(reasons, ", "))
+	if s.logBroadFilters {
+		Info.Printf("Running broad query: %v", err)
+		return nil
+	}
+	return err
+}
+
+// PriceWarnings returns soft validation warnings for a price. They flag
+// likely typos without rejecting the write.
+func (s *ItemService) PriceWarnings(price float64) []string {
+	if s.maxReasonablePrice > 0 && price > s.maxReasonablePrice {
+		return []string{fmt.Sprintf("price %.2f exceeds the reasonable maximum of %.2f", price, s.maxReasonablePrice)}
+	}
+	return nil
+}
+
 func (s *ItemService) GetItem(id string) (*models.Item, error) {
 	if item := s.getFromCache(id); item != nil {
 		return item, nil
 	}
 
 	item := &models.Item{}
-	if err := s.db.Where("id = ?", id).First(item).Error; err != nil {
+	err := s.withRetry(true, func() error {
+		return s.db.Where("id = ?", id).First(item).Error
+	})
+	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("item not found")
 		}
@@ -91,27 +517,103 @@ func (s *ItemService) GetItem(id string) (*models.Item, error) {
 	return item, nil
 }
 
-func (s *ItemService) UpdateItem(id string, req *models.UpdateItemRequest) (*models.Item, error) {
+// GetItemWithDeleted returns the item with id even when it has been soft
+// deleted, in which case its DeletedAt is set. Deleted items are never
+// cached, so it always reads the database.
+func (s *ItemService) GetItemWithDeleted(id string) (*models.Item, error) {
 	item := &models.Item{}
-	if err := s.db.Where("id = ?", id).First(item).Error; err != nil {
+	err := s.withRetry(true, func() error {
+		return s.db.Unscoped().Where("id = ?", id).First(item).Error
+	})
+	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("item not found")
 		}
 		return nil, fmt.Errorf("failed to get item: %w", err)
 	}
 
-	if req.Name != nil {
-		item.Name = *req.Name
+	return item, nil
+}
+
+// GetItemStock returns the stock of an item, served from the item cache
+// when possible
+func (s *ItemService) GetItemStock(id string) (*models.StockResponse, error) {
+	item, err := s.GetItem(id)
+	if err != nil {
+		return nil, err
 	}
-	if req.Stock != nil {
-		item.Stock = *req.Stock
+
+	return &models.StockResponse{
+		ID:        item.ID,
+		Stock:     item.Stock,
+		Available: item.Stock,
+	}, nil
+}
+
+// GetItemByNumber returns the item with the given sequential item number
+func (s *ItemService) GetItemByNumber(number int64) (*models.Item, error) {
+	item := &models.Item{}
+	if err := s.db.Where("item_number = ?", number).First(item).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("item not found")
+		}
+		return nil, fmt.Errorf("failed to get item: %w", err)
 	}
-	if req.Price != nil {
-		item.Price = *req.Price
+
+	return item, nil
+}
+
+func (s *ItemService) UpdateItem(id string, req *models.UpdateItemRequest) (*models.Item, error) {
+	if req.Name != nil {
+		if err := s.ValidateName(*req.Name); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.validateAmounts(req.Stock, req.Price); err != nil {
+		return nil, err
 	}
 
-	if err := s.db.Save(item).Error; err != nil {
-		return nil, fmt.Errorf("failed to update item: %w", err)
+	item := &models.Item{}
+	err := s.withRetry(false, func() error {
+		// Each attempt starts again from the stored item
+		*item = models.Item{}
+		return s.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Where("id = ?", id).First(item).Error; err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return fmt.Errorf("item not found")
+				}
+				return fmt.Errorf("failed to get item: %w", err)
+			}
+
+			if modifiedSince(item, req.UnmodifiedSince) {
+				return ErrPreconditionFailed
+			}
+			if err := checkLock(tx, item.ID, req.LockOwner); err != nil {
+				return err
+			}
+			if req.CategoryID != nil {
+				if _, err := findCategoryID(tx, *req.CategoryID); err != nil {
+					return err
+				}
+			}
+
+			previousStock := item.Stock
+			applyUpdate(item, req)
+			item.UpdatedBy = actorOrSystem(req.Actor)
+
+			if err := tx.Save(item).Error; err != nil {
+				return fmt.Errorf("failed to update item: %w", err)
+			}
+
+			if delta := item.Stock - previousStock; delta != 0 {
+				return recordMovement(tx, item, delta, req.Reason, models.MovementReasonAdjustment)
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	s.invalidateCache()
@@ -119,105 +621,1198 @@ func (s *ItemService) UpdateItem(id string, req *models.UpdateItemRequest) (*mod
 	return item, nil
 }
 
-func (s *ItemService) DeleteItem(id string) error {
-	result := s.db.Where("id = ?", id).Delete(&models.Item{})
-	if result.Error != nil {
-		return fmt.Errorf("failed to delete item: %w", result.Error)
+// CloneItem copies an existing item into a new one with a fresh ID,
+// applying any overrides given in req
+func (s *ItemService) CloneItem(id string, req *models.UpdateItemRequest) (*models.Item, error) {
+	if req.Name != nil {
+		if err := s.ValidateName(*req.Name); err != nil {
+			return nil, err
+		}
 	}
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("item not found")
+	if err := s.validateAmounts(req.Stock, req.Price); err != nil {
+		return nil, err
+	}
+
+	source := &models.Item{}
+	if err := s.db.Where("id = ?", id).First(source).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("item not found")
+		}
+		return nil, fmt.Errorf("failed to get item: %w", err)
+	}
+
+	clone := &models.Item{
+		Name:         source.Name,
+		Stock:        source.Stock,
+		Price:        source.Price,
+		Currency:     source.Currency,
+		ImageURL:     source.ImageURL,
+		ReorderPoint: source.ReorderPoint,
+		AutoReorder:  source.AutoReorder,
+		WeightGrams:  source.WeightGrams,
+		LengthMM:     source.LengthMM,
+		WidthMM:      source.WidthMM,
+		HeightMM:     source.HeightMM,
+		CategoryID:   source.CategoryID,
+		CreatedBy:    req.Actor,
+		UpdatedBy:    req.Actor,
+	}
+	if req.CategoryID != nil {
+		if _, err := findCategoryID(s.db, *req.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+	applyUpdate(clone, req)
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(clone).Error; err != nil {
+			return fmt.Errorf("failed to clone item: %w", err)
+		}
+
+		return recordMovement(tx, clone, clone.Stock, req.Reason, models.MovementReasonInitial)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	s.invalidateCache()
 
-	return nil
+	return clone, nil
 }
 
-func (s *ItemService) GetItems(pagination *models.PaginationRequest, filters *models.FilterRequest, sort *models.SortRequest) (*models.PaginatedResponse, error) {
-	query := s.db.Model(&models.Item{})
+// findCategoryID parses id and checks that the category exists. An empty id
+// means no category and returns nil.
+func findCategoryID(db *gorm.DB, id string) (*uuid.UUID, error) {
+	if id == "" {
+		return nil, nil
+	}
+	categoryID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCategoryNotFound, id)
+	}
 
-	if filters != nil {
-		if filters.Name != "" {
-			query = query.Where("name ILIKE ?", "%"+filters.Name+"%")
-		}
-		if filters.MinStock != nil {
-			query = query.Where("stock >= ?", *filters.MinStock)
-		}
-		if filters.MinPrice != nil {
-			query = query.Where("price >= ?", *filters.MinPrice)
-		}
-		if filters.MaxPrice != nil {
-			query = query.Where("price <= ?", *filters.MaxPrice)
-		}
+	var count int64
+	if err := db.Model(&models.Category{}).Where("id = ?", categoryID).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrCategoryNotFound, id)
 	}
+	return &categoryID, nil
+}
 
-	if sort != nil && sort.SortBy != "" {
-		order := "ASC"
-		if sort.SortOrder == "desc" {
-			order = "DESC"
-		}
-		query = query.Order(fmt.Sprintf("%s %s", sort.SortBy, order))
-	} else {
-		query = query.Order("created_at DESC")
+// actorOrSystem returns actor, or models.SystemActor when it is empty
+func actorOrSystem(actor string) string {
+	if actor == "" {
+		return models.SystemActor
 	}
+	return actor
+}
 
-	var total int64
-	if err := query.Count(&total).Error; err != nil {
-		return nil, fmt.Errorf("failed to count items: %w", err)
+// applyUpdate copies the fields set in req onto item
+func applyUpdate(item *models.Item, req *models.UpdateItemRequest) {
+	if req.Name != nil {
+		item.Name = *req.Name
+	}
+	if req.Stock != nil {
+		item.Stock = *req.Stock
+	}
+	if req.Price != nil {
+		item.Price = *req.Price
+	}
+	if req.Currency != nil {
+		item.Currency = *req.Currency
+	}
+	if req.ImageURL != nil {
+		item.ImageURL = *req.ImageURL
+	}
+	if req.ClearImageURL {
+		item.ImageURL = ""
+	}
+	// The category was checked to exist before the update is applied
+	if req.CategoryID != nil {
+		if categoryID, err := uuid.Parse(*req.CategoryID); err == nil {
+			item.CategoryID = &categoryID
+		}
+	}
+	if req.ClearCategory {
+		item.CategoryID = nil
+	}
+	if req.Active != nil {
+		item.Active = *req.Active
 	}
+	if req.ReorderPoint != nil {
+		item.ReorderPoint = *req.ReorderPoint
+	}
+	if req.AutoReorder != nil {
+		item.AutoReorder = *req.AutoReorder
+	}
+	if req.WeightGrams != nil {
+		item.WeightGrams = *req.WeightGrams
+	}
+	if req.LengthMM != nil {
+		item.LengthMM = *req.LengthMM
+	}
+	if req.WidthMM != nil {
+		item.WidthMM = *req.WidthMM
+	}
+	if req.HeightMM != nil {
+		item.HeightMM = *req.HeightMM
+	}
+}
 
-	var items []models.Item
-	var nextCursor string
-	var hasMore bool
+// OperationError reports the operation that caused Transact to roll back
+type OperationError struct {
+	Index  int
+	ItemID string
+	Err    error
+}
 
-	if pagination != nil && pagination.Cursor != "" {
-		cursorData, err := s.decodeCursor(pagination.Cursor)
-		if err != nil {
-			return nil, fmt.Errorf("invalid cursor: %w", err)
+func (e *OperationError) Error() string {
+	return fmt.Sprintf("operation %d on item %s failed: %v", e.Index, e.ItemID, e.Err)
+}
+
+func (e *OperationError) Unwrap() error {
+	return e.Err
+}
+
+// Transact applies every stock operation in a single transaction. If any
+// item is missing, would drop below zero stock or would exceed the maximum
+// stock nothing is changed and an *OperationError identifying the failing
+// operation is returned.
+func (s *ItemService) Transact(operations []models.StockOperation) ([]models.Item, error) {
+	items := make([]models.Item, 0, len(operations))
+
+	err := s.withRetry(false, func() error {
+		items = items[:0]
+		return s.db.Transaction(func(tx *gorm.DB) error {
+			for i, op := range operations {
+				// The guard makes the checks and the update a single atomic statement
+				result := tx.Model(&models.Item{}).
+					Where("id = ? AND stock + ? >= 0 AND stock + ? <= ?", op.ID, op.Delta, op.Delta, s.maxStock).
+					Update("stock", gorm.Expr("stock + ?", op.Delta))
+				if result.Error != nil {
+					return &OperationError{Index: i, ItemID: op.ID, Err: fmt.Errorf("failed to update stock: %w", result.Error)}
+				}
+
+				item := models.Item{}
+				if err := tx.Where("id = ?", op.ID).First(&item).Error; err != nil {
+					if err == gorm.ErrRecordNotFound {
+						return &OperationError{Index: i, ItemID: op.ID, Err: fmt.Errorf("item not found")}
+					}
+					return &OperationError{Index: i, ItemID: op.ID, Err: fmt.Errorf("failed to get item: %w", err)}
+				}
+				if result.RowsAffected == 0 {
+					if err := s.ValidateStock(item.Stock + op.Delta); err != nil {
+						return &OperationError{Index: i, ItemID: op.ID, Err: err}
+					}
+					return &OperationError{Index: i, ItemID: op.ID, Err: fmt.Errorf("insufficient stock")}
+				}
+
+				if err := recordMovement(tx, &item, op.Delta, op.Reason, models.MovementReasonTransaction); err != nil {
+					return &OperationError{Index: i, ItemID: op.ID, Err: err}
+				}
+				items = append(items, item)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateCache()
+
+	return items, nil
+}
+
+// MergeItems folds the items in req.MergeIDs into the item req.KeepID in a
+// single transaction. Their stock is added to the kept item, each move is
+// recorded in the stock ledger, and the merged items are soft-deleted. Items
+// locked by another owner cannot be merged.
+func (s *ItemService) MergeItems(req *models.MergeItemsRequest) (*models.Item, error) {
+	kept := &models.Item{}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", req.KeepID).First(kept).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("item not found")
+			}
+			return fmt.Errorf("failed to get item: %w", err)
+		}
+
+		var merged []models.Item
+		if err := tx.Where("id IN ?", req.MergeIDs).Order("item_number ASC").Find(&merged).Error; err != nil {
+			return fmt.Errorf("failed to get items: %w", err)
+		}
+		if len(merged) != len(req.MergeIDs) {
+			return fmt.Errorf("item not found")
+		}
+
+		for _, item := range append([]models.Item{*kept}, merged...) {
+			if err := checkLock(tx, item.ID, req.LockOwner); err != nil {
+				return err
+			}
+		}
+
+		total := 0
+		for i := range merged {
+			item := &merged[i]
+			stock := item.Stock
+			if stock == 0 {
+				continue
+			}
+			total += stock
+
+			item.Stock = 0
+			if err := tx.Model(item).Update("stock", 0).Error; err != nil {
+				return fmt.Errorf("failed to update item: %w", err)
+			}
+			if err := recordMovement(tx, item, -stock, req.Reason, models.MovementReasonMerge); err != nil {
+				return err
+			}
+		}
+
+		kept.Stock += total
+		kept.UpdatedBy = actorOrSystem(req.Actor)
+		if err := tx.Save(kept).Error; err != nil {
+			return fmt.Errorf("failed to update item: %w", err)
+		}
+		if total != 0 {
+			if err := recordMovement(tx, kept, total, req.Reason, models.MovementReasonMerge); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("id IN ?", req.MergeIDs).Delete(&models.Item{}).Error; err != nil {
+			return fmt.Errorf("failed to delete merged items: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateCache()
+
+	return kept, nil
+}
+
+// stockSyncBatchSize is how many SKUs SyncStock looks up per query
+const stockSyncBatchSize = 500
+
+// SyncStock sets the stock of the items with the listed SKUs in a single
+// transaction, leaving every other column alone. SKUs without an item are
+// reported as unmatched rather than failing the sync; when a SKU is listed
+// more than once the last entry wins. A stock above the configured maximum
+// fails the whole sync before anything is written.
+func (s *ItemService) SyncStock(entries []models.StockSyncEntry) (*models.StockSyncResponse, error) {
+	entries = lastEntryPerSKU(entries)
+	for _, entry := range entries {
+		if err := s.ValidateStock(*entry.Stock); err != nil {
+			return nil, fmt.Errorf("sku %s: %w", entry.SKU, err)
+		}
+	}
+
+	response := &models.StockSyncResponse{}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for start := 0; start < len(entries); start += stockSyncBatchSize {
+			batch := entries[start:min(start+stockSyncBatchSize, len(entries))]
+
+			skus := make([]string, len(batch))
+			for i, entry := range batch {
+				skus[i] = entry.SKU
+			}
+
+			var items []models.Item
+			if err := tx.Where("sku IN ?", skus).Find(&items).Error; err != nil {
+				return fmt.Errorf("failed to get items: %w", err)
+			}
+			bySKU := make(map[string]*models.Item, len(items))
+			for i := range items {
+				bySKU[*items[i].SKU] = &items[i]
+			}
+
+			for _, entry := range batch {
+				item, ok := bySKU[entry.SKU]
+				if !ok {
+					response.Unmatched++
+					response.UnmatchedSKUs = append(response.UnmatchedSKUs, entry.SKU)
+					continue
+				}
+				response.Updated++
+
+				delta := *entry.Stock - item.Stock
+				if delta == 0 {
+					continue
+				}
+				if err := tx.Model(item).Update("stock", *entry.Stock).Error; err != nil {
+					return fmt.Errorf("failed to update stock of %s: %w", entry.SKU, err)
+				}
+				if err := recordMovement(tx, item, delta, "", models.MovementReasonStockSync); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateCache()
+
+	return response, nil
+}
+
+// lastEntryPerSKU keeps one entry per SKU, in the order SKUs first appear,
+// with the stock of the last entry listing it
+func lastEntryPerSKU(entries []models.StockSyncEntry) []models.StockSyncEntry {
+	index := make(map[string]int, len(entries))
+	unique := make([]models.StockSyncEntry, 0, len(entries))
+	for _, entry := range entries {
+		if i, ok := index[entry.SKU]; ok {
+			unique[i] = entry
+			continue
+		}
+		index[entry.SKU] = len(unique)
+		unique = append(unique, entry)
+	}
+	return unique
+}
+
+// GetItemMovements returns a page of the stock ledger of an item, oldest first
+func (s *ItemService) GetItemMovements(id string, pagination *models.PaginationRequest) (*models.PaginatedMovementsResponse, error) {
+	var count int64
+	if err := s.db.Model(&models.Item{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to get item: %w", err)
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("item not found")
+	}
+
+	query := s.db.Model(&models.StockMovement{}).Where("item_id = ?", id)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count stock movements: %w", err)
+	}
+
+	if pagination != nil && pagination.Cursor != "" {
+		var cursor movementCursor
+		if err := s.unmarshalCursor(pagination.Cursor, &cursor); err != nil {
+			return nil, err
+		}
+		if cursor.ID == 0 {
+			return nil, fmt.Errorf("%w: missing movement id", ErrInvalidCursor)
+		}
+		query = query.Where("id > ?", cursor.ID)
+	}
+
+	limit := s.defaultPageSize
+	if pagination != nil && pagination.Limit > 0 {
+		limit = pagination.Limit
+	}
+
+	movements := []models.StockMovement{}
+	if err := query.Order("id ASC").Limit(limit + 1).Find(&movements).Error; err != nil {
+		return nil, fmt.Errorf("failed to get stock movements: %w", err)
+	}
+
+	response := &models.PaginatedMovementsResponse{Items: movements, Total: total}
+	if len(movements) > limit {
+		response.Items = movements[:limit]
+		response.HasMore = true
+		response.NextCursor, _ = s.encodeCursor(&movementCursor{ID: response.Items[limit-1].ID})
+	}
+
+	return response, nil
+}
+
+// recordMovement appends a stock ledger entry for item within tx. The
+// fallback reason is used when the caller did not supply one.
+func recordMovement(tx *gorm.DB, item *models.Item, delta int, reason, fallback string) error {
+	if reason == "" {
+		reason = fallback
+	}
+
+	movement := &models.StockMovement{
+		ItemID:         item.ID,
+		Delta:          delta,
+		Reason:         reason,
+		ResultingStock: item.Stock,
+	}
+	if err := tx.Create(movement).Error; err != nil {
+		return fmt.Errorf("failed to record stock movement: %w", err)
+	}
+
+	return nil
+}
+
+func (s *ItemService) DeleteItem(id string) error {
+	return s.DeleteItemUnmodifiedSince(id, time.Time{})
+}
+
+// DeleteItemUnmodifiedSince soft-deletes the item unless it changed after
+// since, in which case it returns ErrPreconditionFailed. A zero since
+// deletes the item unconditionally.
+func (s *ItemService) DeleteItemUnmodifiedSince(id string, since time.Time) error {
+	if since.IsZero() {
+		result := s.db.Where("id = ?", id).Delete(&models.Item{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete item: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("item not found")
+		}
+
+		s.invalidateCache()
+
+		return nil
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		item := &models.Item{}
+		if err := tx.Where("id = ?", id).First(item).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("item not found")
+			}
+			return fmt.Errorf("failed to get item: %w", err)
+		}
+		if modifiedSince(item, since) {
+			return ErrPreconditionFailed
+		}
+
+		if err := tx.Delete(item).Error; err != nil {
+			return fmt.Errorf("failed to delete item: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.invalidateCache()
+
+	return nil
+}
+
+// modifiedSince reports whether item changed after since. HTTP dates have
+// one second resolution, so updated_at is truncated before comparing. A
+// zero since never counts as modified.
+func modifiedSince(item *models.Item, since time.Time) bool {
+	return !since.IsZero() && item.UpdatedAt.Truncate(time.Second).After(since)
+}
+
+// BulkSoftDelete soft-deletes every item matching filters in a single UPDATE
+// and returns how many were deleted. An empty filter would match every
+// active item, so it is refused with ErrFilterRequired unless confirmAll is
+// set. Filters over the complexity budget are refused like in the listing.
+func (s *ItemService) BulkSoftDelete(filters *models.FilterRequest, confirmAll bool) (int64, error) {
+	if filters.IsEmpty() && !confirmAll {
+		return 0, ErrFilterRequired
+	}
+	if err := s.CheckFilters(filters); err != nil {
+		return 0, err
+	}
+
+	query := s.db.Model(&models.Item{})
+	if condition, args := filterConditions(s.db, filters); condition != "" {
+		query = query.Where(condition, args...)
+	} else {
+		// GORM refuses a DELETE without conditions
+		query = query.Where("1 = 1")
+	}
+
+	result := query.Delete(&models.Item{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete items: %w", result.Error)
+	}
+
+	if result.RowsAffected > 0 {
+		s.invalidateCache()
+	}
+
+	return result.RowsAffected, nil
+}
+
+// BulkTag attaches tags to every item matching filters in one transaction,
+// returning how many items matched. Tags an item already carries are left
+// as they are. Filters over the complexity budget are refused like in the
+// listing.
+func (s *ItemService) BulkTag(filters *models.FilterRequest, tags []string) (int64, error) {
+	if err := s.CheckFilters(filters); err != nil {
+		return 0, err
+	}
+
+	var ids []uuid.UUID
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.Item{})
+		if condition, args := filterConditions(tx, filters); condition != "" {
+			query = query.Where(condition, args...)
+		}
+		if err := query.Pluck("id", &ids).Error; err != nil {
+			return fmt.Errorf("failed to find items: %w", err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		now := time.Now().UTC()
+		itemTags := make([]models.ItemTag, 0, len(ids)*len(tags))
+		for _, id := range ids {
+			for _, tag := range tags {
+				itemTags = append(itemTags, models.ItemTag{ItemID: id, Tag: tag, CreatedAt: now})
+			}
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(itemTags, 500).Error; err != nil {
+			return fmt.Errorf("failed to tag items: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(ids)), nil
+}
+
+// GetItemTags returns the tags attached to an item in alphabetical order
+func (s *ItemService) GetItemTags(id string) ([]string, error) {
+	if _, err := s.GetItem(id); err != nil {
+		return nil, err
+	}
+
+	tags := []string{}
+	if err := s.db.Model(&models.ItemTag{}).Where("item_id = ?", id).Order("tag ASC").Pluck("tag", &tags).Error; err != nil {
+		return nil, fmt.Errorf("failed to get item tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// HardDeleteItem permanently removes an item, whether or not it was soft-deleted
+func (s *ItemService) HardDeleteItem(id string) error {
+	return s.HardDeleteItemUnmodifiedSince(id, time.Time{})
+}
+
+// HardDeleteItemUnmodifiedSince permanently deletes the item unless it
+// changed after since, in which case it returns ErrPreconditionFailed. A
+// zero since deletes the item unconditionally.
+func (s *ItemService) HardDeleteItemUnmodifiedSince(id string, since time.Time) error {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		item := &models.Item{}
+		if err := tx.Unscoped().Where("id = ?", id).First(item).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("item not found")
+			}
+			return fmt.Errorf("failed to get item: %w", err)
+		}
+		if modifiedSince(item, since) {
+			return ErrPreconditionFailed
+		}
+
+		if err := tx.Unscoped().Delete(item).Error; err != nil {
+			return fmt.Errorf("failed to hard delete item: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.invalidateCache()
+
+	return nil
+}
+
+// PurgeSoftDeletedItems permanently removes items that were soft-deleted
+// longer ago than the configured retention period
+func (s *ItemService) PurgeSoftDeletedItems() (int64, error) {
+	cutoff := time.Now().Add(-s.softDeleteRetention)
+
+	result := s.db.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Delete(&models.Item{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to purge deleted items: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// RefreshSearchNames brings the stored search name of every item, deleted
+// or not, in line with the current name normalization, after a migration
+// added the column or SEARCH_NORMALIZATION changed. It returns how many
+// items it updated.
+func (s *ItemService) RefreshSearchNames() (int64, error) {
+	var updated int64
+	var batch []models.Item
+	result := s.db.Unscoped().Select("id", "name", "search_name").
+		FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+			for _, item := range batch {
+				search := models.NormalizeSearchName(item.Name)
+				if search == item.SearchName {
+					continue
+				}
+				err := s.db.Unscoped().Model(&models.Item{}).Where("id = ?", item.ID).
+					UpdateColumn("search_name", search).Error
+				if err != nil {
+					return err
+				}
+				updated++
+			}
+			return nil
+		})
+	if result.Error != nil {
+		return updated, fmt.Errorf("failed to refresh search names: %w", result.Error)
+	}
+
+	return updated, nil
+}
+
+// NormalizePrices rounds the price of every item, deleted or not, to two
+// decimals in a single UPDATE and returns how many items it changed. Items
+// already priced in cents are left untouched.
+func (s *ItemService) NormalizePrices() (int64, error) {
+	result := s.db.Unscoped().Model(&models.Item{}).
+		Where("price <> ROUND(price, 2)").
+		Update("price", gorm.Expr("ROUND(price, 2)"))
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to normalize prices: %w", result.Error)
+	}
+
+	if result.RowsAffected > 0 {
+		s.invalidateCache()
+	}
+	return result.RowsAffected, nil
+}
+
+// LockItem acquires the lock on an item for owner, or renews it when owner
+// already holds it, until ttl from now. It fails with ErrItemLocked while
+// another owner holds an unexpired lock.
+func (s *ItemService) LockItem(id, owner string, ttl time.Duration) (*models.ItemLock, error) {
+	item := &models.Item{}
+	if err := s.db.Select("id").Where("id = ?", id).First(item).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("item not found")
+		}
+		return nil, fmt.Errorf("failed to get item: %w", err)
+	}
+
+	now := time.Now().UTC()
+	lock := &models.ItemLock{
+		ItemID:    item.ID,
+		Owner:     owner,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+
+	// An existing lock is only replaced when it is owner's or has expired,
+	// so two owners racing for the lock cannot both get it
+	result := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "item_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"owner", "expires_at", "created_at"}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "item_locks.owner = ? OR item_locks.expires_at <= ?", Vars: []interface{}{owner, now}},
+		}},
+	}).Create(lock)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to lock item: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrItemLocked
+	}
+
+	return lock, nil
+}
+
+// UnlockItem releases owner's lock on an item. Releasing an item that is not
+// locked, or whose lock has expired, succeeds; releasing another owner's
+// unexpired lock fails with ErrItemLocked.
+func (s *ItemService) UnlockItem(id, owner string) error {
+	now := time.Now().UTC()
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("item_id = ? AND (owner = ? OR expires_at <= ?)", id, owner, now).
+			Delete(&models.ItemLock{}).Error
+		if err != nil {
+			return fmt.Errorf("failed to unlock item: %w", err)
+		}
+
+		var held int64
+		if err := tx.Model(&models.ItemLock{}).Where("item_id = ?", id).Count(&held).Error; err != nil {
+			return fmt.Errorf("failed to unlock item: %w", err)
+		}
+		if held > 0 {
+			return ErrItemLocked
+		}
+		return nil
+	})
+}
+
+// checkLock returns ErrItemLocked when an owner other than owner holds an
+// unexpired lock on the item
+func checkLock(tx *gorm.DB, itemID uuid.UUID, owner string) error {
+	var locked int64
+	err := tx.Model(&models.ItemLock{}).
+		Where("item_id = ? AND owner <> ? AND expires_at > ?", itemID, owner, time.Now().UTC()).
+		Count(&locked).Error
+	if err != nil {
+		return fmt.Errorf("failed to check item lock: %w", err)
+	}
+	if locked > 0 {
+		return ErrItemLocked
+	}
+	return nil
+}
+
+// GetItems returns a page of items. When fields is non-empty only those
+// columns are loaded, along with the id and created_at needed for the cursor.
+func (s *ItemService) GetItems(pagination *models.PaginationRequest, filters *models.FilterRequest, sort *models.SortRequest, fields []string) (*models.PaginatedResponse, error) {
+	if err := s.CheckFilters(filters); err != nil {
+		return nil, err
+	}
+	query := s.db.Model(&models.Item{})
+
+	if filters != nil {
+		if condition, args := filterConditions(s.db, filters); condition != "" {
+			query = query.Where(condition, args...)
+		}
+	}
+
+	sortBy, sortOrder := "created_at", "desc"
+	if sort != nil && sort.SortBy != "" {
+		sortBy, sortOrder = sort.SortBy, "asc"
+		if sort.SortOrder == "desc" {
+			sortOrder = "desc"
+		}
+	}
+	// Only columns from the whitelist are written into the query
+	column, ok := models.SortColumn(sortBy)
+	key, hasKey := sortKeys[sortBy]
+	if !ok || !hasKey {
+		return nil, fmt.Errorf("%w: cannot sort by %q", ErrInvalidSort, sortBy)
+	}
+	// The ID breaks ties so that the order, and so each page, is stable
+	query = query.Order(fmt.Sprintf("%s %s, id %s", column, sortOrder, sortOrder))
+
+	var total int64
+	if err := s.withRetry(true, func() error { return query.Count(&total).Error }); err != nil {
+		return nil, fmt.Errorf("failed to count items: %w", err)
+	}
+
+	var items []models.Item
+	var nextCursor string
+	var hasMore bool
+
+	if pagination != nil && pagination.Cursor != "" {
+		cursorData, err := s.decodeCursor(pagination.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if cursorData.SortBy != sortBy || cursorData.SortOrder != sortOrder {
+			return nil, fmt.Errorf("%w: it was issued for sort_by=%s&sort_order=%s; start again from the first page to change the sort",
+				ErrInvalidCursor, cursorData.SortBy, cursorData.SortOrder)
+		}
+
+		// decodeCursor has already validated the value
+		value, _ := key.parse(cursorData.Value)
+		operator := ">"
+		if sortOrder == "desc" {
+			operator = "<"
+		}
+		query = query.Where(fmt.Sprintf("(%s %s ?) OR (%s = ? AND id %s ?)", column, operator, column, operator),
+			value, value, cursorData.ID)
+	}
+
+	limit := s.defaultPageSize
+	if pagination != nil && pagination.Limit > 0 {
+		limit = pagination.Limit
+	}
+	query = query.Limit(limit + 1)
+
+	if len(fields) > 0 {
+		query = query.Select(selectColumns(fields, column))
+	}
+
+	if err := s.withRetry(true, func() error { return query.Find(&items).Error }); err != nil {
+		return nil, fmt.Errorf("failed to get items: %w", err)
+	}
+
+	if len(items) > limit {
+		hasMore = true
+		items = items[:limit]
+	}
+
+	if hasMore && len(items) > 0 {
+		lastItem := items[len(items)-1]
+		nextCursor, _ = s.encodeCursor(&CursorData{
+			ID:        lastItem.ID.String(),
+			SortBy:    sortBy,
+			SortOrder: sortOrder,
+			Value:     key.format(&lastItem),
+		})
+	}
+
+	return &models.PaginatedResponse{
+		Items:      items,
+		NextCursor: nextCursor,
+		HasMore:    hasMore,
+		Total:      total,
+	}, nil
+}
+
+// selectColumns returns the columns fields are read from, computed fields
+// being replaced by their source columns, plus the columns required to build
+// a cursor for a list sorted by sortColumn
+func selectColumns(fields []string, sortColumn string) []string {
+	var columns []string
+	add := func(column string) {
+		if !slices.Contains(columns, column) {
+			columns = append(columns, column)
+		}
+	}
+
+	for _, field := range fields {
+		if sources, ok := models.ComputedItemFields[field]; ok {
+			for _, source := range sources {
+				add(source)
+			}
+			continue
+		}
+		add(field)
+	}
+	add("id")
+	add(sortColumn)
+
+	return columns
+}
+
+// SuggestNames returns up to limit distinct item names starting with prefix,
+// ignoring case and ordered alphabetically
+func (s *ItemService) SuggestNames(prefix string, limit int) ([]string, error) {
+	names := []string{}
+	err := s.db.Model(&models.Item{}).
+		Distinct("name").
+		Where(fmt.Sprintf(`name %s ? ESCAPE '\'`, likeOperator(s.db)), escapeLike(prefix)+"%").
+		Where("active = ?", true).
+		Order("name ASC").
+		Limit(limit).
+		Pluck("name", &names).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest names: %w", err)
+	}
+
+	return names, nil
+}
+
+// SampleItems returns up to n items picked at random. Both PostgreSQL and
+// SQLite provide RANDOM(), so the database shuffles and only n rows come back.
+func (s *ItemService) SampleItems(n int) ([]models.Item, error) {
+	items := []models.Item{}
+	if err := s.db.Order("RANDOM()").Limit(n).Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("failed to sample items: %w", err)
+	}
+
+	return items, nil
+}
+
+// normalizedName is the SQL expression items are compared by when looking for duplicates
+const normalizedName = "LOWER(TRIM(name))"
+
+// FindDuplicates groups items, active or not, whose names are equal once
+// lowercased and trimmed, leaving out names that only one item has. Groups
+// are ordered by name and the items in each by item number.
+func (s *ItemService) FindDuplicates() (*models.DuplicatesResponse, error) {
+	duplicated := s.db.Model(&models.Item{}).
+		Select(normalizedName).
+		Group(normalizedName).
+		Having("COUNT(*) > 1")
+
+	var rows []struct {
+		models.Item
+		NormalizedName string
+	}
+	err := s.db.Model(&models.Item{}).
+		Select("*, " + normalizedName + " AS normalized_name").
+		Where(normalizedName+" IN (?)", duplicated).
+		Order("normalized_name ASC, item_number ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find duplicate items: %w", err)
+	}
+
+	response := &models.DuplicatesResponse{Groups: []models.DuplicateGroup{}}
+	for _, row := range rows {
+		// Scan skips the AfterFind hook
+		row.Item.VolumeMM3 = row.Item.Volume()
+
+		last := len(response.Groups) - 1
+		if last < 0 || response.Groups[last].NormalizedName != row.NormalizedName {
+			response.Groups = append(response.Groups, models.DuplicateGroup{NormalizedName: row.NormalizedName})
+			last++
+		}
+		response.Groups[last].Items = append(response.Groups[last].Items, row.Item)
+	}
+
+	return response, nil
+}
+
+// GetLowStockItems returns the active items whose stock is below their
+// reorder point, lowest stock first
+func (s *ItemService) GetLowStockItems() ([]models.Item, error) {
+	items := []models.Item{}
+	err := lowStock(s.db.Model(&models.Item{}).Where("active = ?", true)).
+		Order("stock ASC, name ASC").
+		Find(&items).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get low stock items: %w", err)
+	}
+
+	return items, nil
+}
+
+// lowStock narrows db to items whose stock is below their own reorder point,
+// or below DefaultLowStockThreshold for items without one
+func lowStock(db *gorm.DB) *gorm.DB {
+	return db.Where("stock < CASE WHEN reorder_point > 0 THEN reorder_point ELSE ? END", DefaultLowStockThreshold)
+}
+
+// likeOperator returns the case-insensitive LIKE operator for the database.
+// SQLite has no ILIKE, but its LIKE already ignores case.
+func likeOperator(db *gorm.DB) string {
+	if db.Dialector.Name() == "postgres" {
+		return "ILIKE"
+	}
+	return "LIKE"
+}
+
+// escapeLike escapes LIKE wildcards so value is matched literally
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// filterConditions builds the WHERE condition for a set of filters on db.
+// Unless the filters say otherwise, only active items match.
+func filterConditions(db *gorm.DB, filters *models.FilterRequest) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	// Names are matched after the same normalization as the stored search
+	// names, falling back to the raw name when nothing is left of the search
+	if filters.Name != "" {
+		if search := models.NormalizeSearchName(filters.Name); search != "" {
+			conditions = append(conditions, fmt.Sprintf("search_name %s ?", likeOperator(db)))
+			args = append(args, "%"+search+"%")
+		} else {
+			conditions = append(conditions, fmt.Sprintf("name %s ?", likeOperator(db)))
+			args = append(args, "%"+filters.Name+"%")
+		}
+	}
+	if filters.MinStock != nil {
+		conditions = append(conditions, "stock >= ?")
+		args = append(args, *filters.MinStock)
+	}
+	if filters.MinPrice != nil {
+		conditions = append(conditions, "price >= ?")
+		args = append(args, *filters.MinPrice)
+	}
+	if filters.MaxPrice != nil {
+		conditions = append(conditions, "price <= ?")
+		args = append(args, *filters.MaxPrice)
+	}
+	if filters.PriceEq != nil {
+		conditions = append(conditions, "price = ?")
+		args = append(args, *filters.PriceEq)
+	}
+	if filters.CreatedAfter != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filters.CreatedAfter.UTC())
+	}
+	if filters.CreatedBefore != nil {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, filters.CreatedBefore.UTC())
+	}
+	if filters.Uncategorized {
+		conditions = append(conditions, "category_id IS NULL")
+	}
+	// Discontinued items are hidden unless asked for
+	if filters.Active != nil {
+		conditions = append(conditions, "active = ?")
+		args = append(args, *filters.Active)
+	} else if !filters.IncludeInactive {
+		conditions = append(conditions, "active = ?")
+		args = append(args, true)
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// ExportLimit checks the items matching filters against MAX_EXPORT_ROWS
+// before an export starts. It returns the number of matching items and
+// the limit to pass to ExportItems: 0 when they all fit, otherwise the
+// maximum, so the export is truncated. When large exports are rejected it
+// fails with ErrExportTooLarge instead.
+func (s *ItemService) ExportLimit(filters *models.FilterRequest) (int, int64, error) {
+	if s.maxExportRows == 0 {
+		return 0, 0, nil
+	}
+	if err := s.CheckFilters(filters); err != nil {
+		return 0, 0, err
+	}
+	query := s.db.Model(&models.Item{})
+	if condition, args := filterConditions(s.db, filters); condition != "" {
+		query = query.Where(condition, args...)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return 0, 0, fmt.Errorf("failed to count items to export: %w", err)
+	}
+	if total <= int64(s.maxExportRows) {
+		return 0, total, nil
+	}
+	if s.rejectLargeExports {
+		return 0, total, fmt.Errorf("%w: %d items match but at most %d can be exported, narrow the filters",
+			ErrExportTooLarge, total, s.maxExportRows)
+	}
+	return s.maxExportRows, total, nil
+}
+
+// ExportItems calls fn with every item matching filters in item number
+// order, so the oldest come first, stopping after limit items unless limit
+// is 0. Items are read one row at a time, so memory use does not grow with
+// the number of items. An error from fn stops the export and is returned.
+func (s *ItemService) ExportItems(filters *models.FilterRequest, limit int, fn func(item *models.Item) error) error {
+	if err := s.CheckFilters(filters); err != nil {
+		return err
+	}
+	query := s.db.Model(&models.Item{}).Order("item_number ASC")
+	if condition, args := filterConditions(s.db, filters); condition != "" {
+		query = query.Where(condition, args...)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	rows, err := query.Rows()
+	if err != nil {
+		return fmt.Errorf("failed to export items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.Item
+		if err := s.db.ScanRows(rows, &item); err != nil {
+			return fmt.Errorf("failed to scan item: %w", err)
+		}
+		// ScanRows skips the AfterFind hook
+		item.VolumeMM3 = item.Volume()
+
+		if err := fn(&item); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to export items: %w", err)
+	}
+
+	return nil
+}
+
+// LastModified returns the latest change to the items matching filters, or
+// the zero time when none match. Soft-deleted items are included, so deleting
+// a matching item also moves the time forward.
+func (s *ItemService) LastModified(filters *models.FilterRequest) (time.Time, error) {
+	if err := s.CheckFilters(filters); err != nil {
+		return time.Time{}, err
+	}
+	query := func() *gorm.DB {
+		query := s.db.Unscoped().Model(&models.Item{})
+		if condition, args := filterConditions(s.db, filters); condition != "" {
+			query = query.Where(condition, args...)
+		}
+		return query
+	}
+
+	var updated, deleted []time.Time
+	if err := query().Order("updated_at DESC").Limit(1).Pluck("updated_at", &updated).Error; err != nil {
+		return time.Time{}, fmt.Errorf("failed to get last modified time: %w", err)
+	}
+	if err := query().Where("deleted_at IS NOT NULL").Order("deleted_at DESC").Limit(1).
+		Pluck("deleted_at", &deleted).Error; err != nil {
+		return time.Time{}, fmt.Errorf("failed to get last modified time: %w", err)
+	}
+
+	var lastModified time.Time
+	for _, t := range append(updated, deleted...) {
+		if t.After(lastModified) {
+			lastModified = t
+		}
+	}
+	return lastModified, nil
+}
+
+// GetBatchCounts counts the items matching each named filter using a
+// single query with one conditional aggregate per filter. Every filter must
+// fit the complexity budget on its own.
+func (s *ItemService) GetBatchCounts(filters []models.NamedFilter) (map[string]int64, error) {
+	for i := range filters {
+		if err := s.CheckFilters(&filters[i].Filter); err != nil {
+			return nil, fmt.Errorf("filter %s: %w", filters[i].Name, err)
 		}
+	}
+
+	selects := make([]string, len(filters))
+	var args []interface{}
 
-		query = query.Where("(created_at < ?) OR (created_at = ? AND id < ?)",
-			cursorData.CreatedAt, cursorData.CreatedAt, cursorData.ID)
+	for i := range filters {
+		condition, conditionArgs := filterConditions(s.db, &filters[i].Filter)
+		if condition == "" {
+			condition = "1 = 1"
+		}
+		selects[i] = fmt.Sprintf("COUNT(CASE WHEN %s THEN 1 END) AS count_%d", condition, i)
+		args = append(args, conditionArgs...)
 	}
 
-	limit := 10
-	if pagination != nil && pagination.Limit > 0 {
-		limit = pagination.Limit
+	rows, err := s.db.Model(&models.Item{}).Select(strings.Join(selects, ", "), args...).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count items: %w", err)
 	}
-	query = query.Limit(limit + 1)
+	defer rows.Close()
 
-	if err := query.Find(&items).Error; err != nil {
-		return nil, fmt.Errorf("failed to get items: %w", err)
+	counts := make([]int64, len(filters))
+	dest := make([]interface{}, len(filters))
+	for i := range counts {
+		dest[i] = &counts[i]
 	}
 
-	if len(items) > limit {
-		hasMore = true
-		items = items[:limit]
+	if rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan counts: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to count items: %w", err)
 	}
 
-	if hasMore && len(items) > 0 {
-		lastItem := items[len(items)-1]
-		nextCursor, _ = s.encodeCursor(&CursorData{
-			ID:        lastItem.ID.String(),
-			CreatedAt: lastItem.CreatedAt.Format(time.RFC3339Nano),
-		})
+	result := make(map[string]int64, len(filters))
+	for i, filter := range filters {
+		result[filter.Name] = counts[i]
 	}
 
-	return &models.PaginatedResponse{
-		Items:      items,
-		NextCursor: nextCursor,
-		HasMore:    hasMore,
-		Total:      total,
-	}, nil
+	return result, nil
 }
 
-func (s *ItemService) SeedDatabase() error {
-	var count int64
-	s.db.Model(&models.Item{}).Count(&count)
-	if count > 0 {
-		return nil
-	}
+// seedLockKey identifies the PostgreSQL advisory lock that serializes seeding
+const seedLockKey = 20240101
 
+// SeedDatabase inserts sample items when the table is empty. The check and the
+// insert run in one transaction holding an advisory lock, so servers starting
+// at the same time cannot both seed.
+func (s *ItemService) SeedDatabase() error {
 	sampleItems := []models.Item{
 		{Name: "Laptop", Stock: 50, Price: 999.99},
 		{Name: "Mouse", Stock: 200, Price: 25.99},
@@ -231,29 +1826,48 @@ func (s *ItemService) SeedDatabase() error {
 		{Name: "Smartphone", Stock: 40, Price: 699.99},
 	}
 
-	var wg sync.WaitGroup
-	errors := make(chan error, len(sampleItems))
-
-	for _, item := range sampleItems {
-		wg.Add(1)
-		go func(item models.Item) {
-			defer wg.Done()
-			if err := s.db.Create(&item).Error; err != nil {
-				errors <- fmt.Errorf("failed to create item %s: %w", item.Name, err)
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if tx.Dialector.Name() == "postgres" {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", seedLockKey).Error; err != nil {
+				return fmt.Errorf("failed to acquire seed lock: %w", err)
 			}
-		}(item)
-	}
+		}
 
-	wg.Wait()
-	close(errors)
+		var count int64
+		if err := tx.Model(&models.Item{}).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to count items: %w", err)
+		}
+		if count > 0 {
+			return nil
+		}
 
-	for err := range errors {
-		if err != nil {
-			return err
+		if err := tx.Create(&sampleItems).Error; err != nil {
+			return fmt.Errorf("failed to create sample items: %w", err)
 		}
+
+		return nil
+	})
+}
+
+// WarmCache loads the n most recently updated items into the item cache so
+// the first lookups after startup do not all hit the database. It returns
+// the number of items cached.
+func (s *ItemService) WarmCache(n int) (int, error) {
+	if s.cache == nil || n <= 0 {
+		return 0, nil
 	}
 
-	return nil
+	var items []models.Item
+	if err := s.db.Order("updated_at DESC").Limit(n).Find(&items).Error; err != nil {
+		return 0, fmt.Errorf("failed to load items for cache warm-up: %w", err)
+	}
+
+	for i := range items {
+		s.setCache(items[i].ID.String(), &items[i])
+	}
+	s.cache.Wait()
+
+	return len(items), nil
 }
 
 func (s *ItemService) getFromCache(id string) *models.Item {
@@ -274,7 +1888,20 @@ func (s *ItemService) setCache(id string, item *models.Item) {
 		return
 	}
 	
-	s.cache.SetWithTTL(id, item, 1, 5*time.Minute)
+	s.cache.SetWithTTL(id, item, itemCost(item), 5*time.Minute)
+}
+
+// itemBaseCost is a rough size in bytes of an item's fixed-size fields
+const itemBaseCost int64 = 256
+
+// itemCost estimates the bytes an item holds in the cache: its fixed-size
+// fields plus the strings it holds
+func itemCost(item *models.Item) int64 {
+	cost := itemBaseCost + int64(len(item.Name)+len(item.SearchName)+len(item.Currency)+len(item.ImageURL)+len(item.CreatedBy)+len(item.UpdatedBy))
+	if item.SKU != nil {
+		cost += int64(len(*item.SKU))
+	}
+	return cost
 }
 
 func (s *ItemService) invalidateCache() {
@@ -291,52 +1918,626 @@ func (s *ItemService) Close() {
 	}
 }
 
-func (s *ItemService) encodeCursor(cursor *CursorData) (string, error) {
+// encodeCursor serializes a cursor, appending an HMAC signature when a
+// cursor secret is configured
+func (s *ItemService) encodeCursor(cursor interface{}) (string, error) {
 	data, err := json.Marshal(cursor)
 	if err != nil {
 		return "", err
 	}
-	return base64.StdEncoding.EncodeToString(data), nil
+
+	encoded := base64.StdEncoding.EncodeToString(data)
+	if len(s.cursorSecret) == 0 {
+		return encoded, nil
+	}
+
+	return encoded + "." + s.signCursor(encoded), nil
 }
 
+// decodeCursor parses and validates a cursor. Every failure wraps
+// ErrInvalidCursor so callers can report it as a client error.
 func (s *ItemService) decodeCursor(cursor string) (*CursorData, error) {
-	data, err := base64.StdEncoding.DecodeString(cursor)
+	var cursorData CursorData
+	if err := s.unmarshalCursor(cursor, &cursorData); err != nil {
+		return nil, err
+	}
+
+	if _, err := uuid.Parse(cursorData.ID); err != nil {
+		return nil, fmt.Errorf("%w: id is not a valid UUID", ErrInvalidCursor)
+	}
+	key, ok := sortKeys[cursorData.SortBy]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown sort_by %q", ErrInvalidCursor, cursorData.SortBy)
+	}
+	if _, err := key.parse(cursorData.Value); err != nil {
+		return nil, fmt.Errorf("%w: value is not a valid %s", ErrInvalidCursor, cursorData.SortBy)
+	}
+
+	return &cursorData, nil
+}
+
+// DescribeCursor decodes a list cursor for debugging pagination. Besides
+// the position it records, it reports whether the item it points at still
+// exists and, if so, whether the item has since moved in the sort order.
+func (s *ItemService) DescribeCursor(cursor string) (*models.CursorInfo, error) {
+	cursorData, err := s.decodeCursor(cursor)
 	if err != nil {
 		return nil, err
 	}
 
-	var cursorData CursorData
-	if err := json.Unmarshal(data, &cursorData); err != nil {
+	info := &models.CursorInfo{
+		ID:        cursorData.ID,
+		SortBy:    cursorData.SortBy,
+		SortOrder: cursorData.SortOrder,
+		Value:     cursorData.Value,
+	}
+
+	var item models.Item
+	err = s.db.Where("id = ?", cursorData.ID).First(&item).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return info, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cursor item: %w", err)
+	}
+
+	info.Exists = true
+	info.CurrentValue = sortKeys[cursorData.SortBy].format(&item)
+	info.Moved = info.CurrentValue != cursorData.Value
+	return info, nil
+}
+
+// unmarshalCursor checks the signature of a cursor and decodes its payload
+// into v. Every failure wraps ErrInvalidCursor.
+func (s *ItemService) unmarshalCursor(cursor string, v interface{}) error {
+	encoded := cursor
+	if len(s.cursorSecret) > 0 {
+		payload, signature, found := strings.Cut(cursor, ".")
+		if !found {
+			return fmt.Errorf("%w: missing signature", ErrInvalidCursor)
+		}
+		if !hmac.Equal([]byte(signature), []byte(s.signCursor(payload))) {
+			return fmt.Errorf("%w: signature mismatch", ErrInvalidCursor)
+		}
+		encoded = payload
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	return nil
+}
+
+func (s *ItemService) signCursor(payload string) string {
+	mac := hmac.New(sha256.New, s.cursorSecret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// GetStatsForIDs aggregates count, stock and the value in each currency
+// over the given items. The overall value is only set when they share one
+// currency. IDs that do not exist are ignored.
+func (s *ItemService) GetStatsForIDs(ids []string) (*models.IDStatsResponse, error) {
+	matching := s.db.Model(&models.Item{}).Where("id IN ?", ids).Session(&gorm.Session{})
+
+	var stats models.IDStatsResponse
+	err := matching.Select("COUNT(*) as count, COALESCE(SUM(stock), 0) as total_stock").Scan(&stats).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats for ids: %w", err)
+	}
+	byCurrency, err := valueByCurrency(matching)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats for ids: %w", err)
+	}
+	stats.TotalValueByCurrency = byCurrency
+	stats.TotalValue = singleCurrencyAmount(byCurrency)
+
+	return &stats, nil
+}
+
+// CompareItems returns the items with ids in the requested order, each
+// with its price and stock difference from the first. It fails with "item
+// not found" when any of them does not exist.
+func (s *ItemService) CompareItems(ids []string) (*models.CompareItemsResponse, error) {
+	var items []models.Item
+	if err := s.db.Where("id IN ?", ids).Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("failed to get items: %w", err)
+	}
+
+	byID := make(map[string]models.Item, len(items))
+	for _, item := range items {
+		byID[item.ID.String()] = item
+	}
+
+	response := &models.CompareItemsResponse{Items: make([]models.ItemComparison, 0, len(ids))}
+	for _, id := range ids {
+		item, ok := byID[strings.ToLower(id)]
+		if !ok {
+			return nil, fmt.Errorf("item not found")
+		}
+		base := item
+		if len(response.Items) > 0 {
+			base = response.Items[0].Item
+		}
+		response.Items = append(response.Items, models.NewItemComparison(item, base))
+	}
+
+	return response, nil
+}
+
+// GetRelatedItems returns up to limit active items related to the item
+// with id: those in the same category, or also uncategorized, priced in the
+// same currency within models.RelatedPriceSpread of its price. The closest
+// prices come first and the item itself is left out.
+func (s *ItemService) GetRelatedItems(id string, limit int) (*models.RelatedItemsResponse, error) {
+	item, err := s.GetItem(id)
+	if err != nil {
 		return nil, err
 	}
 
-	return &cursorData, nil
+	query := s.db.Model(&models.Item{}).
+		Where("id <> ? AND active = ? AND currency = ?", item.ID, true, item.Currency).
+		Where("price BETWEEN ? AND ?", item.Price*(1-models.RelatedPriceSpread), item.Price*(1+models.RelatedPriceSpread))
+	if item.CategoryID != nil {
+		query = query.Where("category_id = ?", *item.CategoryID)
+	} else {
+		query = query.Where("category_id IS NULL")
+	}
+
+	related := []models.Item{}
+	closest := clause.Expr{SQL: "ABS(price - ?), id ASC", Vars: []interface{}{item.Price}}
+	err = query.Order(clause.OrderBy{Expression: closest}).Limit(limit).Find(&related).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get related items: %w", err)
+	}
+
+	return &models.RelatedItemsResponse{Items: related}, nil
+}
+
+// GetListAggregate totals the stock of every item matching filters, and
+// their value and average price in each currency. The overall value and
+// average price are only set when the items share one currency.
+func (s *ItemService) GetListAggregate(filters *models.FilterRequest) (*models.ListAggregate, error) {
+	if err := s.CheckFilters(filters); err != nil {
+		return nil, err
+	}
+	query := s.db.Model(&models.Item{}).
+		Select("currency, SUM(price * stock) as total_value, SUM(stock) as total_stock, AVG(price) as avg_price").
+		Group("currency")
+	if condition, args := filterConditions(s.db, filters); condition != "" {
+		query = query.Where(condition, args...)
+	}
+
+	var currencies []struct {
+		Currency   string
+		TotalValue float64
+		TotalStock int64
+		AvgPrice   float64
+	}
+	if err := query.Scan(&currencies).Error; err != nil {
+		return nil, fmt.Errorf("failed to get list aggregate: %w", err)
+	}
+
+	aggregate := &models.ListAggregate{
+		TotalValueByCurrency: make(models.CurrencyAmounts, len(currencies)),
+		AvgPriceByCurrency:   make(models.CurrencyAmounts, len(currencies)),
+	}
+	for _, currency := range currencies {
+		aggregate.TotalValueByCurrency[currency.Currency] = currency.TotalValue
+		aggregate.AvgPriceByCurrency[currency.Currency] = currency.AvgPrice
+		aggregate.TotalStock += currency.TotalStock
+	}
+	aggregate.TotalValue = singleCurrencyAmount(aggregate.TotalValueByCurrency)
+	aggregate.AvgPrice = singleCurrencyAmount(aggregate.AvgPriceByCurrency)
+
+	return aggregate, nil
+}
+
+// GetInventoryValue sums price * stock per currency over the items
+// matching filters. The overall total is only set when they share one
+// currency.
+func (s *ItemService) GetInventoryValue(filters *models.FilterRequest) (*models.InventoryValueResponse, error) {
+	if err := s.CheckFilters(filters); err != nil {
+		return nil, err
+	}
+	query := s.db.Model(&models.Item{})
+	if condition, args := filterConditions(s.db, filters); condition != "" {
+		query = query.Where(condition, args...)
+	}
+	matching := query.Session(&gorm.Session{})
+
+	var value models.InventoryValueResponse
+	if err := matching.Count(&value.ItemCount).Error; err != nil {
+		return nil, fmt.Errorf("failed to get inventory value: %w", err)
+	}
+	byCurrency, err := valueByCurrency(matching)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inventory value: %w", err)
+	}
+	value.TotalValueByCurrency = byCurrency
+	value.TotalValue = singleCurrencyAmount(byCurrency)
+
+	return &value, nil
 }
 
-func (s *ItemService) GetItemStats() (map[string]interface{}, error) {
+// valueByCurrency sums price * stock over the items of query for each
+// currency they are priced in
+func valueByCurrency(query *gorm.DB) (map[string]float64, error) {
+	var values []struct {
+		Currency   string
+		TotalValue float64
+	}
+	if err := query.Select("currency, SUM(price * stock) as total_value").Group("currency").Scan(&values).Error; err != nil {
+		return nil, err
+	}
+
+	byCurrency := make(map[string]float64, len(values))
+	for _, value := range values {
+		byCurrency[value.Currency] = value.TotalValue
+	}
+	return byCurrency, nil
+}
+
+// singleCurrencyAmount returns the amount of byCurrency when it holds at
+// most one currency, zero when it holds none, and nil otherwise, as summing
+// or averaging prices across currencies is meaningless
+func singleCurrencyAmount(byCurrency map[string]float64) *float64 {
+	if len(byCurrency) > 1 {
+		return nil
+	}
+	total := 0.0
+	for _, value := range byCurrency {
+		total = value
+	}
+	return &total
+}
+
+// computeItemStats aggregates the stats of the items matching filters,
+// which by default are the active items
+func (s *ItemService) computeItemStats(filters *models.FilterRequest) (map[string]interface{}, error) {
 	var stats struct {
 		TotalItems    int64   `json:"total_items"`
 		TotalValue    float64 `json:"total_value"`
 		AveragePrice  float64 `json:"average_price"`
+		MinPrice      float64 `json:"min_price"`
+		MaxPrice      float64 `json:"max_price"`
+		MinStock      int64   `json:"min_stock"`
+		MaxStock      int64   `json:"max_stock"`
 		LowStockItems int64   `json:"low_stock_items"`
 	}
 
-	if err := s.db.Model(&models.Item{}).Count(&stats.TotalItems).Error; err != nil {
+	// Discontinued items are left out of the stats unless filters ask for them
+	query := s.db.Model(&models.Item{})
+	if condition, args := filterConditions(s.db, filters); condition != "" {
+		query = query.Where(condition, args...)
+	}
+	matching := query.Session(&gorm.Session{})
+
+	// Aggregates are NULL over no rows, so they are reported as zero
+	if err := matching.Select(`COUNT(*) as total_items,
+		COALESCE(SUM(price * stock), 0) as total_value,
+		COALESCE(AVG(price), 0) as average_price,
+		COALESCE(MIN(price), 0) as min_price,
+		COALESCE(MAX(price), 0) as max_price,
+		COALESCE(MIN(stock), 0) as min_stock,
+		COALESCE(MAX(stock), 0) as max_stock`).Scan(&stats).Error; err != nil {
 		return nil, err
 	}
 
-	if err := s.db.Model(&models.Item{}).Select("SUM(price * stock) as total_value, AVG(price) as average_price").Scan(&stats).Error; err != nil {
+	if err := lowStock(matching).Count(&stats.LowStockItems).Error; err != nil {
 		return nil, err
 	}
 
-	if err := s.db.Model(&models.Item{}).Where("stock < ?", 10).Count(&stats.LowStockItems).Error; err != nil {
+	byCurrency, err := valueByCurrency(matching)
+	if err != nil {
 		return nil, err
 	}
 
+	// Summing prices across currencies is meaningless, so the overall
+	// total is only reported when every item shares one currency
+	var totalValue interface{} = stats.TotalValue
+	if len(byCurrency) > 1 {
+		totalValue = nil
+	}
+
 	return map[string]interface{}{
-		"total_items":     stats.TotalItems,
-		"total_value":     stats.TotalValue,
-		"average_price":   stats.AveragePrice,
-		"low_stock_items": stats.LowStockItems,
+		"total_items":             stats.TotalItems,
+		"total_value":             totalValue,
+		"total_value_by_currency": byCurrency,
+		"average_price":           stats.AveragePrice,
+		"min_price":               stats.MinPrice,
+		"max_price":               stats.MaxPrice,
+		"min_stock":               stats.MinStock,
+		"max_stock":               stats.MaxStock,
+		"low_stock_items":         stats.LowStockItems,
 	}, nil
 }
+
+// CreateCategory adds a category, under the parent named in req if any.
+// Categories cannot nest deeper than models.MaxCategoryDepth.
+func (s *ItemService) CreateCategory(req *models.CreateCategoryRequest) (*models.Category, error) {
+	category := &models.Category{Name: req.Name}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		parentID, err := findCategoryID(tx, req.ParentID)
+		if err != nil {
+			return err
+		}
+		category.ParentID = parentID
+
+		// Walk up from the parent, stopping at the depth limit
+		depth := 1
+		for id := parentID; id != nil; depth++ {
+			if depth >= models.MaxCategoryDepth {
+				return fmt.Errorf("%w: at most %d levels are allowed", ErrCategoryTooDeep, models.MaxCategoryDepth)
+			}
+			var parent models.Category
+			if err := tx.Select("id", "parent_id").Where("id = ?", id).Take(&parent).Error; err != nil {
+				return fmt.Errorf("failed to get category: %w", err)
+			}
+			id = parent.ParentID
+		}
+
+		if err := tx.Create(category).Error; err != nil {
+			return fmt.Errorf("failed to create category: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// GetCategoryCounts returns every category with the number of active items
+// filed directly under it, most items first. Categories without items are
+// included with a count of 0.
+func (s *ItemService) GetCategoryCounts() ([]models.CategoryCount, error) {
+	counts := []models.CategoryCount{}
+	err := s.db.Model(&models.Category{}).
+		Select("categories.id AS id, categories.name AS category, COUNT(items.id) AS count").
+		Joins("LEFT JOIN items ON items.category_id = categories.id AND items.deleted_at IS NULL AND items.active = ?", true).
+		Group("categories.id, categories.name").
+		Order("COUNT(items.id) DESC, categories.name ASC").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count items by category: %w", err)
+	}
+
+	return counts, nil
+}
+
+// GetItemTree returns the active items grouped under the category
+// hierarchy. Subcategories below models.MaxCategoryDepth, or caught in a
+// parent cycle, cannot be reached from the top level; their items are
+// listed as uncategorized rather than dropped.
+func (s *ItemService) GetItemTree() (*models.ItemTreeResponse, error) {
+	var categories []models.Category
+	if err := s.db.Order("name ASC").Find(&categories).Error; err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+
+	condition, args := filterConditions(s.db, &models.FilterRequest{})
+	var items []models.Item
+	if err := s.db.Where(condition, args...).Order("name ASC").Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("failed to get items: %w", err)
+	}
+
+	var totals []struct {
+		CategoryID *uuid.UUID
+		ItemCount  int
+		TotalStock int
+	}
+	err := s.db.Model(&models.Item{}).
+		Select("category_id, COUNT(*) AS item_count, COALESCE(SUM(stock), 0) AS total_stock").
+		Where(condition, args...).
+		Where("category_id IS NOT NULL").
+		Group("category_id").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count items by category: %w", err)
+	}
+
+	nodes := make(map[uuid.UUID]*models.CategoryNode, len(categories))
+	children := make(map[uuid.UUID][]*models.CategoryNode)
+	var roots []*models.CategoryNode
+	for _, category := range categories {
+		node := &models.CategoryNode{
+			ID:       category.ID,
+			Name:     category.Name,
+			Items:    []models.Item{},
+			Children: []*models.CategoryNode{},
+		}
+		nodes[category.ID] = node
+		if category.ParentID == nil {
+			roots = append(roots, node)
+		} else {
+			children[*category.ParentID] = append(children[*category.ParentID], node)
+		}
+	}
+	for _, total := range totals {
+		if node, ok := nodes[*total.CategoryID]; ok {
+			node.ItemCount = total.ItemCount
+			node.TotalStock = total.TotalStock
+		}
+	}
+
+	// Attach subcategories level by level, adding their totals to each
+	// ancestor on the way back up
+	reached := make(map[uuid.UUID]bool, len(categories))
+	var attach func(node *models.CategoryNode, depth int)
+	attach = func(node *models.CategoryNode, depth int) {
+		reached[node.ID] = true
+		if depth >= models.MaxCategoryDepth {
+			return
+		}
+		for _, child := range children[node.ID] {
+			if reached[child.ID] {
+				continue
+			}
+			attach(child, depth+1)
+			node.Children = append(node.Children, child)
+			node.ItemCount += child.ItemCount
+			node.TotalStock += child.TotalStock
+		}
+	}
+	for _, root := range roots {
+		attach(root, 1)
+	}
+
+	tree := &models.ItemTreeResponse{
+		Categories:    roots,
+		Uncategorized: []models.Item{},
+	}
+	if tree.Categories == nil {
+		tree.Categories = []*models.CategoryNode{}
+	}
+	for _, item := range items {
+		if item.CategoryID != nil && reached[*item.CategoryID] {
+			node := nodes[*item.CategoryID]
+			node.Items = append(node.Items, item)
+			continue
+		}
+		tree.Uncategorized = append(tree.Uncategorized, item)
+	}
+
+	return tree, nil
+}
+
+// GroupItems buckets the items matching filters by the value of field,
+// ordered by name within each group. Categories are keyed by ID, with
+// their names in the response's labels, and items in no category are
+// grouped under models.UncategorizedGroup. With counts only the size of
+// each group is returned, counted by the database; otherwise at most
+// models.MaxGroupedItems items are loaded and more fail with
+// ErrTooManyToGroup.
+func (s *ItemService) GroupItems(filters *models.FilterRequest, field string, counts bool) (*models.GroupedItemsResponse, error) {
+	if !models.IsGroupField(field) {
+		return nil, fmt.Errorf("%w: cannot group by %q", ErrInvalidGroupField, field)
+	}
+	if err := s.CheckFilters(filters); err != nil {
+		return nil, err
+	}
+
+	query := s.db.Model(&models.Item{})
+	if condition, args := filterConditions(s.db, filters); condition != "" {
+		query = query.Where(condition, args...)
+	}
+
+	response := &models.GroupedItemsResponse{Field: field}
+	var keys []string
+	if counts {
+		var rows []struct {
+			GroupKey *string
+			Count    int
+		}
+		err := query.Select(groupKeyColumn(field) + " AS group_key, COUNT(*) AS count").
+			Group("group_key").Scan(&rows).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to count items: %w", err)
+		}
+
+		response.Counts = make(map[string]int, len(rows))
+		for _, row := range rows {
+			key := models.UncategorizedGroup
+			if row.GroupKey != nil {
+				key = *row.GroupKey
+			}
+			response.Counts[key] += row.Count
+			keys = append(keys, key)
+		}
+	} else {
+		var items []models.Item
+		if err := query.Order("name ASC").Limit(models.MaxGroupedItems + 1).Find(&items).Error; err != nil {
+			return nil, fmt.Errorf("failed to get items: %w", err)
+		}
+		if len(items) > models.MaxGroupedItems {
+			return nil, fmt.Errorf("%w: more than %d items match, narrow the filters or group with counts=true",
+				ErrTooManyToGroup, models.MaxGroupedItems)
+		}
+
+		response.Groups = map[string][]models.Item{}
+		for _, item := range items {
+			key := groupKey(field, &item)
+			if _, ok := response.Groups[key]; !ok {
+				keys = append(keys, key)
+			}
+			response.Groups[key] = append(response.Groups[key], item)
+		}
+	}
+
+	if field == "category" {
+		labels, err := s.categoryLabels(keys)
+		if err != nil {
+			return nil, err
+		}
+		response.Labels = labels
+	}
+	return response, nil
+}
+
+// groupKey returns the group item falls into when grouping by field
+func groupKey(field string, item *models.Item) string {
+	switch field {
+	case "currency":
+		return item.Currency
+	case "price_range":
+		return models.PriceRange(item.Price)
+	default:
+		if item.CategoryID == nil {
+			return models.UncategorizedGroup
+		}
+		return item.CategoryID.String()
+	}
+}
+
+// groupKeyColumn returns the SQL computing the group key of field, a price
+// range being labelled the way models.PriceRange labels it
+func groupKeyColumn(field string) string {
+	switch field {
+	case "currency":
+		return "currency"
+	case "price_range":
+		var sql strings.Builder
+		sql.WriteString("CASE")
+		lower := 0.0
+		for _, upper := range models.PriceRangeBounds {
+			fmt.Fprintf(&sql, " WHEN price < %g THEN '%s'", upper, models.PriceRange(lower))
+			lower = upper
+		}
+		fmt.Fprintf(&sql, " ELSE '%s' END", models.PriceRange(lower))
+		return sql.String()
+	default:
+		return "category_id"
+	}
+}
+
+// categoryLabels maps the category IDs among keys to the names of their categories
+func (s *ItemService) categoryLabels(keys []string) (map[string]string, error) {
+	ids := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key != models.UncategorizedGroup {
+			ids = append(ids, key)
+		}
+	}
+
+	labels := make(map[string]string, len(ids))
+	if len(ids) == 0 {
+		return labels, nil
+	}
+	var categories []models.Category
+	if err := s.db.Where("id IN ?", ids).Find(&categories).Error; err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+	for _, category := range categories {
+		labels[category.ID.String()] = category.Name
+	}
+	return labels, nil
+}
@@ -0,0 +1,166 @@
+package utils
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"inventory-api/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestItemService_SeedDatabase_Concurrent(t *testing.T) {
+	testDB := NewTestDB(t)
+	defer testDB.Close()
+
+	// Every connection to an in-memory SQLite database sees its own database
+	sqlDB, err := testDB.DB.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	service := NewItemServiceWithDB(testDB.DB)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = service.SeedDatabase()
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	var count int64
+	require.NoError(t, testDB.DB.Model(&models.Item{}).Count(&count).Error)
+	assert.Equal(t, int64(10), count)
+}
+
+func TestItemService_CacheEviction(t *testing.T) {
+	testDB := NewTestDB(t)
+	defer testDB.Close()
+
+	const itemCount = 50
+	items := make([]*models.Item, itemCount)
+	for i := range items {
+		items[i] = testDB.CreateTestItem(t, fmt.Sprintf("Item %02d", i), i, 1.00)
+	}
+
+	// Room for only a handful of items
+	cfg := &Config{Cache: CacheConfig{MaxCost: 5 * itemCost(items[0]), NumCounters: 1000}}
+	service := NewItemServiceWithConfig(testDB.DB, cfg)
+	defer service.Close()
+
+	for _, item := range items {
+		service.setCache(item.ID.String(), item)
+		service.cache.Wait()
+	}
+
+	cached := 0
+	for _, item := range items {
+		if service.getFromCache(item.ID.String()) != nil {
+			cached++
+		}
+	}
+	assert.Greater(t, cached, 0)
+	assert.Less(t, cached, itemCount, "items beyond the cache budget are evicted")
+}
+
+func TestItemService_CacheDisabled(t *testing.T) {
+	testDB := NewTestDB(t)
+	defer testDB.Close()
+
+	item := testDB.CreateTestItem(t, "Widget", 10, 5.00)
+	id := item.ID.String()
+
+	service := NewItemServiceWithConfig(testDB.DB, &Config{Cache: CacheConfig{Disabled: true}})
+	defer service.Close()
+	require.Nil(t, service.cache)
+
+	var queries atomic.Int32
+	require.NoError(t, testDB.DB.Callback().Query().Before("gorm:query").Register("test:count", func(*gorm.DB) {
+		queries.Add(1)
+	}))
+
+	for i := 0; i < 3; i++ {
+		got, err := service.GetItem(id)
+		require.NoError(t, err)
+		assert.Equal(t, "Widget", got.Name)
+	}
+	assert.EqualValues(t, 3, queries.Load(), "every read queries the database")
+
+	// Changes made behind the service's back are seen straight away
+	require.NoError(t, testDB.DB.Model(item).UpdateColumn("name", "Renamed").Error)
+	got, err := service.GetItem(id)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", got.Name)
+
+	n, err := service.WarmCache(10)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+func TestItemService_WarmCache(t *testing.T) {
+	testDB := NewTestDB(t)
+	defer testDB.Close()
+
+	stale := testDB.CreateTestItem(t, "Stale", 1, 1.00)
+	recent := testDB.CreateTestItem(t, "Recent", 2, 2.00)
+	latest := testDB.CreateTestItem(t, "Latest", 3, 3.00)
+
+	now := time.Now().UTC()
+	for i, item := range []*models.Item{stale, recent, latest} {
+		updatedAt := now.Add(time.Duration(i-3) * time.Hour)
+		require.NoError(t, testDB.DB.Model(item).UpdateColumn("updated_at", updatedAt).Error)
+	}
+
+	service := NewItemServiceWithDB(testDB.DB)
+	defer service.Close()
+
+	n, err := service.WarmCache(2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	cached := service.getFromCache(latest.ID.String())
+	require.NotNil(t, cached)
+	assert.Equal(t, "Latest", cached.Name)
+
+	cached = service.getFromCache(recent.ID.String())
+	require.NotNil(t, cached)
+	assert.Equal(t, "Recent", cached.Name)
+
+	assert.Nil(t, service.getFromCache(stale.ID.String()))
+}
+
+func TestItemService_GetItems_SortWhitelist(t *testing.T) {
+	testDB := NewTestDB(t)
+	defer testDB.Close()
+	service := NewItemServiceWithDB(testDB.DB)
+	testDB.CreateTestItem(t, "Widget", 1, 1.0)
+
+	t.Run("every sortable field can build a cursor", func(t *testing.T) {
+		for field := range models.SortColumns {
+			assert.Contains(t, sortKeys, field)
+		}
+	})
+
+	t.Run("fields outside the whitelist never reach the query", func(t *testing.T) {
+		for _, field := range []string{"stock;DROP TABLE items", "stock desc, (SELECT 1)", "password", "deleted_at"} {
+			_, err := service.GetItems(nil, nil, &models.SortRequest{SortBy: field}, nil)
+			assert.ErrorIs(t, err, ErrInvalidSort, field)
+		}
+
+		var count int64
+		require.NoError(t, testDB.DB.Model(&models.Item{}).Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+	})
+}
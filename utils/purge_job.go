@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"context"
+	"time"
+)
+
+// StartPurgeJob purges expired soft-deleted items every interval until ctx
+// is cancelled. The returned channel is closed once the job has stopped.
+func StartPurgeJob(ctx context.Context, service *ItemService, interval time.Duration) <-chan struct{} {
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				purged, err := service.PurgeSoftDeletedItems()
+				if err != nil {
+					Error.Printf("Soft-delete purge failed: %v", err)
+					continue
+				}
+				Info.Printf("Purged %d soft-deleted items", purged)
+			}
+		}
+	}()
+
+	return done
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"inventory-api/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func softDeleteTestItem(t *testing.T, testDB *TestDB, name string, deletedAt time.Time) *models.Item {
+	item := testDB.CreateTestItem(t, name, 1, 1.0)
+	require.NoError(t, testDB.DB.Delete(item).Error)
+	require.NoError(t, testDB.DB.Unscoped().Model(item).Update("deleted_at", deletedAt).Error)
+	return item
+}
+
+func countAllItems(t *testing.T, testDB *TestDB) int64 {
+	var count int64
+	require.NoError(t, testDB.DB.Unscoped().Model(&models.Item{}).Count(&count).Error)
+	return count
+}
+
+func TestItemService_PurgeSoftDeletedItems(t *testing.T) {
+	testDB := NewTestDB(t)
+	defer testDB.Close()
+
+	cfg := &Config{Retention: RetentionConfig{SoftDeleteDays: 30}}
+	service := NewItemServiceWithConfig(testDB.DB, cfg)
+
+	testDB.CreateTestItem(t, "Live Item", 1, 1.0)
+	expired := softDeleteTestItem(t, testDB, "Expired Item", time.Now().AddDate(0, 0, -31))
+	softDeleteTestItem(t, testDB, "Recently Deleted Item", time.Now().AddDate(0, 0, -1))
+
+	purged, err := service.PurgeSoftDeletedItems()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+	assert.Equal(t, int64(2), countAllItems(t, testDB))
+
+	var count int64
+	require.NoError(t, testDB.DB.Unscoped().Model(&models.Item{}).Where("id = ?", expired.ID).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestStartPurgeJob(t *testing.T) {
+	testDB := NewTestDB(t)
+	defer testDB.Close()
+
+	// The job and the test share one in-memory database, which is per connection
+	sqlDB, err := testDB.DB.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	service := NewItemServiceWithConfig(testDB.DB, &Config{Retention: RetentionConfig{SoftDeleteDays: 30}})
+	softDeleteTestItem(t, testDB, "Expired Item", time.Now().AddDate(0, 0, -31))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := StartPurgeJob(ctx, service, 10*time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		return countAllItems(t, testDB) == 0
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("purge job did not stop after cancellation")
+	}
+}
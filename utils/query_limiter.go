@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrDatabaseBusy is returned when every query slot and queue position is
+// taken, so the statement is shed rather than left to pile up
+var ErrDatabaseBusy = errors.New("database is busy, retry later")
+
+// queryQueueTimeout bounds how long a queued statement waits for a slot
+var queryQueueTimeout = time.Second
+
+// queryLimiterAcquired marks statements holding a slot, so only they release one
+const queryLimiterAcquired = "query_limiter:acquired"
+
+// QueryLimiter is a GORM plugin bounding how many statements run at once,
+// independently of the connection pool size. Up to queueSize more statements
+// wait for a slot; beyond that, or after waiting too long, statements fail
+// with ErrDatabaseBusy.
+type QueryLimiter struct {
+	slots chan struct{}
+	queue chan struct{}
+}
+
+// NewQueryLimiter creates a limiter running at most maxConcurrent statements
+// with up to queueSize more waiting
+func NewQueryLimiter(maxConcurrent, queueSize int) *QueryLimiter {
+	return &QueryLimiter{
+		slots: make(chan struct{}, maxConcurrent),
+		queue: make(chan struct{}, maxConcurrent+queueSize),
+	}
+}
+
+// Name implements gorm.Plugin
+func (l *QueryLimiter) Name() string {
+	return "query_limiter"
+}
+
+// Initialize implements gorm.Plugin, holding a slot while each statement
+// is executed
+func (l *QueryLimiter) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().Before("gorm:create").Register("query_limiter:acquire", l.acquire),
+		callbacks.Create().After("gorm:create").Register("query_limiter:release", l.release),
+		callbacks.Query().Before("gorm:query").Register("query_limiter:acquire", l.acquire),
+		callbacks.Query().After("gorm:query").Register("query_limiter:release", l.release),
+		callbacks.Update().Before("gorm:update").Register("query_limiter:acquire", l.acquire),
+		callbacks.Update().After("gorm:update").Register("query_limiter:release", l.release),
+		callbacks.Delete().Before("gorm:delete").Register("query_limiter:acquire", l.acquire),
+		callbacks.Delete().After("gorm:delete").Register("query_limiter:release", l.release),
+		callbacks.Row().Before("gorm:row").Register("query_limiter:acquire", l.acquire),
+		callbacks.Row().After("gorm:row").Register("query_limiter:release", l.release),
+		callbacks.Raw().Before("gorm:raw").Register("query_limiter:acquire", l.acquire),
+		callbacks.Raw().After("gorm:raw").Register("query_limiter:release", l.release),
+	)
+}
+
+// acquire takes a slot for the statement, queueing for one when the queue
+// has room, or fails the statement with ErrDatabaseBusy
+func (l *QueryLimiter) acquire(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+
+	select {
+	case l.queue <- struct{}{}:
+	default:
+		db.AddError(ErrDatabaseBusy)
+		return
+	}
+
+	timer := time.NewTimer(queryQueueTimeout)
+	defer timer.Stop()
+
+	select {
+	case l.slots <- struct{}{}:
+		db.InstanceSet(queryLimiterAcquired, true)
+	case <-timer.C:
+		<-l.queue
+		db.AddError(ErrDatabaseBusy)
+	case <-db.Statement.Context.Done():
+		<-l.queue
+		db.AddError(db.Statement.Context.Err())
+	}
+}
+
+// release frees the slot taken by acquire, if any
+func (l *QueryLimiter) release(db *gorm.DB) {
+	if acquired, _ := db.InstanceGet(queryLimiterAcquired); acquired != true {
+		return
+	}
+
+	db.InstanceSet(queryLimiterAcquired, false)
+	<-l.slots
+	<-l.queue
+}
@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"inventory-api/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// blockQueries registers a callback that holds the query slot of any
+// statement tagged with "test:block" until the returned release is called.
+// The started channel receives once the blocked statement holds its slot.
+func blockQueries(t *testing.T, db *gorm.DB) (started <-chan struct{}, release func()) {
+	startedCh := make(chan struct{}, 1)
+	unblock := make(chan struct{})
+	err := db.Callback().Query().After("query_limiter:acquire").Before("gorm:query").
+		Register("test:block", func(db *gorm.DB) {
+			if _, ok := db.Get("test:block"); ok && db.Error == nil {
+				startedCh <- struct{}{}
+				<-unblock
+			}
+		})
+	require.NoError(t, err)
+	return startedCh, func() { close(unblock) }
+}
+
+func TestQueryLimiter(t *testing.T) {
+	t.Run("sheds queries once the queue is full", func(t *testing.T) {
+		testDB := NewTestDB(t)
+		defer testDB.Close()
+		require.NoError(t, testDB.DB.Use(NewQueryLimiter(1, 0)))
+		started, release := blockQueries(t, testDB.DB)
+
+		done := make(chan error, 1)
+		go func() {
+			var items []models.Item
+			done <- testDB.DB.Set("test:block", true).Find(&items).Error
+		}()
+		<-started
+
+		var items []models.Item
+		err := testDB.DB.Find(&items).Error
+		assert.ErrorIs(t, err, ErrDatabaseBusy)
+
+		release()
+		require.NoError(t, <-done)
+		assert.NoError(t, testDB.DB.Find(&items).Error, "the slot is freed after the blocked query finishes")
+	})
+
+	t.Run("queued query runs once a slot frees up", func(t *testing.T) {
+		testDB := NewTestDB(t)
+		defer testDB.Close()
+		require.NoError(t, testDB.DB.Use(NewQueryLimiter(1, 1)))
+		started, release := blockQueries(t, testDB.DB)
+
+		done := make(chan error, 1)
+		go func() {
+			var items []models.Item
+			done <- testDB.DB.Set("test:block", true).Find(&items).Error
+		}()
+		<-started
+
+		queued := make(chan error, 1)
+		go func() {
+			var items []models.Item
+			queued <- testDB.DB.Find(&items).Error
+		}()
+		time.Sleep(50 * time.Millisecond)
+		release()
+
+		require.NoError(t, <-done)
+		assert.NoError(t, <-queued)
+	})
+
+	t.Run("queued query gives up after the queue timeout", func(t *testing.T) {
+		previous := queryQueueTimeout
+		queryQueueTimeout = 10 * time.Millisecond
+		t.Cleanup(func() { queryQueueTimeout = previous })
+
+		testDB := NewTestDB(t)
+		defer testDB.Close()
+		require.NoError(t, testDB.DB.Use(NewQueryLimiter(1, 1)))
+		started, release := blockQueries(t, testDB.DB)
+
+		done := make(chan error, 1)
+		go func() {
+			var items []models.Item
+			done <- testDB.DB.Set("test:block", true).Find(&items).Error
+		}()
+		<-started
+
+		var items []models.Item
+		err := testDB.DB.Find(&items).Error
+		assert.ErrorIs(t, err, ErrDatabaseBusy)
+
+		release()
+		require.NoError(t, <-done)
+	})
+}
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"inventory-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QuerySizeMiddleware rejects requests with 400 Bad Request when the raw
+// query string is longer than maxLength bytes or has more than maxParams
+// parameters, before binding spends any time parsing it. Parameters are
+// counted as written, so a repeated parameter counts every time. A limit of
+// 0 disables that check.
+func QuerySizeMiddleware(maxLength, maxParams int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := c.Request.URL.RawQuery
+
+		var message string
+		if maxLength > 0 && len(query) > maxLength {
+			message = fmt.Sprintf("query string is %d bytes, at most %d are allowed", len(query), maxLength)
+		} else if maxParams > 0 {
+			if n := countQueryParams(query); n > maxParams {
+				message = fmt.Sprintf("query string has %d parameters, at most %d are allowed", n, maxParams)
+			}
+		}
+		if message == "" {
+			c.Next()
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Query string too large",
+			Message: message,
+			Code:    http.StatusBadRequest,
+		})
+	}
+}
+
+// countQueryParams counts the non-empty &-separated parameters of a raw
+// query string without decoding them
+func countQueryParams(query string) int {
+	n := 0
+	for _, param := range strings.Split(query, "&") {
+		if param != "" {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"inventory-api/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuerySizeMiddleware(t *testing.T) {
+	router := SetupTestRouter()
+	router.Use(QuerySizeMiddleware(100, 5))
+	router.GET("/items", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		name     string
+		query    string
+		expected int
+	}{
+		{name: "no query", query: "", expected: http.StatusOK},
+		{name: "within limits", query: "name=widget&min_stock=5&limit=10", expected: http.StatusOK},
+		{name: "at the parameter limit", query: "a=1&b=2&c=3&d=4&e=5", expected: http.StatusOK},
+		{name: "empty parameters are not counted", query: "a=1&&b=2&&&c=3&d=4&e=5&", expected: http.StatusOK},
+		{name: "too many parameters", query: "a=1&b=2&c=3&d=4&e=5&f=6", expected: http.StatusBadRequest},
+		{name: "duplicate parameters", query: strings.Repeat("name=x&", 1000), expected: http.StatusBadRequest},
+		{name: "oversized query string", query: "name=" + strings.Repeat("x", 100), expected: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/items?"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expected, w.Code)
+			if tt.expected != http.StatusBadRequest {
+				return
+			}
+			var response models.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, "Query string too large", response.Error)
+			assert.Equal(t, http.StatusBadRequest, response.Code)
+		})
+	}
+
+	t.Run("zero limits disable the checks", func(t *testing.T) {
+		router := SetupTestRouter()
+		router.Use(QuerySizeMiddleware(0, 0))
+		router.GET("/items", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		req := httptest.NewRequest(http.MethodGet, "/items?"+strings.Repeat("name=x&", 1000), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
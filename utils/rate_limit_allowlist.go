@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Allowlist matches trusted clients that bypass rate limiting, either by
+// client IP or by the API key sent in the X-API-Key header
+type Allowlist struct {
+	networks []*net.IPNet
+	apiKeys  map[string]bool
+}
+
+// NewAllowlist parses a comma-separated list of IPs, CIDRs and API keys.
+// Entries that are neither an IP nor contain a slash are treated as API keys.
+func NewAllowlist(entries string) (*Allowlist, error) {
+	allowlist := &Allowlist{apiKeys: make(map[string]bool)}
+
+	for _, entry := range strings.Split(entries, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if strings.Contains(entry, "/") {
+			_, network, err := net.ParseCIDR(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid allowlist CIDR %q: %w", entry, err)
+			}
+			allowlist.networks = append(allowlist.networks, network)
+			continue
+		}
+
+		if ip := net.ParseIP(entry); ip != nil {
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 8 * net.IPv4len
+			}
+			allowlist.networks = append(allowlist.networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		allowlist.apiKeys[entry] = true
+	}
+
+	return allowlist, nil
+}
+
+// Allows reports whether the request comes from a trusted client
+func (a *Allowlist) Allows(c *gin.Context) bool {
+	if a == nil {
+		return false
+	}
+
+	if key := c.GetHeader(APIKeyHeader); key != "" && a.apiKeys[key] {
+		return true
+	}
+
+	ip := net.ParseIP(c.ClientIP())
+	if ip == nil {
+		return false
+	}
+	for _, network := range a.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAllowlistedRouter(t *testing.T, entries string) *gin.Engine {
+	allowlist, err := NewAllowlist(entries)
+	require.NoError(t, err)
+
+	router := SetupTestRouter()
+	router.Use(RateLimitMiddleware(NewRateLimiter(1, 1), NewKeyFunc(RateLimitKeyIP, ""), allowlist))
+	router.GET("/ping", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestRateLimitMiddleware_Allowlist(t *testing.T) {
+	tests := []struct {
+		name       string
+		entries    string
+		remoteAddr string
+		headers    map[string]string
+		expected   int
+	}{
+		{
+			name:       "allowlisted IP bypasses limit",
+			entries:    "192.0.2.1",
+			remoteAddr: "192.0.2.1:1234",
+			expected:   http.StatusOK,
+		},
+		{
+			name:       "IP inside allowlisted CIDR bypasses limit",
+			entries:    "10.0.0.0/8, 192.0.2.0/24",
+			remoteAddr: "192.0.2.77:1234",
+			expected:   http.StatusOK,
+		},
+		{
+			name:       "allowlisted API key bypasses limit",
+			entries:    "cron-job-key",
+			remoteAddr: "192.0.2.1:1234",
+			headers:    map[string]string{APIKeyHeader: "cron-job-key"},
+			expected:   http.StatusOK,
+		},
+		{
+			name:       "non-listed IP is still limited",
+			entries:    "10.0.0.0/8",
+			remoteAddr: "192.0.2.1:1234",
+			expected:   http.StatusTooManyRequests,
+		},
+		{
+			name:       "non-listed API key is still limited",
+			entries:    "cron-job-key",
+			remoteAddr: "192.0.2.1:1234",
+			headers:    map[string]string{APIKeyHeader: "other-key"},
+			expected:   http.StatusTooManyRequests,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newAllowlistedRouter(t, tt.entries)
+
+			assert.Equal(t, http.StatusOK, doRateLimitedRequest(router, tt.remoteAddr, tt.headers))
+			assert.Equal(t, tt.expected, doRateLimitedRequest(router, tt.remoteAddr, tt.headers))
+		})
+	}
+}
+
+func TestNewAllowlist_InvalidCIDR(t *testing.T) {
+	_, err := NewAllowlist("10.0.0.0/33")
+	assert.Error(t, err)
+}
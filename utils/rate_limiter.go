@@ -1,14 +1,40 @@
 package utils
 
 import (
+	"fmt"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	"golang.org/x/time/rate"
 )
 
+// Rate limit key strategies selectable via RATE_LIMIT_KEY
+const (
+	RateLimitKeyIP     = "ip"
+	RateLimitKeyAPIKey = "apikey"
+)
+
+// Rate limit algorithms selectable via RATE_LIMIT_ALGO
+const (
+	RateLimitAlgoTokenBucket = "token_bucket"
+	RateLimitAlgoFixedWindow = "fixed_window"
+)
+
+// APIKeyHeader is the header used to identify API key clients
+const APIKeyHeader = "X-API-Key"
+
+// KeyFunc extracts the rate limit key from a request
+type KeyFunc func(c *gin.Context) string
+
+// Limiter decides whether a request identified by key may proceed
+type Limiter interface {
+	Allow(key string) bool
+}
+
 type RateLimiter struct {
 	limiters map[string]*rate.Limiter
 	mu       sync.RWMutex
@@ -42,13 +68,73 @@ func (rl *RateLimiter) Allow(key string) bool {
 	return limiter.Allow()
 }
 
-func RateLimitMiddleware(requestsPerSecond int, burst int) gin.HandlerFunc {
-	limiter := NewRateLimiter(requestsPerSecond, burst)
+// NewKeyFunc returns the key function for the given strategy. The apikey
+// strategy limits each of the comma-separated apiKeys on its own; requests
+// with any other X-API-Key value, or none, are keyed on the client IP, so
+// made-up keys cannot dodge the limit. Unknown strategies key on the client
+// IP, which honours X-Forwarded-For only from trusted proxies.
+func NewKeyFunc(strategy string, apiKeys string) KeyFunc {
+	switch strategy {
+	case RateLimitKeyAPIKey:
+		known := make(map[string]bool)
+		for _, key := range strings.Split(apiKeys, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				known[key] = true
+			}
+		}
+		return func(c *gin.Context) string {
+			if key := c.GetHeader(APIKeyHeader); known[key] {
+				return "apikey:" + key
+			}
+			return c.ClientIP()
+		}
+	default:
+		return func(c *gin.Context) string {
+			return c.ClientIP()
+		}
+	}
+}
+
+// NewLimiter returns a limiter using the configured algorithm. It is Redis
+// backed when REDIS_URL is configured, so the limit is shared across
+// replicas, and in-memory otherwise.
+func NewLimiter(cfg *Config) (Limiter, error) {
+	fixedWindow := cfg.RateLimit.Algorithm == RateLimitAlgoFixedWindow
+
+	if cfg.Redis.URL == "" {
+		if fixedWindow {
+			return NewFixedWindowLimiter(cfg.RateLimit.Requests, cfg.RateLimit.Window), nil
+		}
+		return NewRateLimiter(cfg.RateLimit.Requests, cfg.RateLimit.Burst), nil
+	}
+
+	opts, err := redis.ParseURL(cfg.Redis.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis url: %w", err)
+	}
+
+	if fixedWindow {
+		return NewRedisFixedWindowLimiter(redis.NewClient(opts), cfg.RateLimit.Requests, cfg.RateLimit.Window), nil
+	}
+	return NewRedisRateLimiter(redis.NewClient(opts), cfg.RateLimit.Requests, cfg.RateLimit.Burst), nil
+}
+
+// RateLimitMiddleware limits requests per key. Clients matched by the
+// allowlist are never limited; a nil allowlist matches no one.
+func RateLimitMiddleware(limiter Limiter, keyFunc KeyFunc, allowlist *Allowlist) gin.HandlerFunc {
+	if keyFunc == nil {
+		keyFunc = NewKeyFunc(RateLimitKeyIP, "")
+	}
 
 	return func(c *gin.Context) {
-		clientIP := c.ClientIP()
+		if allowlist.Allows(c) {
+			c.Next()
+			return
+		}
+
+		key := keyFunc(c)
 
-		if !limiter.Allow(clientIP) {
+		if !limiter.Allow(key) {
 			c.JSON(http.StatusTooManyRequests, gin.H{
 				"error":   "Rate limit exceeded",
 				"message": "Too many requests. Please try again later.",
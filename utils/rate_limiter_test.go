@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRateLimitedRouter(strategy string) *gin.Engine {
+	router := SetupTestRouter()
+	router.Use(RateLimitMiddleware(NewRateLimiter(1, 1), NewKeyFunc(strategy, "tenant-a, tenant-b"), nil))
+	router.GET("/ping", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func doRateLimitedRequest(router *gin.Engine, remoteAddr string, headers map[string]string) int {
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.RemoteAddr = remoteAddr
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestRateLimitMiddleware_KeyStrategies(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		first    map[string]string
+		second   map[string]string
+		expected int
+	}{
+		{
+			name:     "ip strategy limits same client IP",
+			strategy: RateLimitKeyIP,
+			expected: http.StatusTooManyRequests,
+		},
+		{
+			name:     "ip strategy ignores api key",
+			strategy: RateLimitKeyIP,
+			first:    map[string]string{APIKeyHeader: "tenant-a"},
+			second:   map[string]string{APIKeyHeader: "tenant-b"},
+			expected: http.StatusTooManyRequests,
+		},
+		{
+			name:     "apikey strategy separates tenants",
+			strategy: RateLimitKeyAPIKey,
+			first:    map[string]string{APIKeyHeader: "tenant-a"},
+			second:   map[string]string{APIKeyHeader: "tenant-b"},
+			expected: http.StatusOK,
+		},
+		{
+			name:     "apikey strategy limits same key",
+			strategy: RateLimitKeyAPIKey,
+			first:    map[string]string{APIKeyHeader: "tenant-a"},
+			second:   map[string]string{APIKeyHeader: "tenant-a"},
+			expected: http.StatusTooManyRequests,
+		},
+		{
+			name:     "apikey strategy falls back to IP",
+			strategy: RateLimitKeyAPIKey,
+			expected: http.StatusTooManyRequests,
+		},
+		{
+			name:     "apikey strategy limits unknown keys by IP",
+			strategy: RateLimitKeyAPIKey,
+			first:    map[string]string{APIKeyHeader: "made-up-1"},
+			second:   map[string]string{APIKeyHeader: "made-up-2"},
+			expected: http.StatusTooManyRequests,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newRateLimitedRouter(tt.strategy)
+
+			assert.Equal(t, http.StatusOK, doRateLimitedRequest(router, "192.0.2.1:1234", tt.first))
+			assert.Equal(t, tt.expected, doRateLimitedRequest(router, "192.0.2.1:1234", tt.second))
+		})
+	}
+}
+
+func TestRateLimitMiddleware_SpoofedHeaders(t *testing.T) {
+	for _, strategy := range []string{RateLimitKeyIP, RateLimitKeyAPIKey, "header"} {
+		t.Run(strategy, func(t *testing.T) {
+			router := newRateLimitedRouter(strategy)
+			require.NoError(t, SetTrustedProxies(router, "10.0.0.0/8"))
+
+			// An untrusted peer making up a new client and key each time
+			// is still limited as itself
+			codes := make([]int, 3)
+			for i := range codes {
+				codes[i] = doRateLimitedRequest(router, "192.0.2.1:1234", map[string]string{
+					"X-Forwarded-For": fmt.Sprintf("203.0.113.%d", i+1),
+					APIKeyHeader:      fmt.Sprintf("made-up-%d", i+1),
+				})
+			}
+			assert.Equal(t, []int{http.StatusOK, http.StatusTooManyRequests, http.StatusTooManyRequests}, codes)
+
+			// Behind a trusted proxy the forwarded clients are told apart
+			assert.Equal(t, http.StatusOK, doRateLimitedRequest(router, "10.0.0.1:1234", map[string]string{"X-Forwarded-For": "203.0.113.10"}))
+			assert.Equal(t, http.StatusOK, doRateLimitedRequest(router, "10.0.0.1:1234", map[string]string{"X-Forwarded-For": "203.0.113.11"}))
+		})
+	}
+}
+
+func TestNewKeyFunc_UnknownStrategyUsesIP(t *testing.T) {
+	router := newRateLimitedRouter("unknown")
+
+	assert.Equal(t, http.StatusOK, doRateLimitedRequest(router, "192.0.2.1:1234", nil))
+	assert.Equal(t, http.StatusOK, doRateLimitedRequest(router, "192.0.2.2:1234", nil))
+	assert.Equal(t, http.StatusTooManyRequests, doRateLimitedRequest(router, "192.0.2.1:1234", nil))
+}
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+
+	"inventory-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrReadOnly is returned for writes made while READ_ONLY is set
+var ErrReadOnly = errors.New("service is read-only")
+
+// ReadOnlyMiddleware answers 503 Service Unavailable without calling the
+// handler when readOnly is set. Attach it to routes that write, so reads keep
+// working during maintenance windows such as a database migration.
+func ReadOnlyMiddleware(readOnly bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if readOnly {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, models.ErrorResponse{
+				Error:   "Service unavailable",
+				Message: ErrReadOnly.Error(),
+				Code:    http.StatusServiceUnavailable,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"net/http"
+	"runtime/debug"
+
+	"inventory-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries a client supplied identifier for a request
+const RequestIDHeader = "X-Request-ID"
+
+// RecoveryMiddleware recovers from panics in later handlers, logs the panic
+// with its stack trace and responds with a JSON ErrorResponse, so clients
+// get the same error shape as for any other failure
+func RecoveryMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				requestID := c.GetHeader(RequestIDHeader)
+				Error.Printf("Recovered from panic on %s %s (request id %q): %v\n%s",
+					c.Request.Method, c.Request.URL.Path, requestID, recovered, debug.Stack())
+
+				c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponse{
+					Error:     "Internal server error",
+					Code:      http.StatusInternalServerError,
+					RequestID: requestID,
+				})
+			}
+		}()
+
+		c.Next()
+	}
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenBucketScript refills the bucket for the elapsed time and takes a
+// token if one is available. It returns 1 when the request is allowed.
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local ttl = tonumber(ARGV[4])
+
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "timestamp")
+local tokens = tonumber(bucket[1])
+local timestamp = tonumber(bucket[2])
+if tokens == nil or timestamp == nil then
+	tokens = burst
+	timestamp = now
+end
+
+local elapsed = math.max(0, now - timestamp)
+tokens = math.min(burst, tokens + elapsed * rate)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "timestamp", tostring(now))
+redis.call("EXPIRE", KEYS[1], ttl)
+
+return allowed
+`)
+
+// RedisRateLimiter is a token bucket limiter whose state lives in Redis,
+// so every replica shares the same budget per key
+type RedisRateLimiter struct {
+	client  *redis.Client
+	rate    float64
+	burst   int
+	ttl     int
+	timeout time.Duration
+}
+
+func NewRedisRateLimiter(client *redis.Client, requestsPerSecond int, burst int) *RedisRateLimiter {
+	// Keep idle buckets around long enough to refill completely
+	ttl := 1
+	if requestsPerSecond > 0 {
+		ttl = int(math.Ceil(float64(burst)/float64(requestsPerSecond))) + 1
+	}
+
+	return &RedisRateLimiter{
+		client:  client,
+		rate:    float64(requestsPerSecond),
+		burst:   burst,
+		ttl:     ttl,
+		timeout: 500 * time.Millisecond,
+	}
+}
+
+// Allow takes a token for key. Redis failures fail open so that an
+// unavailable Redis does not take the API down with it.
+func (rl *RedisRateLimiter) Allow(key string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), rl.timeout)
+	defer cancel()
+
+	now := float64(time.Now().UnixMicro()) / 1e6
+	allowed, err := tokenBucketScript.Run(ctx, rl.client, []string{"ratelimit:" + key},
+		strconv.FormatFloat(rl.rate, 'f', -1, 64),
+		rl.burst,
+		strconv.FormatFloat(now, 'f', 6, 64),
+		rl.ttl,
+	).Int()
+	if err != nil {
+		Error.Printf("Redis rate limiter failed, allowing request: %v", err)
+		return true
+	}
+
+	return allowed == 1
+}
@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedisClient(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() {
+		client.Close()
+	})
+	return server, client
+}
+
+func TestRedisRateLimiter_Allow(t *testing.T) {
+	_, client := newTestRedisClient(t)
+	limiter := NewRedisRateLimiter(client, 1, 2)
+
+	assert.True(t, limiter.Allow("client-a"))
+	assert.True(t, limiter.Allow("client-a"))
+	assert.False(t, limiter.Allow("client-a"))
+
+	// Other keys have their own bucket
+	assert.True(t, limiter.Allow("client-b"))
+}
+
+func TestRedisRateLimiter_SharedAcrossReplicas(t *testing.T) {
+	_, client := newTestRedisClient(t)
+	replicaA := NewRedisRateLimiter(client, 1, 2)
+	replicaB := NewRedisRateLimiter(client, 1, 2)
+
+	assert.True(t, replicaA.Allow("client-a"))
+	assert.True(t, replicaB.Allow("client-a"))
+	assert.False(t, replicaA.Allow("client-a"))
+	assert.False(t, replicaB.Allow("client-a"))
+}
+
+func TestRedisRateLimiter_FailsOpen(t *testing.T) {
+	server, client := newTestRedisClient(t)
+	limiter := NewRedisRateLimiter(client, 1, 1)
+	server.Close()
+
+	assert.True(t, limiter.Allow("client-a"))
+	assert.True(t, limiter.Allow("client-a"))
+}
+
+func TestRateLimitMiddleware_RedisBackend(t *testing.T) {
+	_, client := newTestRedisClient(t)
+
+	router := SetupTestRouter()
+	router.Use(RateLimitMiddleware(NewRedisRateLimiter(client, 1, 1), NewKeyFunc(RateLimitKeyIP, ""), nil))
+	router.GET("/ping", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	assert.Equal(t, http.StatusOK, doRateLimitedRequest(router, "192.0.2.1:1234", nil))
+	assert.Equal(t, http.StatusTooManyRequests, doRateLimitedRequest(router, "192.0.2.1:1234", nil))
+}
+
+func TestNewLimiter(t *testing.T) {
+	server, _ := newTestRedisClient(t)
+
+	cfg := &Config{RateLimit: RateLimitConfig{Requests: 1, Burst: 5}}
+	limiter, err := NewLimiter(cfg)
+	require.NoError(t, err)
+	assert.IsType(t, &RateLimiter{}, limiter)
+
+	cfg.Redis.URL = "redis://" + server.Addr()
+	limiter, err = NewLimiter(cfg)
+	require.NoError(t, err)
+	assert.IsType(t, &RedisRateLimiter{}, limiter)
+
+	cfg.Redis.URL = "not-a-url"
+	_, err = NewLimiter(cfg)
+	assert.Error(t, err)
+}
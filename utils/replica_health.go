@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+// Replica health states, as reported by ReplicaHealth.Status
+const (
+	ReplicaHealthy  = "ok"
+	ReplicaDegraded = "degraded"
+)
+
+// replicaLagQuery asks the replica how far its replay trails the primary,
+// in seconds. A replica that has replayed everything it received is not
+// lagging, however long ago the primary last wrote.
+var replicaLagQuery = `SELECT COALESCE(CASE
+	WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+	ELSE EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp())
+END, 0)`
+
+// maxReplicaLag is the replication lag beyond which the replica is reported
+// as degraded; 0 never marks it degraded
+var maxReplicaLag = 30 * time.Second
+
+// ReplicaHealth reports the replication lag of the read replica
+type ReplicaHealth struct {
+	Status        string  `json:"status" example:"ok"`
+	LagSeconds    float64 `json:"lag_seconds" example:"0.4"`
+	MaxLagSeconds float64 `json:"max_lag_seconds,omitempty" example:"30"`
+	Error         string  `json:"error,omitempty"`
+}
+
+// ReplicaStatus checks the replication lag of the read replica, giving up
+// once the configured health timeout is exceeded. It returns nil when no
+// replica is configured.
+func ReplicaStatus() *ReplicaHealth {
+	ctx, cancel := context.WithTimeout(context.Background(), healthTimeout)
+	defer cancel()
+
+	return ReplicaStatusContext(ctx)
+}
+
+// ReplicaStatusContext checks the replication lag of the read replica within
+// ctx. The replica is degraded when its lag exceeds the configured maximum
+// or the lag cannot be read.
+func ReplicaStatusContext(ctx context.Context) *ReplicaHealth {
+	if DB == nil {
+		return nil
+	}
+	if _, ok := DB.Plugins[(&dbresolver.DBResolver{}).Name()]; !ok {
+		return nil
+	}
+
+	health := &ReplicaHealth{Status: ReplicaHealthy, MaxLagSeconds: maxReplicaLag.Seconds()}
+
+	var lag float64
+	if err := DB.WithContext(ctx).Clauses(dbresolver.Read).Raw(replicaLagQuery).Scan(&lag).Error; err != nil {
+		health.Status = ReplicaDegraded
+		health.Error = fmt.Sprintf("failed to read replication lag: %v", err)
+		return health
+	}
+
+	health.LagSeconds = lag
+	if maxReplicaLag > 0 && lag > maxReplicaLag.Seconds() {
+		health.Status = ReplicaDegraded
+	}
+	return health
+}
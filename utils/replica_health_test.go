@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+)
+
+// stubReplicaLag routes reads of the test database to a SQLite replica and
+// answers the lag query with lagQuery
+func stubReplicaLag(t *testing.T, testDB *TestDB, lagQuery string, maxLag time.Duration) {
+	replicaPath := filepath.Join(t.TempDir(), "replica.db")
+	require.NoError(t, UseReplica(testDB.DB, sqlite.Open(replicaPath)))
+
+	previousQuery, previousMax := replicaLagQuery, maxReplicaLag
+	replicaLagQuery, maxReplicaLag = lagQuery, maxLag
+	t.Cleanup(func() { replicaLagQuery, maxReplicaLag = previousQuery, previousMax })
+}
+
+func TestReplicaStatus(t *testing.T) {
+	t.Run("no replica", func(t *testing.T) {
+		useTestDB(t)
+		assert.Nil(t, ReplicaStatus())
+	})
+
+	t.Run("within the maximum lag", func(t *testing.T) {
+		testDB := useTestDB(t)
+		stubReplicaLag(t, testDB, "SELECT 1.5", 30*time.Second)
+
+		health := ReplicaStatus()
+		require.NotNil(t, health)
+		assert.Equal(t, ReplicaHealthy, health.Status)
+		assert.Equal(t, 1.5, health.LagSeconds)
+		assert.Equal(t, 30.0, health.MaxLagSeconds)
+		assert.Empty(t, health.Error)
+	})
+
+	t.Run("lagging", func(t *testing.T) {
+		testDB := useTestDB(t)
+		stubReplicaLag(t, testDB, "SELECT 45", 30*time.Second)
+
+		health := ReplicaStatus()
+		require.NotNil(t, health)
+		assert.Equal(t, ReplicaDegraded, health.Status)
+		assert.Equal(t, 45.0, health.LagSeconds)
+	})
+
+	t.Run("no maximum only reports the lag", func(t *testing.T) {
+		testDB := useTestDB(t)
+		stubReplicaLag(t, testDB, "SELECT 3600", 0)
+
+		health := ReplicaStatus()
+		require.NotNil(t, health)
+		assert.Equal(t, ReplicaHealthy, health.Status)
+		assert.Equal(t, 3600.0, health.LagSeconds)
+	})
+
+	t.Run("lag query fails", func(t *testing.T) {
+		testDB := useTestDB(t)
+		stubReplicaLag(t, testDB, "SELECT pg_last_xact_replay_timestamp()", 30*time.Second)
+
+		health := ReplicaStatus()
+		require.NotNil(t, health)
+		assert.Equal(t, ReplicaDegraded, health.Status)
+		assert.Contains(t, health.Error, "failed to read replication lag")
+	})
+}
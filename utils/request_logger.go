@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestLogEntry is one line of the access log
+type requestLogEntry struct {
+	Time      string  `json:"time"`
+	Method    string  `json:"method"`
+	Path      string  `json:"path"`
+	Query     string  `json:"query,omitempty"`
+	Status    int     `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	ClientIP  string  `json:"client_ip"`
+	Size      int     `json:"size"`
+	RequestID string  `json:"request_id,omitempty"`
+	Error     string  `json:"error,omitempty"`
+	// SampleRate is set on sampled entries, so each one can be counted as
+	// SampleRate requests
+	SampleRate int `json:"sample_rate,omitempty"`
+}
+
+// RequestLogMiddleware writes one JSON line per request to out, replacing
+// gin.Logger. Successful GET requests are sampled: only every sampleRate-th
+// one is logged. Other methods and every response outside 2xx are always
+// logged. A sampleRate of 1 or less logs every request.
+func RequestLogMiddleware(out io.Writer, sampleRate int) gin.HandlerFunc {
+	var (
+		mu        sync.Mutex
+		successes atomic.Uint64
+	)
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		entry := requestLogEntry{
+			Time:      start.UTC().Format(time.RFC3339Nano),
+			Method:    c.Request.Method,
+			Path:      c.Request.URL.Path,
+			Query:     c.Request.URL.RawQuery,
+			Status:    status,
+			LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+			ClientIP:  c.ClientIP(),
+			Size:      c.Writer.Size(),
+			RequestID: c.GetHeader(RequestIDHeader),
+			Error:     c.Errors.ByType(gin.ErrorTypePrivate).String(),
+		}
+
+		if sampleRate > 1 && c.Request.Method == http.MethodGet && status >= 200 && status < 300 {
+			if (successes.Add(1)-1)%uint64(sampleRate) != 0 {
+				return
+			}
+			entry.SampleRate = sampleRate
+		}
+
+		line, err := json.Marshal(entry)
+		if err != nil {
+			Error.Printf("Failed to encode request log entry: %v", err)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		_, _ = out.Write(append(line, '\n'))
+	}
+}
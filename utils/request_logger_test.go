@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLogMiddleware(t *testing.T) {
+	setup := func(sampleRate int) (*gin.Engine, *bytes.Buffer) {
+		var out bytes.Buffer
+		router := SetupTestRouter()
+		router.Use(RequestLogMiddleware(&out, sampleRate))
+		router.GET("/inventory", func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"data": []string{}})
+		})
+		router.GET("/inventory/missing", func(c *gin.Context) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		})
+		router.GET("/inventory/broken", func(c *gin.Context) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed"})
+		})
+		router.POST("/inventory", func(c *gin.Context) {
+			c.JSON(http.StatusCreated, gin.H{})
+		})
+		return router, &out
+	}
+	serve := func(router *gin.Engine, method, path string) {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, path, nil))
+	}
+	entries := func(t *testing.T, out *bytes.Buffer) []requestLogEntry {
+		var entries []requestLogEntry
+		for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			var entry requestLogEntry
+			require.NoError(t, json.Unmarshal([]byte(line), &entry), line)
+			entries = append(entries, entry)
+		}
+		return entries
+	}
+
+	t.Run("samples successful GET requests", func(t *testing.T) {
+		router, out := setup(5)
+		for i := 0; i < 20; i++ {
+			serve(router, http.MethodGet, "/inventory?limit=10")
+		}
+
+		logged := entries(t, out)
+		require.Len(t, logged, 4)
+		for _, entry := range logged {
+			assert.Equal(t, http.MethodGet, entry.Method)
+			assert.Equal(t, "/inventory", entry.Path)
+			assert.Equal(t, "limit=10", entry.Query)
+			assert.Equal(t, http.StatusOK, entry.Status)
+			assert.Equal(t, 5, entry.SampleRate)
+		}
+	})
+
+	t.Run("always logs error responses", func(t *testing.T) {
+		router, out := setup(100)
+		for i := 0; i < 10; i++ {
+			serve(router, http.MethodGet, "/inventory/missing")
+			serve(router, http.MethodGet, "/inventory/broken")
+		}
+
+		logged := entries(t, out)
+		require.Len(t, logged, 20)
+		for i, entry := range logged {
+			if i%2 == 0 {
+				assert.Equal(t, http.StatusNotFound, entry.Status)
+			} else {
+				assert.Equal(t, http.StatusInternalServerError, entry.Status)
+			}
+			assert.Zero(t, entry.SampleRate, "unsampled entries carry no sample rate")
+		}
+	})
+
+	t.Run("always logs other methods", func(t *testing.T) {
+		router, out := setup(100)
+		for i := 0; i < 3; i++ {
+			serve(router, http.MethodPost, "/inventory")
+		}
+
+		logged := entries(t, out)
+		require.Len(t, logged, 3)
+		assert.Equal(t, http.StatusCreated, logged[0].Status)
+	})
+
+	t.Run("a rate of 1 logs every request", func(t *testing.T) {
+		router, out := setup(1)
+		for i := 0; i < 3; i++ {
+			serve(router, http.MethodGet, "/inventory")
+		}
+
+		logged := entries(t, out)
+		require.Len(t, logged, 3)
+		assert.Zero(t, logged[0].SampleRate)
+	})
+
+	t.Run("records the request id", func(t *testing.T) {
+		router, out := setup(1)
+		req := httptest.NewRequest(http.MethodGet, "/inventory", nil)
+		req.Header.Set(RequestIDHeader, "req-42")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		logged := entries(t, out)
+		require.Len(t, logged, 1)
+		assert.Equal(t, "req-42", logged[0].RequestID)
+	})
+}
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"strings"
+	"time"
+)
+
+// Defaults for retrying transient database errors
+const (
+	DefaultRetryAttempts = 2
+	DefaultRetryBackoff  = 50 * time.Millisecond
+)
+
+// sqlState returns the PostgreSQL SQLSTATE code carried by err, or "" when
+// err did not come from the server
+func sqlState(err error) string {
+	var pgErr interface{ SQLState() string }
+	if errors.As(err, &pgErr) {
+		return pgErr.SQLState()
+	}
+	return ""
+}
+
+// isAbortedTransaction reports whether PostgreSQL rolled back the
+// transaction because of a serialization failure or a deadlock. Nothing it
+// wrote was committed, so running it again is safe even for writes.
+func isAbortedTransaction(err error) bool {
+	switch sqlState(err) {
+	case "40001", "40P01":
+		return true
+	default:
+		return false
+	}
+}
+
+// isTransientError reports whether err is expected to go away when the
+// statement is simply tried again: an aborted transaction, or a connection
+// that broke before or during the statement. A write that loses its
+// connection may or may not have been committed, so only idempotent
+// operations treat connection errors as transient.
+func isTransientError(err error, idempotent bool) bool {
+	if err == nil {
+		return false
+	}
+	if isAbortedTransaction(err) {
+		return true
+	}
+	if !idempotent {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.As(err, &netErr) {
+		return true
+	}
+	// SQLSTATE class 08 is a connection exception
+	return strings.HasPrefix(sqlState(err), "08")
+}
+
+// withRetry runs fn, running it again up to the configured number of times
+// while it fails with a transient error. The wait doubles after each
+// attempt, starting at the configured backoff. Other errors are returned
+// straight away.
+func (s *ItemService) withRetry(idempotent bool, fn func() error) error {
+	err := fn()
+	for attempt := 0; attempt < s.retryAttempts && isTransientError(err, idempotent); attempt++ {
+		Info.Printf("Retrying after transient database error (attempt %d of %d): %v", attempt+1, s.retryAttempts, err)
+		time.Sleep(s.retryBackoff << attempt)
+		err = fn()
+	}
+	return err
+}
@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"inventory-api/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// pgError is a stand-in for a PostgreSQL server error with a SQLSTATE code
+type pgError string
+
+func (e pgError) Error() string    { return "pq: error " + string(e) }
+func (e pgError) SQLState() string { return string(e) }
+
+// failStatements registers a callback failing the next *failures creates
+// and updates with err, and counting the statements that reach it
+func failStatements(t *testing.T, db *gorm.DB, err error, failures *int) *int {
+	reached := 0
+	fail := func(db *gorm.DB) {
+		if db.Error != nil {
+			return
+		}
+		reached++
+		if *failures > 0 {
+			*failures--
+			db.AddError(err)
+		}
+	}
+	require.NoError(t, db.Callback().Create().Before("gorm:create").Register("test:fail", fail))
+	require.NoError(t, db.Callback().Update().Before("gorm:update").Register("test:fail", fail))
+	return &reached
+}
+
+func TestIsTransientError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		idempotent bool
+		want       bool
+	}{
+		{"nil", nil, true, false},
+		{"serialization failure", pgError("40001"), false, true},
+		{"deadlock", pgError("40P01"), false, true},
+		{"unique violation", pgError("23505"), true, false},
+		{"connection exception on read", pgError("08006"), true, true},
+		{"connection exception on write", pgError("08006"), false, false},
+		{"bad connection on read", driver.ErrBadConn, true, true},
+		{"bad connection on write", driver.ErrBadConn, false, false},
+		{"not found", gorm.ErrRecordNotFound, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isTransientError(tt.err, tt.idempotent))
+		})
+	}
+}
+
+func TestItemService_Retry(t *testing.T) {
+	t.Run("create succeeds after a serialization failure", func(t *testing.T) {
+		testDB := NewTestDB(t)
+		defer testDB.Close()
+		service := NewItemServiceWithDB(testDB.DB)
+		service.retryBackoff = 0
+		failures := 1
+		failStatements(t, testDB.DB, pgError("40001"), &failures)
+
+		item, err := service.CreateItem(&models.CreateItemRequest{Name: "Retried", Stock: 5, Price: 1.5})
+		require.NoError(t, err)
+		assert.Zero(t, failures)
+
+		var count int64
+		testDB.DB.Model(&models.Item{}).Where("name = ?", "Retried").Count(&count)
+		assert.Equal(t, int64(1), count)
+		assert.NotZero(t, item.ItemNumber)
+	})
+
+	t.Run("update succeeds after a deadlock", func(t *testing.T) {
+		testDB := NewTestDB(t)
+		defer testDB.Close()
+		service := NewItemServiceWithDB(testDB.DB)
+		service.retryBackoff = 0
+		existing := testDB.CreateTestItem(t, "Widget", 5, 1.5)
+		failures := 1
+		failStatements(t, testDB.DB, pgError("40P01"), &failures)
+
+		stock := 7
+		item, err := service.UpdateItem(existing.ID.String(), &models.UpdateItemRequest{Stock: &stock})
+		require.NoError(t, err)
+		assert.Equal(t, 7, item.Stock)
+	})
+
+	t.Run("gives up after the configured attempts", func(t *testing.T) {
+		testDB := NewTestDB(t)
+		defer testDB.Close()
+		service := NewItemServiceWithDB(testDB.DB)
+		service.retryBackoff = 0
+		failures := 10
+		reached := failStatements(t, testDB.DB, pgError("40001"), &failures)
+
+		_, err := service.CreateItem(&models.CreateItemRequest{Name: "Never", Stock: 5, Price: 1.5})
+		assert.Error(t, err)
+		assert.Equal(t, 1+DefaultRetryAttempts, *reached)
+	})
+
+	t.Run("writes are not retried after a broken connection", func(t *testing.T) {
+		testDB := NewTestDB(t)
+		defer testDB.Close()
+		service := NewItemServiceWithDB(testDB.DB)
+		service.retryBackoff = 0
+		failures := 1
+		reached := failStatements(t, testDB.DB, driver.ErrBadConn, &failures)
+
+		_, err := service.CreateItem(&models.CreateItemRequest{Name: "Once", Stock: 5, Price: 1.5})
+		assert.True(t, errors.Is(err, driver.ErrBadConn))
+		assert.Equal(t, 1, *reached)
+	})
+
+	t.Run("zero attempts disables retries", func(t *testing.T) {
+		testDB := NewTestDB(t)
+		defer testDB.Close()
+		service := NewItemServiceWithDB(testDB.DB)
+		service.retryAttempts = 0
+		failures := 1
+		reached := failStatements(t, testDB.DB, pgError("40001"), &failures)
+
+		_, err := service.CreateItem(&models.CreateItemRequest{Name: "Once", Stock: 5, Price: 1.5})
+		assert.Error(t, err)
+		assert.Equal(t, 1, *reached)
+	})
+}
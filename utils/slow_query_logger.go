@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm/logger"
+)
+
+// slowQueryLogger wraps a GORM logger and additionally reports queries
+// taking at least threshold through the Error logger
+type slowQueryLogger struct {
+	logger.Interface
+	threshold time.Duration
+}
+
+// NewSlowQueryLogger returns a GORM logger that logs through inner and
+// reports every query taking threshold or longer, with its SQL and duration
+func NewSlowQueryLogger(inner logger.Interface, threshold time.Duration) logger.Interface {
+	return &slowQueryLogger{Interface: inner, threshold: threshold}
+}
+
+func (l *slowQueryLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return &slowQueryLogger{Interface: l.Interface.LogMode(level), threshold: l.threshold}
+}
+
+func (l *slowQueryLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if elapsed := time.Since(begin); elapsed >= l.threshold {
+		sql, rows := fc()
+		Error.Printf("WARN slow query took %s (threshold %s, %d rows): %s", elapsed, l.threshold, rows, sql)
+	}
+
+	l.Interface.Trace(ctx, begin, fc, err)
+}
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"bytes"
+	"log"
+	"testing"
+	"time"
+
+	"inventory-api/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// captureErrorLog redirects the Error logger into a buffer for the test
+func captureErrorLog(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	previous := Error
+	Error = log.New(&buf, "", 0)
+	t.Cleanup(func() { Error = previous })
+	return &buf
+}
+
+func TestSlowQueryLogger(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold time.Duration
+		expectLog bool
+	}{
+		{name: "zero threshold logs every query", threshold: 0, expectLog: true},
+		{name: "fast queries are not logged", threshold: time.Hour, expectLog: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testDB := NewTestDB(t)
+			defer testDB.Close()
+			testDB.CreateTestItem(t, "Laptop", 5, 999.99)
+
+			buf := captureErrorLog(t)
+			db := testDB.DB.Session(&gorm.Session{
+				Logger: NewSlowQueryLogger(logger.Discard, tt.threshold),
+			})
+
+			var items []models.Item
+			require.NoError(t, db.Where("stock > ?", 1).Find(&items).Error)
+			require.Len(t, items, 1)
+
+			output := buf.String()
+			if !tt.expectLog {
+				assert.Empty(t, output)
+				return
+			}
+
+			assert.Contains(t, output, "WARN slow query took")
+			assert.Contains(t, output, "1 rows")
+			assert.Contains(t, output, "SELECT * FROM `items` WHERE stock > 1")
+		})
+	}
+}
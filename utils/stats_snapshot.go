@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"context"
+	"time"
+
+	"inventory-api/models"
+)
+
+// statsSnapshot is the item stats as computed at one point in time
+type statsSnapshot struct {
+	values     map[string]interface{}
+	computedAt time.Time
+}
+
+// response returns the snapshot as reported to clients, with the time it
+// was computed. The values are copied, so callers may change the map.
+func (s *statsSnapshot) response() map[string]interface{} {
+	stats := make(map[string]interface{}, len(s.values)+1)
+	for key, value := range s.values {
+		stats[key] = value
+	}
+	stats["computed_at"] = s.computedAt.In(models.DisplayLocation())
+	return stats
+}
+
+// GetItemStats returns the stats of the items matching filters, or of the
+// active items when filters is nil or narrows nothing. Those global stats
+// are served from the last snapshot when a stats refresh interval is
+// configured, computing the first one on demand; otherwise, and for
+// filtered stats, they are computed on every call.
+func (s *ItemService) GetItemStats(filters *models.FilterRequest) (map[string]interface{}, error) {
+	if filters != nil && (!filters.IsEmpty() || filters.IncludeInactive) {
+		if err := s.CheckFilters(filters); err != nil {
+			return nil, err
+		}
+		computedAt := time.Now().UTC()
+		values, err := s.computeItemStats(filters)
+		if err != nil {
+			return nil, err
+		}
+		return (&statsSnapshot{values: values, computedAt: computedAt}).response(), nil
+	}
+
+	if s.statsRefreshInterval > 0 {
+		s.statsMu.RLock()
+		snapshot := s.stats
+		s.statsMu.RUnlock()
+		if snapshot != nil {
+			return snapshot.response(), nil
+		}
+	}
+
+	return s.RefreshStats()
+}
+
+// RefreshStats recomputes the stats snapshot and returns it
+func (s *ItemService) RefreshStats() (map[string]interface{}, error) {
+	computedAt := time.Now().UTC()
+	values, err := s.computeItemStats(&models.FilterRequest{})
+	if err != nil {
+		return nil, err
+	}
+	valueByCurrency, _ := values["total_value_by_currency"].(map[string]float64)
+	if alert := s.checkTotalValue(valueByCurrency, computedAt); alert != nil {
+		values["total_value_alert"] = alert
+	}
+	snapshot := &statsSnapshot{values: values, computedAt: computedAt}
+
+	// A slower refresh that started earlier must not replace a newer snapshot
+	s.statsMu.Lock()
+	if s.stats == nil || !s.stats.computedAt.After(computedAt) {
+		s.stats = snapshot
+	}
+	s.statsMu.Unlock()
+
+	return snapshot.response(), nil
+}
+
+// StartStatsRefreshJob recomputes the stats snapshot every interval until
+// ctx is cancelled. The returned channel is closed once the job has stopped.
+func StartStatsRefreshJob(ctx context.Context, service *ItemService, interval time.Duration) <-chan struct{} {
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := service.RefreshStats(); err != nil {
+					Error.Printf("Stats refresh failed: %v", err)
+				}
+			}
+		}
+	}()
+
+	return done
+}
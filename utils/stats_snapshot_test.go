@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemService_GetItemStats_Snapshot(t *testing.T) {
+	t.Run("recomputed on every read without a refresh interval", func(t *testing.T) {
+		testDB := NewTestDB(t)
+		defer testDB.Close()
+		service := NewItemServiceWithDB(testDB.DB)
+
+		stats, err := service.GetItemStats(nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), stats["total_items"])
+
+		testDB.CreateTestItem(t, "Item", 1, 1.0)
+		stats, err = service.GetItemStats(nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), stats["total_items"])
+	})
+
+	t.Run("served from the snapshot until refreshed", func(t *testing.T) {
+		testDB := NewTestDB(t)
+		defer testDB.Close()
+		service := NewItemServiceWithConfig(testDB.DB, &Config{Cache: CacheConfig{StatsRefreshInterval: time.Hour}})
+
+		stats, err := service.GetItemStats(nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), stats["total_items"])
+		computedAt := stats["computed_at"]
+
+		// Callers get their own copy of the snapshot
+		stats["total_items"] = int64(99)
+
+		testDB.CreateTestItem(t, "Item", 1, 1.0)
+		stats, err = service.GetItemStats(nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), stats["total_items"])
+		assert.Equal(t, computedAt, stats["computed_at"])
+
+		refreshed, err := service.RefreshStats()
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), refreshed["total_items"])
+
+		stats, err = service.GetItemStats(nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), stats["total_items"])
+	})
+}
+
+func TestStartStatsRefreshJob(t *testing.T) {
+	testDB := NewTestDB(t)
+	defer testDB.Close()
+
+	// The job and the test share one in-memory database, which is per connection
+	sqlDB, err := testDB.DB.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	service := NewItemServiceWithConfig(testDB.DB, &Config{Cache: CacheConfig{StatsRefreshInterval: time.Hour}})
+	_, err = service.GetItemStats(nil)
+	require.NoError(t, err)
+	testDB.CreateTestItem(t, "Item", 1, 1.0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := StartStatsRefreshJob(ctx, service, 10*time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		stats, err := service.GetItemStats(nil)
+		return err == nil && stats["total_items"] == int64(1)
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("stats refresh job did not stop after cancellation")
+	}
+}
@@ -24,7 +24,7 @@ func NewTestDB(t *testing.T) *TestDB {
 	}
 
 	// Auto-migrate the schema
-	if err := db.AutoMigrate(&models.Item{}); err != nil {
+	if err := db.AutoMigrate(&models.Category{}, &models.Item{}, &models.StockMovement{}, &models.ItemLock{}, &models.ItemTag{}); err != nil {
 		t.Fatalf("Failed to migrate test database: %v", err)
 	}
 
@@ -70,6 +70,11 @@ func IntPtr(i int) *int {
 	return &i
 }
 
+// BoolPtr returns a pointer to a bool
+func BoolPtr(b bool) *bool {
+	return &b
+}
+
 // Float64Ptr returns a pointer to a float64
 func Float64Ptr(f float64) *float64 {
 	return &f
@@ -0,0 +1,160 @@
+package utils
+
+import (
+	"bytes"
+	"net/http"
+
+	"inventory-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// txContextKey is the gin context key the request's transaction is stored under
+const txContextKey = "db_tx"
+
+// requestTx is the transaction a request runs in, with what to do once it commits
+type requestTx struct {
+	tx          *gorm.DB
+	afterCommit []func()
+}
+
+// txWriter holds back the response of a handler running in a transaction
+// until the transaction has ended, so a failed commit can still be reported
+type txWriter struct {
+	gin.ResponseWriter
+	status  int
+	written bool
+	body    bytes.Buffer
+}
+
+func (w *txWriter) WriteHeader(code int) {
+	if code > 0 {
+		w.status = code
+	}
+}
+
+func (w *txWriter) WriteHeaderNow() {
+	w.written = true
+}
+
+func (w *txWriter) Write(data []byte) (int, error) {
+	w.written = true
+	return w.body.Write(data)
+}
+
+func (w *txWriter) WriteString(s string) (int, error) {
+	w.written = true
+	return w.body.WriteString(s)
+}
+
+func (w *txWriter) Status() int {
+	return w.status
+}
+
+func (w *txWriter) Size() int {
+	if !w.written {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *txWriter) Written() bool {
+	return w.written
+}
+
+// Flush is a no-op, as nothing can be sent before the transaction ends
+func (w *txWriter) Flush() {}
+
+// send writes the held back response
+func (w *txWriter) send() {
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.written {
+		_, _ = w.ResponseWriter.Write(w.body.Bytes())
+	}
+}
+
+// TransactionMiddleware runs the rest of the request in one transaction on
+// db, which handlers use through RequestTx or ItemService.WithRequestTx. It
+// commits when the handler responds with a 2xx status and rolls back on any
+// other status or a panic. The response is held back until then, so a failed
+// commit answers 500 instead of the success the handler wrote; streaming
+// handlers should not run behind it.
+func TransactionMiddleware(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tx := db.WithContext(c.Request.Context()).Begin()
+		if tx.Error != nil {
+			Error.Printf("Failed to begin transaction: %v", tx.Error)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   "Failed to begin transaction",
+				Message: tx.Error.Error(),
+				Code:    http.StatusInternalServerError,
+			})
+			return
+		}
+
+		current := &requestTx{tx: tx}
+		c.Set(txContextKey, current)
+		writer := &txWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = writer
+
+		// A panic skips the rest, leaving RecoveryMiddleware to answer on the
+		// real writer once the partial writes are undone
+		done := false
+		defer func() {
+			c.Writer = writer.ResponseWriter
+			if !done {
+				tx.Rollback()
+			}
+		}()
+
+		c.Next()
+
+		done = true
+		c.Writer = writer.ResponseWriter
+		if writer.status < http.StatusOK || writer.status >= http.StatusMultipleChoices {
+			if err := tx.Rollback().Error; err != nil {
+				Error.Printf("Failed to roll back transaction: %v", err)
+			}
+			writer.send()
+			return
+		}
+
+		if err := tx.Commit().Error; err != nil {
+			Error.Printf("Failed to commit transaction: %v", err)
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   "Failed to commit transaction",
+				Message: err.Error(),
+				Code:    http.StatusInternalServerError,
+			})
+			return
+		}
+
+		for _, fn := range current.afterCommit {
+			fn()
+		}
+		writer.send()
+	}
+}
+
+// RequestTx returns the transaction TransactionMiddleware opened for the
+// request, if it runs in one
+func RequestTx(c *gin.Context) (*gorm.DB, bool) {
+	current, ok := c.Get(txContextKey)
+	if !ok {
+		return nil, false
+	}
+	return current.(*requestTx).tx, true
+}
+
+// AfterCommit runs fn once the request's transaction has committed, and not
+// at all if it rolls back. Outside a transaction fn runs straight away.
+func AfterCommit(c *gin.Context, fn func()) {
+	current, ok := c.Get(txContextKey)
+	if !ok {
+		fn()
+		return
+	}
+	tx := current.(*requestTx)
+	tx.afterCommit = append(tx.afterCommit, fn)
+}
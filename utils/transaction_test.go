@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"inventory-api/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionMiddleware(t *testing.T) {
+	testDB := NewTestDB(t)
+	defer testDB.Close()
+
+	service := NewItemServiceWithDB(testDB.DB)
+	defer service.Close()
+
+	router := SetupTestRouter()
+	router.Use(RecoveryMiddleware())
+	tx := TransactionMiddleware(testDB.DB)
+
+	// createTwo writes two items, then answers with status
+	createTwo := func(status int) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			scoped := service.WithRequestTx(c)
+			for _, name := range []string{"Laptop", "Mouse"} {
+				_, err := scoped.CreateItem(&models.CreateItemRequest{Name: name, Stock: 1, Price: 10})
+				require.NoError(t, err)
+			}
+			c.JSON(status, gin.H{"status": status})
+		}
+	}
+	router.POST("/created", tx, createTwo(http.StatusCreated))
+	router.POST("/invalid", tx, createTwo(http.StatusUnprocessableEntity))
+	router.POST("/failed", tx, createTwo(http.StatusInternalServerError))
+	router.POST("/panic", tx, func(c *gin.Context) {
+		_, err := service.WithRequestTx(c).CreateItem(&models.CreateItemRequest{Name: "Keyboard", Stock: 1, Price: 10})
+		require.NoError(t, err)
+		panic("handler failed after writing")
+	})
+
+	count := func() int64 {
+		var n int64
+		require.NoError(t, testDB.DB.Model(&models.Item{}).Count(&n).Error)
+		return n
+	}
+	post := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+		return w
+	}
+
+	t.Run("handler errors roll back partial writes", func(t *testing.T) {
+		for path, status := range map[string]int{"/invalid": http.StatusUnprocessableEntity, "/failed": http.StatusInternalServerError} {
+			w := post(path)
+			assert.Equal(t, status, w.Code, path)
+			assert.JSONEq(t, `{"status":`+strconv.Itoa(status)+`}`, w.Body.String(), path)
+			assert.Zero(t, count(), path)
+		}
+	})
+
+	t.Run("panics roll back partial writes", func(t *testing.T) {
+		w := post("/panic")
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "Internal server error")
+		assert.Zero(t, count())
+	})
+
+	t.Run("success commits", func(t *testing.T) {
+		w := post("/created")
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.JSONEq(t, `{"status":201}`, w.Body.String())
+		assert.Equal(t, int64(2), count())
+	})
+}
+
+func TestItemService_WithRequestTx(t *testing.T) {
+	testDB := NewTestDB(t)
+	defer testDB.Close()
+
+	service := NewItemServiceWithDB(testDB.DB)
+	defer service.Close()
+
+	item := testDB.CreateTestItem(t, "Laptop", 5, 999.99)
+	id := item.ID.String()
+	_, err := service.GetItem(id)
+	require.NoError(t, err)
+	service.cache.Wait()
+
+	router := SetupTestRouter()
+	router.POST("/restock/:stock", TransactionMiddleware(testDB.DB), func(c *gin.Context) {
+		stock, _ := strconv.Atoi(c.Param("stock"))
+		scoped := service.WithRequestTx(c)
+		updated, err := scoped.UpdateItem(id, &models.UpdateItemRequest{Stock: &stock})
+		require.NoError(t, err)
+
+		// Reads in the transaction see its writes but never cache them
+		read, err := scoped.GetItem(id)
+		require.NoError(t, err)
+		assert.Equal(t, stock, read.Stock)
+
+		if c.Query("fail") == "true" {
+			c.JSON(http.StatusConflict, gin.H{"error": "conflict"})
+			return
+		}
+		c.JSON(http.StatusOK, updated)
+	})
+	restock := func(path string) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusConflict, restock("/restock/9?fail=true"))
+	current, err := service.GetItem(id)
+	require.NoError(t, err)
+	assert.Equal(t, 5, current.Stock, "a rolled back write is neither stored nor cached")
+
+	assert.Equal(t, http.StatusOK, restock("/restock/7"))
+	current, err = service.GetItem(id)
+	require.NoError(t, err)
+	assert.Equal(t, 7, current.Stock, "the cache is cleared once the transaction commits")
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	assert.Same(t, service, service.WithRequestTx(c), "outside a transaction the service is used as is")
+}
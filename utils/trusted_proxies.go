@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetTrustedProxies makes router trust the X-Forwarded-For and X-Real-IP
+// headers only from the comma-separated IPs and CIDRs in proxies, so
+// ClientIP reports the real client behind them. An empty list trusts no
+// proxy and ClientIP is the address of the direct peer. If proxies cannot
+// be parsed, no proxy is trusted and the error is returned.
+func SetTrustedProxies(router *gin.Engine, proxies string) error {
+	var trusted []string
+	for _, proxy := range strings.Split(proxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			trusted = append(trusted, proxy)
+		}
+	}
+
+	if err := router.SetTrustedProxies(trusted); err != nil {
+		_ = router.SetTrustedProxies(nil)
+		return err
+	}
+	return nil
+}
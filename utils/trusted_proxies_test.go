@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetTrustedProxies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	clientIP := func(t *testing.T, proxies, remoteAddr, forwardedFor string) string {
+		router := gin.New()
+		require.NoError(t, SetTrustedProxies(router, proxies))
+		router.GET("/ip", func(c *gin.Context) {
+			c.String(http.StatusOK, c.ClientIP())
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+
+	t.Run("forwarded by a trusted proxy", func(t *testing.T) {
+		assert.Equal(t, "203.0.113.7", clientIP(t, "10.0.0.0/8, 192.168.1.10", "10.1.2.3:54321", "203.0.113.7"))
+	})
+
+	t.Run("chain of trusted proxies", func(t *testing.T) {
+		assert.Equal(t, "203.0.113.7", clientIP(t, "10.0.0.0/8", "10.1.2.3:54321", "203.0.113.7, 10.4.5.6"))
+	})
+
+	t.Run("spoofed by an untrusted peer", func(t *testing.T) {
+		assert.Equal(t, "198.51.100.20", clientIP(t, "10.0.0.0/8", "198.51.100.20:54321", "203.0.113.7"))
+	})
+
+	t.Run("no trusted proxies", func(t *testing.T) {
+		assert.Equal(t, "10.1.2.3", clientIP(t, "", "10.1.2.3:54321", "203.0.113.7"))
+	})
+
+	t.Run("invalid proxy", func(t *testing.T) {
+		router := gin.New()
+		assert.Error(t, SetTrustedProxies(router, "10.0.0.0/8,not-an-ip"))
+	})
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"inventory-api/models"
+)
+
+// alertWebhookTimeout bounds how long delivering an alert may take
+var alertWebhookTimeout = 5 * time.Second
+
+// valueAlertEvent is the body posted to the alert webhook
+type valueAlertEvent struct {
+	Event string `json:"event"`
+	models.ValueAlert
+	ComputedAt time.Time `json:"computed_at"`
+}
+
+// totalValue converts the value of each currency into
+// models.DefaultCurrency and sums it. Currencies without an exchange rate
+// cannot be converted and are left out.
+func (s *ItemService) totalValue(valueByCurrency map[string]float64) float64 {
+	total := 0.0
+	for currency, value := range valueByCurrency {
+		rate, ok := s.fxRates[currency]
+		if !ok {
+			Info.Printf("No exchange rate for %s, leaving its value out of the total value check", currency)
+			continue
+		}
+		total += value / rate
+	}
+	return math.Round(total*100) / 100
+}
+
+// checkTotalValue compares the total inventory value with MAX_TOTAL_VALUE
+// and alerts once each time it crosses above it. It returns nil when no
+// maximum is configured.
+func (s *ItemService) checkTotalValue(valueByCurrency map[string]float64, computedAt time.Time) *models.ValueAlert {
+	if s.maxTotalValue <= 0 {
+		return nil
+	}
+
+	alert := &models.ValueAlert{
+		Current:   s.totalValue(valueByCurrency),
+		Threshold: s.maxTotalValue,
+		Currency:  models.DefaultCurrency,
+	}
+	alert.Exceeded = alert.Current > alert.Threshold
+
+	// Only the refresh that sees the value cross the maximum alerts
+	s.alertMu.Lock()
+	crossed := alert.Exceeded && !s.valueExceeded
+	s.valueExceeded = alert.Exceeded
+	s.alertMu.Unlock()
+
+	if crossed {
+		Error.Printf("Total inventory value %.2f %s exceeds the maximum of %.2f", alert.Current, alert.Currency, alert.Threshold)
+		if s.alertWebhookURL != "" {
+			if err := s.sendValueAlert(alert, computedAt); err != nil {
+				Error.Printf("Failed to deliver total value alert: %v", err)
+			}
+		}
+	}
+	return alert
+}
+
+// sendValueAlert posts alert to the alert webhook as JSON
+func (s *ItemService) sendValueAlert(alert *models.ValueAlert, computedAt time.Time) error {
+	body, err := json.Marshal(valueAlertEvent{
+		Event:      "total_value_exceeded",
+		ValueAlert: *alert,
+		ComputedAt: computedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: alertWebhookTimeout}
+	resp, err := client.Post(s.alertWebhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"inventory-api/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemService_TotalValueAlert(t *testing.T) {
+	testDB := NewTestDB(t)
+	defer testDB.Close()
+
+	events := make(chan valueAlertEvent, 10)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event valueAlertEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err == nil {
+			events <- event
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer webhook.Close()
+
+	cfg := &Config{
+		Alerts:   AlertConfig{MaxTotalValue: 1000, WebhookURL: webhook.URL},
+		Currency: CurrencyConfig{FXRates: "EUR=0.5"},
+	}
+	service := NewItemServiceWithConfig(testDB.DB, cfg)
+	defer service.Close()
+
+	alertOf := func(stats map[string]interface{}) *models.ValueAlert {
+		alert, ok := stats["total_value_alert"].(*models.ValueAlert)
+		require.True(t, ok, "stats report the total value alert")
+		return alert
+	}
+
+	item := testDB.CreateTestItem(t, "Widget", 10, 50.00)
+	stats, err := service.RefreshStats()
+	require.NoError(t, err)
+	assert.Equal(t, &models.ValueAlert{Current: 500, Threshold: 1000, Currency: models.DefaultCurrency}, alertOf(stats))
+	assert.Empty(t, events, "no alert below the maximum")
+
+	t.Run("fires when the total crosses the maximum", func(t *testing.T) {
+		// 100 EUR at 0.5 EUR per USD is worth 200 USD
+		euro := testDB.CreateTestItem(t, "Euro Widget", 4, 100.00)
+		require.NoError(t, testDB.DB.Model(euro).Update("currency", "EUR").Error)
+		require.NoError(t, testDB.DB.Model(item).Update("stock", 20).Error)
+
+		stats, err := service.RefreshStats()
+		require.NoError(t, err)
+		alert := alertOf(stats)
+		assert.True(t, alert.Exceeded)
+		assert.Equal(t, 1800.0, alert.Current)
+
+		require.Len(t, events, 1)
+		event := <-events
+		assert.Equal(t, "total_value_exceeded", event.Event)
+		assert.Equal(t, 1800.0, event.Current)
+		assert.Equal(t, 1000.0, event.Threshold)
+		assert.False(t, event.ComputedAt.IsZero())
+	})
+
+	t.Run("fires once per crossing", func(t *testing.T) {
+		_, err := service.RefreshStats()
+		require.NoError(t, err)
+		assert.Empty(t, events, "still above the maximum, so no new alert")
+
+		require.NoError(t, testDB.DB.Model(item).Update("stock", 1).Error)
+		stats, err := service.RefreshStats()
+		require.NoError(t, err)
+		assert.False(t, alertOf(stats).Exceeded)
+		assert.Empty(t, events)
+
+		require.NoError(t, testDB.DB.Model(item).Update("stock", 30).Error)
+		_, err = service.RefreshStats()
+		require.NoError(t, err)
+		assert.Len(t, events, 1, "crossing again alerts again")
+	})
+
+	t.Run("disabled without a maximum", func(t *testing.T) {
+		service := NewItemServiceWithConfig(testDB.DB, &Config{})
+		defer service.Close()
+
+		stats, err := service.RefreshStats()
+		require.NoError(t, err)
+		assert.NotContains(t, stats, "total_value_alert")
+	})
+}
@@ -0,0 +1,26 @@
+package utils
+
+// Build information, injected at build time with
+//
+//	go build -ldflags "-X inventory-api/utils.version=1.2.0 -X inventory-api/utils.commit=$(git rev-parse --short HEAD) -X inventory-api/utils.buildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	version   = "dev"
+	commit    = "unknown"
+	buildTime = "unknown"
+)
+
+// BuildInfo identifies the build of the running binary
+type BuildInfo struct {
+	Version   string `json:"version" example:"1.2.0"`
+	Commit    string `json:"commit" example:"1ab6df7"`
+	BuildTime string `json:"build_time" example:"2024-01-01T00:00:00Z"`
+}
+
+// GetBuildInfo returns the version, commit and build time the binary was built with
+func GetBuildInfo() BuildInfo {
+	return BuildInfo{
+		Version:   version,
+		Commit:    commit,
+		BuildTime: buildTime,
+	}
+}
@@ -0,0 +1,17 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBuildInfo(t *testing.T) {
+	assert.Equal(t, BuildInfo{Version: "dev", Commit: "unknown", BuildTime: "unknown"}, GetBuildInfo())
+
+	previous := []string{version, commit, buildTime}
+	version, commit, buildTime = "1.2.0", "1ab6df7", "2024-01-01T00:00:00Z"
+	t.Cleanup(func() { version, commit, buildTime = previous[0], previous[1], previous[2] })
+
+	assert.Equal(t, BuildInfo{Version: "1.2.0", Commit: "1ab6df7", BuildTime: "2024-01-01T00:00:00Z"}, GetBuildInfo())
+}